@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditActionCreate  = "create"
+	auditActionUpdate  = "update"
+	auditActionDelete  = "delete"
+	auditActionRestore = "restore"
+	auditActionPurge   = "purge"
+
+	auditEntityUser             = "user"
+	auditEntityTraining         = "training"
+	auditEntityTrainingFeedback = "training_feedback"
+)
+
+// auditTx executes the changes made by fn and records the audit entries it returns in the same transaction, on
+// behalf of the actor of the request. fn must make its changes through the store it is given.
+func (server *Server) auditTx(ctx *gin.Context, fn func(store db.Store) ([]db.AuditEntry, error)) error {
+	arg := db.CreateAuditLogParams{
+		OrganizationID: tenantID(ctx),
+		RequestID:      ctx.GetString(requestIDKey),
+	}
+	if user, ok := currentActor(ctx); ok {
+		arg.ActorID = nullActorID(user)
+	}
+
+	return server.store.AuditTx(ctx, arg, fn)
+}
+
+// auditEntry describes a change to an entity, before and after are its states, nil when it doesn't exist
+func auditEntry(action string, entityType string, entityID int64, before, after interface{}) db.AuditEntry {
+	return db.AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+	}
+}
+
+type listAuditLogRequest struct {
+	ActorID    int64  `form:"actor_id" binding:"min=0"`
+	Action     string `form:"action" binding:"omitempty,oneof=create update delete restore purge export erase"`
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=user training training_feedback training_series group group_member group_training organization zone_model test_result race wellness injury availability blackout equipment sport exercise exercise_log api_token"`
+	EntityID   int64  `form:"entity_id" binding:"min=0"`
+	StartDate  string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate    string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	PageID     int32  `form:"page_id" binding:"required,min=1"`
+	PageSize   int32  `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+func (server *Server) listAuditLogs(ctx *gin.Context) {
+	var req listAuditLogRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.ListAuditLogsParams{
+		OrganizationID: tenantID(ctx),
+		ActorID:        req.ActorID,
+		Action:         req.Action,
+		EntityType:     req.EntityType,
+		EntityID:       req.EntityID,
+		StartTime:      time.Unix(0, 0).UTC(),
+		EndTime:        time.Now().UTC().AddDate(0, 0, 1),
+		RowLimit:       req.PageSize,
+		RowOffset:      (req.PageID - 1) * req.PageSize,
+	}
+	// we can ignore the errors because the values were already validated
+	if req.StartDate != "" {
+		arg.StartTime, _ = time.Parse("2006-01-02", req.StartDate)
+	}
+	if req.EndDate != "" {
+		end, _ := time.Parse("2006-01-02", req.EndDate)
+		arg.EndTime = end.AddDate(0, 0, 1)
+	}
+
+	logs, err := server.store.ListAuditLogs(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, logs)
+}
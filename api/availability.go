@@ -0,0 +1,475 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/rondondev/runapp/availability"
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditEntityAvailability = "availability"
+	auditEntityBlackout     = "blackout"
+)
+
+type availabilityRequest struct {
+	ID             int64 `uri:"id" binding:"required,min=1"`
+	AvailabilityID int64 `uri:"availability_id" binding:"required,min=1"`
+}
+
+type createAvailabilityRequest struct {
+	// Weekday is 0 for Sunday, minutes are counted from midnight and MaxDuration is in seconds
+	Weekday     *int32 `json:"weekday" binding:"required,min=0,max=6"`
+	StartMinute *int32 `json:"start_minute" binding:"required,min=0,max=1439"`
+	EndMinute   int32  `json:"end_minute" binding:"required,max=1440"`
+	MaxDuration *int32 `json:"max_duration" binding:"omitempty,min=1"`
+}
+
+func (server *Server) listAvailability(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	windows, err := server.store.ListAvailabilityByUser(ctx, db.ListAvailabilityByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, windows)
+}
+
+func (server *Server) createAvailability(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createAvailabilityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if req.EndMinute <= *req.StartMinute {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("availability.end_minute")))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	arg := db.CreateAvailabilityParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		Weekday:        *req.Weekday,
+		StartMinute:    *req.StartMinute,
+		EndMinute:      req.EndMinute,
+	}
+	if req.MaxDuration != nil {
+		arg.MaxDuration.SetValid(*req.MaxDuration)
+	}
+
+	var window db.Availability
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		window, err = store.CreateAvailability(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityAvailability, window.ID, nil, window)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, window)
+}
+
+func (server *Server) deleteAvailability(ctx *gin.Context) {
+	var req availabilityRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	window, err := server.store.GetAvailability(ctx, db.GetAvailabilityParams{
+		OrganizationID: tenantID(ctx),
+		ID:             req.AvailabilityID,
+	})
+	if err == nil && window.UserID != req.ID {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteAvailability(ctx, db.DeleteAvailabilityParams{
+			OrganizationID: window.OrganizationID,
+			ID:             window.ID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityAvailability, window.ID, window, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type blackoutRequest struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	BlackoutID int64 `uri:"blackout_id" binding:"required,min=1"`
+}
+
+type createBlackoutRequest struct {
+	StartDate string  `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string  `json:"end_date" binding:"required,datetime=2006-01-02"`
+	Reason    *string `json:"reason"`
+}
+
+func (server *Server) listBlackouts(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	blackouts, err := server.store.ListBlackoutsByUser(ctx, db.ListBlackoutsByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blackouts)
+}
+
+func (server *Server) createBlackout(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createBlackoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// we can ignore the errors because the values were already validated
+	start, _ := time.Parse("2006-01-02", req.StartDate)
+	end, _ := time.Parse("2006-01-02", req.EndDate)
+	if end.Before(start) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.end_before_start")))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	arg := db.CreateBlackoutParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		StartDate:      start,
+		EndDate:        end,
+	}
+	if req.Reason != nil {
+		arg.Reason.SetValid(*req.Reason)
+	}
+
+	var blackout db.Blackout
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		blackout, err = store.CreateBlackout(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityBlackout, blackout.ID, nil, blackout)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blackout)
+}
+
+func (server *Server) deleteBlackout(ctx *gin.Context) {
+	var req blackoutRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	blackout, err := server.store.GetBlackout(ctx, db.GetBlackoutParams{
+		OrganizationID: tenantID(ctx),
+		ID:             req.BlackoutID,
+	})
+	if err == nil && blackout.UserID != req.ID {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteBlackout(ctx, db.DeleteBlackoutParams{
+			OrganizationID: blackout.OrganizationID,
+			ID:             blackout.ID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityBlackout, blackout.ID, blackout, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type listConflictsRequest struct {
+	StartDate string `form:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `form:"end_date" binding:"required,datetime=2006-01-02"`
+}
+
+// trainingConflicts is a planned training that doesn't fit the availability of the athlete
+type trainingConflicts struct {
+	Training  db.Training             `json:"training"`
+	Conflicts []availability.Conflict `json:"conflicts"`
+}
+
+// listConflicts lists the trainings still to be done in a period that land on a day the athlete is
+// unavailable
+func (server *Server) listConflicts(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req listConflictsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	start, _ := time.Parse("2006-01-02", req.StartDate)
+	end, _ := time.Parse("2006-01-02", req.EndDate)
+	if end.Before(start) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.end_before_start")))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	trainings, err := server.store.ListTrainingsByUserInPeriod(ctx, db.ListTrainingsByUserInPeriodParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		Date:           start,
+		Date_2:         end,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	calendar, err := server.calendar(ctx, user.OrganizationID, user.ID, start, end)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := []trainingConflicts{}
+	for _, t := range trainings {
+		if t.Status != db.TrainingStatusNew && t.Status != db.TrainingStatusNotified {
+			continue
+		}
+		if conflicts := calendar.Check(t.Date, int(t.PlannedDuration.Int32)); len(conflicts) > 0 {
+			rsp = append(rsp, trainingConflicts{Training: t, Conflicts: conflicts})
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// calendar loads the weekly windows of a user and their blackouts overlapping a period
+func (server *Server) calendar(ctx *gin.Context, organizationID, userID int64, start, end time.Time) (availability.Calendar, error) {
+	var calendar availability.Calendar
+
+	windows, err := server.store.ListAvailabilityByUser(ctx, db.ListAvailabilityByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return calendar, err
+	}
+	for _, w := range windows {
+		calendar.Windows = append(calendar.Windows, availability.Window{
+			Weekday:     time.Weekday(w.Weekday),
+			StartMinute: int(w.StartMinute),
+			EndMinute:   int(w.EndMinute),
+			MaxDuration: int(w.MaxDuration.Int32),
+		})
+	}
+
+	blackouts, err := server.store.ListBlackoutsByUserInPeriod(ctx, db.ListBlackoutsByUserInPeriodParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		StartDate:      start,
+		EndDate:        end,
+	})
+	if err != nil {
+		return calendar, err
+	}
+	for _, b := range blackouts {
+		calendar.Blackouts = append(calendar.Blackouts, availability.Blackout{
+			StartDate: b.StartDate,
+			EndDate:   b.EndDate,
+			Reason:    b.Reason.String,
+		})
+	}
+
+	return calendar, nil
+}
+
+// trainingWarningsResponse is a saved training along with the availability conflicts it has
+type trainingWarningsResponse struct {
+	db.Training
+	Legs     []db.TrainingLeg        `json:"legs,omitempty"`
+	Warnings []availability.Conflict `json:"warnings,omitempty"`
+}
+
+// checkAvailability returns the conflicts of a training planned for a user. When the organization
+// enforces the availability it writes the error response and returns false if there is any.
+func (server *Server) checkAvailability(ctx *gin.Context, userID int64, date time.Time, duration int32) ([]availability.Conflict, bool) {
+	calendar, err := server.calendar(ctx, tenantID(ctx), userID, date, date)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return nil, false
+	}
+
+	conflicts := calendar.Check(date, int(duration))
+	if len(conflicts) > 0 && parseOrganizationSettings(currentOrganization(ctx)).StrictAvailability {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     "the training doesn't fit the availability of the athlete",
+			"conflicts": conflicts,
+		})
+		return nil, false
+	}
+
+	return conflicts, true
+}
+
+// plannedTraining is a training a request creates or moves, with the date it lands on
+type plannedTraining struct {
+	UserID   int64
+	Date     time.Time
+	Duration int32
+}
+
+// availabilityWarning is one of the trainings a request creates or moves along with its conflicts
+type availabilityWarning struct {
+	UserID    int64                   `json:"user_id"`
+	Date      time.Time               `json:"date"`
+	Conflicts []availability.Conflict `json:"conflicts"`
+}
+
+// trainingsConflicts returns the conflicts of each of the trainings, loading the calendar of every user once
+func (server *Server) trainingsConflicts(ctx *gin.Context, trainings []plannedTraining) ([][]availability.Conflict, error) {
+	type period struct{ start, end time.Time }
+	periods := map[int64]period{}
+	for _, t := range trainings {
+		p, ok := periods[t.UserID]
+		if !ok || t.Date.Before(p.start) {
+			p.start = t.Date
+		}
+		if !ok || t.Date.After(p.end) {
+			p.end = t.Date
+		}
+		periods[t.UserID] = p
+	}
+
+	calendars := map[int64]availability.Calendar{}
+	for userID, p := range periods {
+		calendar, err := server.calendar(ctx, tenantID(ctx), userID, p.start, p.end)
+		if err != nil {
+			return nil, err
+		}
+		calendars[userID] = calendar
+	}
+
+	conflicts := make([][]availability.Conflict, len(trainings))
+	for i, t := range trainings {
+		conflicts[i] = calendars[t.UserID].Check(t.Date, int(t.Duration))
+	}
+
+	return conflicts, nil
+}
+
+// checkTrainingsAvailability is checkAvailability for the trainings a request creates or moves at once.
+// When the organization enforces the availability nothing must be saved if any of them has a conflict.
+func (server *Server) checkTrainingsAvailability(ctx *gin.Context, trainings []plannedTraining) ([]availabilityWarning, bool) {
+	conflicts, err := server.trainingsConflicts(ctx, trainings)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return nil, false
+	}
+
+	var warnings []availabilityWarning
+	for i, t := range trainings {
+		if len(conflicts[i]) > 0 {
+			warnings = append(warnings, availabilityWarning{UserID: t.UserID, Date: t.Date, Conflicts: conflicts[i]})
+		}
+	}
+
+	if len(warnings) > 0 && parseOrganizationSettings(currentOrganization(ctx)).StrictAvailability {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     "the trainings don't fit the availability of the athletes",
+			"conflicts": warnings,
+		})
+		return nil, false
+	}
+
+	return warnings, true
+}
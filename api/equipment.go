@@ -0,0 +1,586 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/rondondev/runapp/calc"
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityEquipment = "equipment"
+
+// Metrics an equipment threshold applies to
+const (
+	equipmentMetricDistance = "distance"
+	equipmentMetricDuration = "duration"
+)
+
+type equipmentRequest struct {
+	Type      db.EquipmentType `json:"type" binding:"required,oneof=shoes bike chain wetsuit other"`
+	Brand     string           `json:"brand" binding:"required"`
+	Model     string           `json:"model" binding:"required"`
+	StartDate string           `json:"start_date" binding:"required,datetime=2006-01-02"`
+	// ThresholdDistance is in meters and ThresholdDuration in seconds
+	ThresholdDistance *int32  `json:"threshold_distance" binding:"omitempty,min=1"`
+	ThresholdDuration *int32  `json:"threshold_duration" binding:"omitempty,min=1"`
+	RetiredOn         *string `json:"retired_on" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (r *equipmentRequest) toDB(organizationID, userID int64) (db.CreateEquipmentParams, error) {
+	startDate, err := time.Parse("2006-01-02", r.StartDate)
+	if err != nil {
+		return db.CreateEquipmentParams{}, err
+	}
+
+	arg := db.CreateEquipmentParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Type:           r.Type,
+		Brand:          r.Brand,
+		Model:          r.Model,
+		StartDate:      startDate,
+	}
+	if r.ThresholdDistance != nil {
+		arg.ThresholdDistance.SetValid(*r.ThresholdDistance)
+	}
+	if r.ThresholdDuration != nil {
+		arg.ThresholdDuration.SetValid(*r.ThresholdDuration)
+	}
+	if r.RetiredOn != nil {
+		retiredOn, err := time.Parse("2006-01-02", *r.RetiredOn)
+		if err != nil {
+			return db.CreateEquipmentParams{}, err
+		}
+		if retiredOn.Before(startDate) {
+			return db.CreateEquipmentParams{}, i18n.Errorf("equipment.retired_on")
+		}
+		arg.RetiredOn.SetValid(retiredOn)
+	}
+
+	return arg, nil
+}
+
+type createEquipmentRequest struct {
+	UserID int64 `json:"user_id" binding:"required,min=1"`
+	equipmentRequest
+}
+
+// equipmentResponse is an equipment along with its usage from the actual data of its trainings
+type equipmentResponse struct {
+	db.Equipment
+	Trainings int64 `json:"trainings"`
+	Distance  int64 `json:"distance"`
+	Duration  int64 `json:"duration"`
+}
+
+func (server *Server) listEquipmentByUser(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	equipment, err := server.store.ListEquipmentByUser(ctx, db.ListEquipmentByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp, err := server.withUsage(ctx, equipment)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) listEquipmentAlerts(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	alerts, err := server.store.ListEquipmentAlertsByUser(ctx, db.ListEquipmentAlertsByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	equipment, err := server.store.ListEquipmentByUser(ctx, db.ListEquipmentByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	names := make(map[int64]string, len(equipment))
+	for _, e := range equipment {
+		names[e.ID] = e.Brand + " " + e.Model
+	}
+
+	// the alerts are notifications to the athlete, so they fall back to their locale
+	lang := i18n.Negotiate(ctx.GetHeader(languageHeader), user.Locale)
+	rsp := make([]equipmentAlertResponse, len(alerts))
+	for i, a := range alerts {
+		rsp[i].EquipmentAlert = a
+		rsp[i].Message, err = alertMessage(lang, user.Units, names[a.EquipmentID], a)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// equipmentAlertResponse is an alert along with its notification message
+type equipmentAlertResponse struct {
+	db.EquipmentAlert
+	Message string `json:"message"`
+}
+
+// alertMessage renders the notification of an alert in the language and unit system of the athlete
+func alertMessage(lang string, units db.UnitSystem, equipment string, alert db.EquipmentAlert) (string, error) {
+	unit, divisor := "unit.hours", 3600.0
+	if alert.Metric == equipmentMetricDistance {
+		unit, divisor = "unit.km", 1000.0
+		if units == db.UnitSystemImperial {
+			unit, divisor = "unit.mi", calc.Mile
+		}
+	}
+
+	return i18n.Render(lang, "notification.equipment", map[string]interface{}{
+		"Equipment": equipment,
+		"Value":     calc.Round(float64(alert.Value)/divisor, 1),
+		"Threshold": calc.Round(float64(alert.Threshold)/divisor, 1),
+		"Unit":      i18n.T(lang, unit),
+	})
+}
+
+func (server *Server) getEquipment(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	equipment, ok := server.loadEquipment(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	rsp, err := server.withUsage(ctx, []db.Equipment{equipment})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp[0])
+}
+
+func (server *Server) createEquipment(ctx *gin.Context) {
+	var req createEquipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.loadUser(ctx, req.UserID); !ok {
+		return
+	}
+
+	arg, err := req.toDB(tenantID(ctx), req.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var equipment db.Equipment
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		equipment, err = store.CreateEquipment(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityEquipment, equipment.ID, nil, equipment)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, equipment)
+}
+
+func (server *Server) updateEquipment(ctx *gin.Context) {
+	var r idRequest
+	if err := ctx.ShouldBindUri(&r); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req equipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	equipment, ok := server.loadEquipment(ctx, r.ID)
+	if !ok {
+		return
+	}
+
+	arg, err := req.toDB(equipment.OrganizationID, equipment.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var updated db.Equipment
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateEquipment(ctx, db.UpdateEquipmentParams{
+			OrganizationID:    equipment.OrganizationID,
+			ID:                equipment.ID,
+			Type:              arg.Type,
+			Brand:             arg.Brand,
+			Model:             arg.Model,
+			StartDate:         arg.StartDate,
+			ThresholdDistance: arg.ThresholdDistance,
+			ThresholdDuration: arg.ThresholdDuration,
+			RetiredOn:         arg.RetiredOn,
+		})
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityEquipment, equipment.ID, equipment, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// A lowered threshold can be crossed already
+	server.checkEquipmentAlerts(ctx, updated.OrganizationID, []int64{updated.ID})
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (server *Server) deleteEquipment(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	equipment, ok := server.loadEquipment(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteEquipment(ctx, db.DeleteEquipmentParams{OrganizationID: equipment.OrganizationID, ID: equipment.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityEquipment, equipment.ID, equipment, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type trainingEquipmentRequest struct {
+	ID          int64 `uri:"id" binding:"required,min=1"`
+	EquipmentID int64 `uri:"equipment_id" binding:"required,min=1"`
+}
+
+type addTrainingEquipmentRequest struct {
+	EquipmentID int64 `json:"equipment_id" binding:"required,min=1"`
+}
+
+func (server *Server) listTrainingEquipment(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: req.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	equipment, err := server.store.ListTrainingEquipment(ctx, db.ListTrainingEquipmentParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, equipment)
+}
+
+// addTrainingEquipment attaches an equipment of the athlete to one of their completed trainings
+func (server *Server) addTrainingEquipment(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req addTrainingEquipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	training, equipment, ok := server.loadTrainingEquipment(ctx, u.ID, req.EquipmentID)
+	if !ok {
+		return
+	}
+
+	if training.Status != db.TrainingStatusDone && training.Status != db.TrainingStatusDoneFeedback {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("equipment.not_completed")))
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.AddTrainingEquipment(ctx, db.AddTrainingEquipmentParams{
+			TrainingID:  training.ID,
+			EquipmentID: equipment.ID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityTraining, training.ID, nil, gin.H{"equipment_id": equipment.ID})}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.checkEquipmentAlerts(ctx, equipment.OrganizationID, []int64{equipment.ID})
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (server *Server) removeTrainingEquipment(ctx *gin.Context) {
+	var req trainingEquipmentRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	training, equipment, ok := server.loadTrainingEquipment(ctx, req.ID, req.EquipmentID)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.RemoveTrainingEquipment(ctx, db.RemoveTrainingEquipmentParams{
+			TrainingID:  training.ID,
+			EquipmentID: equipment.ID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityTraining, training.ID, gin.H{"equipment_id": equipment.ID}, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// loadEquipment gets an equipment of the current organization, writing the error response if it doesn't exist
+func (server *Server) loadEquipment(ctx *gin.Context, id int64) (db.Equipment, bool) {
+	equipment, err := server.store.GetEquipment(ctx, db.GetEquipmentParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return equipment, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return equipment, false
+	}
+
+	return equipment, true
+}
+
+// loadTrainingEquipment gets a training and an equipment of the same athlete, writing the error response
+// if any of them doesn't exist
+func (server *Server) loadTrainingEquipment(ctx *gin.Context, trainingID, equipmentID int64) (db.Training, db.Equipment, bool) {
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: trainingID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return training, db.Equipment{}, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return training, db.Equipment{}, false
+	}
+
+	equipment, err := server.store.GetEquipment(ctx, db.GetEquipmentParams{OrganizationID: training.OrganizationID, ID: equipmentID})
+	if err == nil && equipment.UserID != training.UserID {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_field", "equipment_id")))
+			return training, equipment, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return training, equipment, false
+	}
+
+	return training, equipment, true
+}
+
+// withUsage adds the distance and time accumulated by each equipment
+func (server *Server) withUsage(ctx *gin.Context, equipment []db.Equipment) ([]equipmentResponse, error) {
+	rsp := make([]equipmentResponse, len(equipment))
+	if len(equipment) == 0 {
+		return rsp, nil
+	}
+
+	ids := make([]int64, len(equipment))
+	for i, e := range equipment {
+		ids[i] = e.ID
+	}
+	usage, err := server.store.ListEquipmentUsage(ctx, db.ListEquipmentUsageParams{
+		OrganizationID: equipment[0].OrganizationID,
+		EquipmentIds:   ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]db.ListEquipmentUsageRow, len(usage))
+	for _, u := range usage {
+		byID[u.EquipmentID] = u
+	}
+	for i, e := range equipment {
+		u := byID[e.ID]
+		rsp[i] = equipmentResponse{Equipment: e, Trainings: u.Trainings, Distance: u.Distance, Duration: u.Duration}
+	}
+
+	return rsp, nil
+}
+
+// checkEquipmentAlerts raises an alert for each threshold crossed by the usage of the equipment, once per
+// threshold. It runs once the change is saved, a failure is logged but does not fail the request.
+func (server *Server) checkEquipmentAlerts(ctx *gin.Context, organizationID int64, equipmentIDs []int64) {
+	if len(equipmentIDs) == 0 {
+		return
+	}
+
+	usage, err := server.store.ListEquipmentUsage(ctx, db.ListEquipmentUsageParams{
+		OrganizationID: organizationID,
+		EquipmentIds:   equipmentIDs,
+	})
+	if err != nil {
+		log.Printf("cannot check equipment alerts: %v", err)
+		return
+	}
+
+	for _, u := range usage {
+		equipment, err := server.store.GetEquipment(ctx, db.GetEquipmentParams{OrganizationID: organizationID, ID: u.EquipmentID})
+		if err != nil {
+			log.Printf("cannot check equipment alerts of %d: %v", u.EquipmentID, err)
+			continue
+		}
+		// Retired equipment isn't used anymore
+		if equipment.RetiredOn.Valid {
+			continue
+		}
+
+		alerts, err := server.store.ListEquipmentAlertsByUser(ctx, db.ListEquipmentAlertsByUserParams{
+			OrganizationID: organizationID,
+			UserID:         equipment.UserID,
+		})
+		if err != nil {
+			log.Printf("cannot check equipment alerts of %d: %v", equipment.ID, err)
+			continue
+		}
+
+		thresholds := map[string]null.Int32{
+			equipmentMetricDistance: equipment.ThresholdDistance,
+			equipmentMetricDuration: equipment.ThresholdDuration,
+		}
+		values := map[string]int64{
+			equipmentMetricDistance: u.Distance,
+			equipmentMetricDuration: u.Duration,
+		}
+		for _, a := range alerts {
+			if a.EquipmentID == equipment.ID && thresholds[a.Metric].Int32 == a.Threshold {
+				delete(thresholds, a.Metric)
+			}
+		}
+
+		for _, metric := range []string{equipmentMetricDistance, equipmentMetricDuration} {
+			threshold, ok := thresholds[metric]
+			if !ok || !threshold.Valid || values[metric] < int64(threshold.Int32) {
+				continue
+			}
+
+			alert, err := server.store.CreateEquipmentAlert(ctx, db.CreateEquipmentAlertParams{
+				OrganizationID: organizationID,
+				EquipmentID:    equipment.ID,
+				Metric:         metric,
+				Threshold:      threshold.Int32,
+				Value:          values[metric],
+			})
+			if err != nil {
+				log.Printf("cannot raise %s alert of equipment %d: %v", metric, equipment.ID, err)
+				continue
+			}
+			log.Printf("equipment %d crossed its %s threshold of %d with %d", equipment.ID, metric, alert.Threshold, alert.Value)
+		}
+	}
+}
+
+// checkTrainingEquipmentAlerts checks the alerts of the equipment attached to a training whose actual data changed
+func (server *Server) checkTrainingEquipmentAlerts(ctx *gin.Context, training db.Training) {
+	equipment, err := server.store.ListTrainingEquipment(ctx, db.ListTrainingEquipmentParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+	})
+	if err != nil {
+		log.Printf("cannot check equipment alerts of training %d: %v", training.ID, err)
+		return
+	}
+
+	ids := make([]int64, len(equipment))
+	for i, e := range equipment {
+		ids[i] = e.ID
+	}
+	server.checkEquipmentAlerts(ctx, training.OrganizationID, ids)
+}
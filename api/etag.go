@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+// etag is the entity tag of a version of a user, training or feedback
+func etag(version int32) string {
+	return strconv.Quote(strconv.Itoa(int(version)))
+}
+
+func setETag(ctx *gin.Context, version int32) {
+	ctx.Header(etagHeader, etag(version))
+}
+
+// checkIfMatch checks that the client edits the current version of an entity, writing 428 if the If-Match
+// header is missing or 412 if it doesn't match. The version is checked again by the update itself.
+func checkIfMatch(ctx *gin.Context, version int32) bool {
+	header := ctx.GetHeader(ifMatchHeader)
+	if header == "" {
+		ctx.JSON(http.StatusPreconditionRequired, errorResponse(ctx, i18n.Errorf("precondition.required")))
+		return false
+	}
+
+	current := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	preconditionFailed(ctx)
+	return false
+}
+
+// preconditionFailed writes the response of an update made from an outdated version
+func preconditionFailed(ctx *gin.Context) {
+	ctx.JSON(http.StatusPreconditionFailed, errorResponse(ctx, i18n.Errorf("precondition.failed")))
+}
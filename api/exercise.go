@@ -0,0 +1,597 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"sort"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/strength"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditEntityExercise    = "exercise"
+	auditEntityExerciseLog = "exercise_log"
+)
+
+// strengthDefaultDays is the period of the 1RM history when no start date is given
+const strengthDefaultDays = 365
+
+type exerciseRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	MuscleGroups []string `json:"muscle_groups" binding:"dive,required"`
+	Equipment    *string  `json:"equipment"`
+	VideoUrl     *string  `json:"video_url" binding:"omitempty,url"`
+}
+
+func (r *exerciseRequest) toDB(organizationID int64) db.CreateExerciseParams {
+	arg := db.CreateExerciseParams{
+		OrganizationID: organizationID,
+		Name:           r.Name,
+		MuscleGroups:   r.MuscleGroups,
+	}
+	if arg.MuscleGroups == nil {
+		arg.MuscleGroups = []string{}
+	}
+	if r.Equipment != nil {
+		arg.Equipment.SetValid(*r.Equipment)
+	}
+	if r.VideoUrl != nil {
+		arg.VideoUrl.SetValid(*r.VideoUrl)
+	}
+
+	return arg
+}
+
+func (server *Server) listExercises(ctx *gin.Context) {
+	exercises, err := server.store.ListExercises(ctx, tenantID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, exercises)
+}
+
+func (server *Server) createExercise(ctx *gin.Context) {
+	var req exerciseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var exercise db.Exercise
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		exercise, err = store.CreateExercise(ctx, req.toDB(tenantID(ctx)))
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityExercise, exercise.ID, nil, exercise)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, exercise)
+}
+
+func (server *Server) updateExercise(ctx *gin.Context) {
+	var r idRequest
+	if err := ctx.ShouldBindUri(&r); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req exerciseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	exercise, ok := server.loadExercise(ctx, r.ID)
+	if !ok {
+		return
+	}
+
+	arg := req.toDB(exercise.OrganizationID)
+	var updated db.Exercise
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateExercise(ctx, db.UpdateExerciseParams{
+			OrganizationID: exercise.OrganizationID,
+			ID:             exercise.ID,
+			Name:           arg.Name,
+			MuscleGroups:   arg.MuscleGroups,
+			Equipment:      arg.Equipment,
+			VideoUrl:       arg.VideoUrl,
+		})
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityExercise, exercise.ID, exercise, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// deleteExercise removes an exercise from the library, the prescriptions and sets already recorded keep it
+func (server *Server) deleteExercise(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	exercise, ok := server.loadExercise(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteExercise(ctx, db.DeleteExerciseParams{OrganizationID: exercise.OrganizationID, ID: exercise.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityExercise, exercise.ID, exercise, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type prescriptionRequest struct {
+	ExerciseID int64 `json:"exercise_id" binding:"required,min=1"`
+	Sets       int32 `json:"sets" binding:"required,min=1,max=20"`
+	Reps       int32 `json:"reps" binding:"required,min=1,max=100"`
+	// The intensity is given either as a percentage of the 1RM or as a target RPE
+	Percent1rm *float64 `json:"percent_1rm" binding:"omitempty,min=1,max=120"`
+	Rpe        *float64 `json:"rpe" binding:"omitempty,min=1,max=10"`
+	// Rest is in seconds
+	Rest  *int32  `json:"rest" binding:"omitempty,min=0,max=3600"`
+	Notes *string `json:"notes"`
+}
+
+type setTrainingExercisesRequest struct {
+	Exercises []prescriptionRequest `json:"exercises" binding:"dive"`
+}
+
+// prescriptionResponse is a prescribed exercise along with the load matching its percentage of the
+// estimated 1RM of the athlete, when there is one
+type prescriptionResponse struct {
+	db.ExercisePrescription
+	Load null.Float64 `json:"load"`
+}
+
+func (server *Server) listTrainingExercises(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	training, ok := server.loadTraining(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	prescriptions, err := server.store.ListTrainingPrescriptions(ctx, db.ListTrainingPrescriptionsParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp, err := server.withLoads(ctx, training, prescriptions)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// setTrainingExercises replaces the exercises prescribed for a training, in the given order
+func (server *Server) setTrainingExercises(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req setTrainingExercisesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	for _, p := range req.Exercises {
+		if p.Percent1rm != nil && p.Rpe != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("exercise.load_conflict")))
+			return
+		}
+	}
+
+	training, ok := server.loadTraining(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	exercises, err := server.exercisesByID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.SetTrainingExercisesTxParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+		Prescriptions:  make([]db.CreateExercisePrescriptionParams, len(req.Exercises)),
+	}
+	for i, p := range req.Exercises {
+		if _, ok := exercises[p.ExerciseID]; !ok {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.invalid_id", "exercise_id", p.ExerciseID)))
+			return
+		}
+
+		prescription := db.CreateExercisePrescriptionParams{
+			OrganizationID: training.OrganizationID,
+			TrainingID:     training.ID,
+			ExerciseID:     p.ExerciseID,
+			Position:       int32(i + 1),
+			Sets:           p.Sets,
+			Reps:           p.Reps,
+		}
+		if p.Percent1rm != nil {
+			prescription.Percent1rm.SetValid(*p.Percent1rm)
+		}
+		if p.Rpe != nil {
+			prescription.Rpe.SetValid(*p.Rpe)
+		}
+		if p.Rest != nil {
+			prescription.Rest.SetValid(*p.Rest)
+		}
+		if p.Notes != nil {
+			prescription.Notes.SetValid(*p.Notes)
+		}
+		arg.Prescriptions[i] = prescription
+	}
+
+	var prescriptions []db.ExercisePrescription
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		prescriptions, err = store.SetTrainingExercisesTx(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityTraining, training.ID, nil, gin.H{"exercises": prescriptions})}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp, err := server.withLoads(ctx, training, prescriptions)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type exerciseLogRequest struct {
+	ID    int64 `uri:"id" binding:"required,min=1"`
+	SetID int64 `uri:"set_id" binding:"required,min=1"`
+}
+
+type createExerciseLogRequest struct {
+	ExerciseID     int64  `json:"exercise_id" binding:"required,min=1"`
+	PrescriptionID *int64 `json:"prescription_id" binding:"omitempty,min=1"`
+	SetNumber      int32  `json:"set_number" binding:"required,min=1"`
+	Reps           int32  `json:"reps" binding:"required,min=1,max=100"`
+	// Weight is in kg, 0 for bodyweight exercises
+	Weight float64  `json:"weight" binding:"min=0,max=500"`
+	Rpe    *float64 `json:"rpe" binding:"omitempty,min=1,max=10"`
+}
+
+func (server *Server) listTrainingSets(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	training, ok := server.loadTraining(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	logs, err := server.store.ListTrainingExerciseLogs(ctx, db.ListTrainingExerciseLogsParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, logs)
+}
+
+// createTrainingSet logs a set actually done by the athlete during a training
+func (server *Server) createTrainingSet(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createExerciseLogRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	training, ok := server.loadTraining(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	if _, ok := server.loadExercise(ctx, req.ExerciseID); !ok {
+		return
+	}
+
+	arg := db.CreateExerciseLogParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+		ExerciseID:     req.ExerciseID,
+		SetNumber:      req.SetNumber,
+		Reps:           req.Reps,
+		Weight:         req.Weight,
+	}
+	if req.PrescriptionID != nil {
+		prescriptions, err := server.store.ListTrainingPrescriptions(ctx, db.ListTrainingPrescriptionsParams{
+			OrganizationID: training.OrganizationID,
+			TrainingID:     training.ID,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		found := false
+		for _, p := range prescriptions {
+			found = found || (p.ID == *req.PrescriptionID && p.ExerciseID == req.ExerciseID)
+		}
+		if !found {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.invalid_field", "prescription_id")))
+			return
+		}
+		arg.PrescriptionID.SetValid(*req.PrescriptionID)
+	}
+	if req.Rpe != nil {
+		arg.Rpe.SetValid(*req.Rpe)
+	}
+
+	var set db.ExerciseLog
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		set, err = store.CreateExerciseLog(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityExerciseLog, set.ID, nil, set)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, set)
+}
+
+func (server *Server) deleteTrainingSet(ctx *gin.Context) {
+	var req exerciseLogRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	set, err := server.store.GetExerciseLog(ctx, db.GetExerciseLogParams{OrganizationID: tenantID(ctx), ID: req.SetID})
+	if err == nil && set.TrainingID != req.ID {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteExerciseLog(ctx, db.DeleteExerciseLogParams{OrganizationID: set.OrganizationID, ID: set.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityExerciseLog, set.ID, set, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type strengthRequest struct {
+	ExerciseID int64  `form:"exercise_id" binding:"omitempty,min=1"`
+	StartDate  string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate    string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// strengthResponse is the estimated 1RM of an exercise at the end of the period along with its history
+type strengthResponse struct {
+	ExerciseID int64               `json:"exercise_id"`
+	Name       string              `json:"name"`
+	OneRepMax  null.Float64        `json:"one_rep_max"`
+	History    []strength.Estimate `json:"history"`
+}
+
+func (server *Server) getStrength(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req strengthRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	// we can ignore the errors because the values were already validated
+	end := userToday(user)
+	if req.EndDate != "" {
+		end, _ = time.Parse("2006-01-02", req.EndDate)
+	}
+	start := end.AddDate(0, 0, -strengthDefaultDays)
+	if req.StartDate != "" {
+		start, _ = time.Parse("2006-01-02", req.StartDate)
+	}
+	if end.Before(start) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.end_before_start")))
+		return
+	}
+
+	exercises, err := server.exercisesByID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// the days before start are loaded so the current estimation covers the whole recent period
+	histories, err := server.strengthHistories(ctx, user.OrganizationID, user.ID, start.AddDate(0, 0, -strength.RecentDays), end)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := []strengthResponse{}
+	for id, history := range histories {
+		if req.ExerciseID != 0 && id != req.ExerciseID {
+			continue
+		}
+
+		r := strengthResponse{ExerciseID: id, Name: exercises[id].Name, History: []strength.Estimate{}}
+		if orm, ok := strength.Current(history, end); ok {
+			r.OneRepMax.SetValid(orm)
+		}
+		for _, e := range history {
+			if !e.Date.Before(start) {
+				r.History = append(r.History, e)
+			}
+		}
+		rsp = append(rsp, r)
+	}
+	sort.Slice(rsp, func(i, j int) bool {
+		if rsp[i].Name != rsp[j].Name {
+			return rsp[i].Name < rsp[j].Name
+		}
+		return rsp[i].ExerciseID < rsp[j].ExerciseID
+	})
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// loadExercise gets an exercise of the library of the current organization, writing the error response if
+// it doesn't exist
+func (server *Server) loadExercise(ctx *gin.Context, id int64) (db.Exercise, bool) {
+	exercise, err := server.store.GetExercise(ctx, db.GetExerciseParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_field", "exercise_id")))
+			return exercise, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return exercise, false
+	}
+
+	return exercise, true
+}
+
+// exercisesByID returns the exercise library of the current organization by id
+func (server *Server) exercisesByID(ctx *gin.Context) (map[int64]db.Exercise, error) {
+	exercises, err := server.store.ListExercises(ctx, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]db.Exercise, len(exercises))
+	for _, e := range exercises {
+		byID[e.ID] = e
+	}
+	return byID, nil
+}
+
+// strengthHistories returns the best estimated 1RM of each day by exercise from the sets logged by a
+// user between two dates
+func (server *Server) strengthHistories(ctx *gin.Context, organizationID, userID int64, start, end time.Time) (map[int64][]strength.Estimate, error) {
+	rows, err := server.store.ListExerciseHistory(ctx, db.ListExerciseHistoryParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		StartDate:      start,
+		EndDate:        end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make(map[int64][]strength.Set)
+	for _, r := range rows {
+		sets[r.ExerciseID] = append(sets[r.ExerciseID], strength.Set{
+			Date:   r.Date,
+			Reps:   int(r.Reps),
+			Weight: r.Weight,
+			RPE:    r.Rpe.Float64,
+		})
+	}
+
+	histories := make(map[int64][]strength.Estimate, len(sets))
+	for id, s := range sets {
+		histories[id] = strength.History(s)
+	}
+	return histories, nil
+}
+
+// withLoads adds the load of the prescriptions given as a percentage of the 1RM, based on the estimation
+// of the athlete on the day of the training
+func (server *Server) withLoads(ctx *gin.Context, training db.Training, prescriptions []db.ExercisePrescription) ([]prescriptionResponse, error) {
+	rsp := make([]prescriptionResponse, len(prescriptions))
+	for i, p := range prescriptions {
+		rsp[i].ExercisePrescription = p
+	}
+
+	histories, err := server.strengthHistories(ctx, training.OrganizationID, training.UserID,
+		training.Date.AddDate(0, 0, -strength.RecentDays), training.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range prescriptions {
+		if !p.Percent1rm.Valid {
+			continue
+		}
+		if orm, ok := strength.Current(histories[p.ExerciseID], training.Date); ok {
+			rsp[i].Load.SetValid(strength.Load(orm, p.Percent1rm.Float64))
+		}
+	}
+	return rsp, nil
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/fitness"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityTestResult = "test_result"
+
+// testProtocolSports is the sport of the training a test is recorded on
+var testProtocolSports = map[db.TestProtocol]string{
+	db.TestProtocolCooper: sports.Running,
+	db.TestProtocolTt30:   sports.Running,
+	db.TestProtocolFtp20:  sports.Cycling,
+	db.TestProtocolCss:    sports.Swimming,
+}
+
+type createTestResultRequest struct {
+	Protocol db.TestProtocol `json:"protocol" binding:"required,oneof=cooper tt30 ftp20 css"`
+	Distance *int32          `json:"distance" binding:"omitempty,min=1"`
+	AvgPower *int32          `json:"avg_power" binding:"omitempty,min=1"`
+	AvgHr    *int32          `json:"avg_hr" binding:"omitempty,min=1"`
+	Time400m *int32          `json:"time_400m" binding:"omitempty,min=1"`
+	Time200m *int32          `json:"time_200m" binding:"omitempty,min=1"`
+	// UpdateZones creates new zone models for the athlete from the derived thresholds
+	UpdateZones bool `json:"update_zones"`
+}
+
+func (r *createTestResultRequest) toDB(training db.Training) (db.CreateTestResultTxParams, error) {
+	if sport := testProtocolSports[r.Protocol]; sport != training.Sport {
+		return db.CreateTestResultTxParams{}, i18n.Errorf("fitness.wrong_sport", r.Protocol, sport)
+	}
+
+	arg := db.CreateTestResultTxParams{
+		TestResult: db.CreateTestResultParams{
+			OrganizationID: training.OrganizationID,
+			UserID:         training.UserID,
+			TrainingID:     training.ID,
+			Protocol:       r.Protocol,
+		},
+		ZoneModels: []db.CreateZoneModelParams{},
+	}
+	var in fitness.Input
+	if r.Distance != nil {
+		arg.TestResult.Distance.SetValid(*r.Distance)
+		in.Distance = int(*r.Distance)
+	}
+	if r.AvgPower != nil {
+		arg.TestResult.AvgPower.SetValid(*r.AvgPower)
+		in.AvgPower = int(*r.AvgPower)
+	}
+	if r.AvgHr != nil {
+		arg.TestResult.AvgHr.SetValid(*r.AvgHr)
+		in.AvgHR = int(*r.AvgHr)
+	}
+	if r.Time400m != nil {
+		arg.TestResult.Time400m.SetValid(*r.Time400m)
+		in.Time400 = int(*r.Time400m)
+	}
+	if r.Time200m != nil {
+		arg.TestResult.Time200m.SetValid(*r.Time200m)
+		in.Time200 = int(*r.Time200m)
+	}
+
+	result, err := fitness.Calculate(fitness.Protocol(r.Protocol), in)
+	if err != nil {
+		return db.CreateTestResultTxParams{}, err
+	}
+
+	addZoneModel := func(sport string, method db.ZoneMethod, threshold int) {
+		if !r.UpdateZones {
+			return
+		}
+		arg.ZoneModels = append(arg.ZoneModels, db.CreateZoneModelParams{
+			OrganizationID: training.OrganizationID,
+			UserID:         training.UserID,
+			Sport:          sport,
+			Method:         method,
+			Threshold:      int32(threshold),
+			EffectiveFrom:  training.Date,
+		})
+	}
+	if result.VO2max > 0 {
+		arg.TestResult.Vo2max.SetValid(result.VO2max)
+	}
+	if result.ThresholdPace > 0 {
+		arg.TestResult.ThresholdPace.SetValid(int32(result.ThresholdPace))
+		addZoneModel(sports.Running, db.ZoneMethodPace, result.ThresholdPace)
+	}
+	if result.FTP > 0 {
+		arg.TestResult.Ftp.SetValid(int32(result.FTP))
+		addZoneModel(sports.Cycling, db.ZoneMethodPower, result.FTP)
+	}
+	if result.CSS > 0 {
+		arg.TestResult.Css.SetValid(int32(result.CSS))
+		addZoneModel(sports.Swimming, db.ZoneMethodCss, result.CSS)
+	}
+	if result.LTHR > 0 {
+		arg.TestResult.Lthr.SetValid(int32(result.LTHR))
+		addZoneModel(training.Sport, db.ZoneMethodHrLthr, result.LTHR)
+	}
+
+	return arg, nil
+}
+
+func (server *Server) getTestResult(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	result, err := server.store.GetTestResult(ctx, db.GetTestResultParams{
+		OrganizationID: tenantID(ctx),
+		TrainingID:     req.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) createTestResult(ctx *gin.Context) {
+	var t idRequest
+	if err := ctx.ShouldBindUri(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createTestResultRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the training exists
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: t.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	arg, err := req.toDB(training)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var result db.CreateTestResultTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.CreateTestResultTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []db.AuditEntry{auditEntry(auditActionCreate, auditEntityTestResult, result.TestResult.ID, nil, result.TestResult)}
+		for _, model := range result.ZoneModels {
+			entries = append(entries, auditEntry(auditActionCreate, auditEntityZoneModel, model.ID, nil, model))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) deleteTestResult(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	result, err := server.store.GetTestResult(ctx, db.GetTestResultParams{
+		OrganizationID: tenantID(ctx),
+		TrainingID:     req.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// Zone models created from the result are kept, they can be deleted on their own
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteTestResult(ctx, db.DeleteTestResultParams{
+			OrganizationID: result.OrganizationID,
+			TrainingID:     result.TrainingID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityTestResult, result.ID, result, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (server *Server) listTestResultsByUser(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	results, err := server.store.ListTestResultsByUser(ctx, db.ListTestResultsByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/gdpr"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (server *Server) exportUser(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Only admins and the user themselves can export the data
+	actor, ok := currentActor(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, i18n.Errorf("actor.missing")))
+		return
+	}
+	if actor.ID != req.ID && actor.Type != db.UserTypeAdmin {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, i18n.Errorf("gdpr.export_forbidden")))
+		return
+	}
+
+	data, err := gdpr.Collect(ctx, server.store, tenantID(ctx), req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = gdpr.Write(&buf, data); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	err = server.auditTx(ctx, func(db.Store) ([]db.AuditEntry, error) {
+		return []db.AuditEntry{auditEntry(gdpr.AuditActionExport, auditEntityUser, req.ID, nil, nil)}, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	filename := fmt.Sprintf("user-%d-export.zip", req.ID)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+func (server *Server) eraseUser(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the user exists, even if it was deleted
+	_, err := server.store.GetUserIncludingDeleted(ctx, db.GetUserIncludingDeletedParams{
+		OrganizationID: tenantID(ctx),
+		ID:             req.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	actor, _ := currentActor(ctx)
+	actorID := nullActorID(actor)
+
+	erased, err := gdpr.Erase(ctx, server.store, tenantID(ctx), req.ID, actorID, ctx.GetString(requestIDKey))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, erased)
+}
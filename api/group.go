@@ -0,0 +1,650 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	auditEntityGroup         = "group"
+	auditEntityGroupMember   = "group_member"
+	auditEntityGroupTraining = "group_training"
+)
+
+type groupMemberRequest struct {
+	ID     int64 `uri:"id" binding:"required,min=1"`
+	UserID int64 `uri:"user_id" binding:"required,min=1"`
+}
+
+type groupTrainingRequest struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	TrainingID int64 `uri:"training_id" binding:"required,min=1"`
+}
+
+type propagateRequest struct {
+	Propagate bool `form:"propagate"`
+}
+
+type createGroupRequest struct {
+	Name    string `json:"name" binding:"required"`
+	CoachID *int64 `json:"coach_id" binding:"omitempty,min=1"`
+}
+
+func (server *Server) listGroups(ctx *gin.Context) {
+	var req listUserRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.ListGroupsParams{
+		OrganizationID: tenantID(ctx),
+		Limit:          req.PageSize,
+		Offset:         (req.PageID - 1) * req.PageSize,
+	}
+
+	groups, err := server.store.ListGroups(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groups)
+}
+
+func (server *Server) getGroup(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+}
+
+func (server *Server) createGroup(ctx *gin.Context) {
+	var req createGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	coachID, ok := server.groupCoach(ctx, req.CoachID)
+	if !ok {
+		return
+	}
+
+	arg := db.CreateGroupParams{
+		OrganizationID: tenantID(ctx),
+		Name:           req.Name,
+		CoachID:        coachID,
+	}
+
+	var group db.Group
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		group, err = store.CreateGroup(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityGroup, group.ID, nil, group)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+}
+
+func (server *Server) updateGroup(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	coachID, ok := server.groupCoach(ctx, req.CoachID)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateGroupParams{
+		OrganizationID: group.OrganizationID,
+		ID:             group.ID,
+		Name:           req.Name,
+		CoachID:        coachID,
+	}
+
+	var updated db.Group
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateGroup(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityGroup, group.ID, group, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (server *Server) deleteGroup(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteGroup(ctx, db.DeleteGroupParams{OrganizationID: group.OrganizationID, ID: group.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityGroup, group.ID, group, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (server *Server) listGroupMembers(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	members, err := server.store.ListGroupMembers(ctx, db.ListGroupMembersParams{
+		OrganizationID: group.OrganizationID,
+		GroupID:        group.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+type addGroupMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required,min=1"`
+}
+
+func (server *Server) addGroupMember(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req addGroupMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	// Only athletes can be members of a group
+	user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: group.OrganizationID, ID: req.UserID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_id", "user_id", req.UserID)))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if user.Type != db.UserTypeAthlete {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("group.athletes_only")))
+		return
+	}
+
+	var member db.GroupMember
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		member, err = store.AddGroupMember(ctx, db.AddGroupMemberParams{GroupID: group.ID, UserID: user.ID})
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityGroupMember, group.ID, nil, member)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+func (server *Server) removeGroupMember(ctx *gin.Context) {
+	var req groupMemberRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	arg := db.RemoveGroupMemberParams{GroupID: group.ID, UserID: req.UserID}
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.RemoveGroupMember(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityGroupMember, group.ID, arg, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (server *Server) listGroupTrainings(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	trainings, err := server.store.ListGroupTrainings(ctx, db.ListGroupTrainingsParams{
+		OrganizationID: group.OrganizationID,
+		GroupID:        group.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trainings)
+}
+
+func (server *Server) getGroupTraining(ctx *gin.Context) {
+	var req groupTrainingRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	groupTraining, ok := server.loadGroupTraining(ctx, req)
+	if !ok {
+		return
+	}
+
+	trainings, err := server.store.ListTrainingsByGroupTraining(ctx, db.ListTrainingsByGroupTrainingParams{
+		OrganizationID:  groupTraining.OrganizationID,
+		GroupTrainingID: null.NewInt64(groupTraining.ID, true),
+	})
+	if err == nil {
+		err = server.markOverdue(ctx, trainings)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, db.GroupTrainingTxResult{GroupTraining: groupTraining, Trainings: trainings})
+}
+
+// groupTrainingWarningsResponse is a saved group training along with the availability conflicts of the
+// trainings of its members
+type groupTrainingWarningsResponse struct {
+	db.GroupTrainingTxResult
+	Warnings []availabilityWarning `json:"warnings,omitempty"`
+}
+
+// createGroupTraining plans a training for a group, fanning it out to the calendar of each member
+func (server *Server) createGroupTraining(ctx *gin.Context, req createTrainingRequest) {
+	if req.Attributes != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("group.attributes")))
+		return
+	}
+	if req.Sport == sports.Multisport {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("group.multisport")))
+		return
+	}
+
+	group, ok := server.loadGroup(ctx, req.GroupID)
+	if !ok {
+		return
+	}
+
+	training, err := req.toDB(group.OrganizationID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.CreateGroupTrainingTxParams{
+		GroupTraining: db.CreateGroupTrainingParams{
+			OrganizationID:  group.OrganizationID,
+			GroupID:         group.ID,
+			Date:            training.Date,
+			Sport:           training.Sport,
+			Type:            training.Type,
+			Intensity:       training.Intensity,
+			Details:         training.Details,
+			PlannedDuration: training.PlannedDuration,
+		},
+		Status: training.Status,
+	}
+
+	members, err := server.store.ListGroupMembers(ctx, db.ListGroupMembersParams{
+		OrganizationID: group.OrganizationID,
+		GroupID:        group.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	planned := make([]plannedTraining, 0, len(members))
+	for _, member := range members {
+		planned = append(planned, plannedTraining{UserID: member.ID, Date: training.Date, Duration: training.PlannedDuration.Int32})
+	}
+	warnings, ok := server.checkTrainingsAvailability(ctx, planned)
+	if !ok {
+		return
+	}
+
+	var result db.GroupTrainingTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.CreateGroupTrainingTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []db.AuditEntry{auditEntry(auditActionCreate, auditEntityGroupTraining, result.GroupTraining.ID, nil, result.GroupTraining)}
+		for _, t := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionCreate, auditEntityTraining, t.ID, nil, t))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groupTrainingWarningsResponse{GroupTrainingTxResult: result, Warnings: warnings})
+}
+
+type updateGroupTrainingRequest struct {
+	Date      string  `json:"date" binding:"required,datetime=2006-01-02"`
+	Sport     string  `json:"sport" binding:"required"`
+	Type      *string `json:"type"`
+	Intensity *string `json:"intensity"`
+	Details   string  `json:"details" binding:"required"`
+	// PlannedDuration is in seconds
+	PlannedDuration *int32 `json:"planned_duration" binding:"omitempty,min=1"`
+}
+
+func (r *updateGroupTrainingRequest) toDB(organizationID, id int64) (db.UpdateGroupTrainingParams, error) {
+	d, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return db.UpdateGroupTrainingParams{}, err
+	}
+	arg := db.UpdateGroupTrainingParams{
+		OrganizationID: organizationID,
+		ID:             id,
+		Date:           d,
+		Sport:          r.Sport,
+		Details:        r.Details,
+	}
+	if r.Type != nil {
+		arg.Type.SetValid(*r.Type)
+	}
+	if r.Intensity != nil {
+		arg.Intensity.SetValid(*r.Intensity)
+	}
+	if r.PlannedDuration != nil {
+		arg.PlannedDuration.SetValid(*r.PlannedDuration)
+	}
+
+	return arg, nil
+}
+
+// updateGroupTraining updates a group training. With ?propagate=true the change is also
+// applied to the trainings of the members who haven't completed it yet.
+func (server *Server) updateGroupTraining(ctx *gin.Context) {
+	var u groupTrainingRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req updateGroupTrainingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var p propagateRequest
+	if err := ctx.ShouldBindQuery(&p); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// the group trainings have no attributes, so sports with required metrics can't be planned for groups
+	if !server.checkTrainingSport(ctx, req.Sport, nil) {
+		return
+	}
+	if req.Sport == sports.Multisport {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("group.multisport")))
+		return
+	}
+
+	groupTraining, ok := server.loadGroupTraining(ctx, u)
+	if !ok {
+		return
+	}
+
+	arg, err := req.toDB(groupTraining.OrganizationID, groupTraining.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var before []db.Training
+	if p.Propagate {
+		before, err = server.store.ListTrainingsByGroupTraining(ctx, db.ListTrainingsByGroupTrainingParams{
+			OrganizationID:  groupTraining.OrganizationID,
+			GroupTrainingID: null.NewInt64(groupTraining.ID, true),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	var planned []plannedTraining
+	for _, t := range before {
+		if t.Status != db.TrainingStatusDone && t.Status != db.TrainingStatusDoneFeedback {
+			planned = append(planned, plannedTraining{UserID: t.UserID, Date: arg.Date, Duration: arg.PlannedDuration.Int32})
+		}
+	}
+	warnings, ok := server.checkTrainingsAvailability(ctx, planned)
+	if !ok {
+		return
+	}
+
+	previous := map[int64]db.Training{}
+	for _, t := range before {
+		previous[t.ID] = t
+	}
+
+	var result db.GroupTrainingTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.UpdateGroupTrainingTx(ctx, db.UpdateGroupTrainingTxParams{
+			GroupTraining: arg,
+			Propagate:     p.Propagate,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityGroupTraining, groupTraining.ID, groupTraining, result.GroupTraining)}
+		for _, t := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionUpdate, auditEntityTraining, t.ID, previous[t.ID], t))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groupTrainingWarningsResponse{GroupTrainingTxResult: result, Warnings: warnings})
+}
+
+// deleteGroupTraining deletes a group training. With ?propagate=true the trainings of the
+// members who haven't completed it yet are deleted as well.
+func (server *Server) deleteGroupTraining(ctx *gin.Context) {
+	var req groupTrainingRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var p propagateRequest
+	if err := ctx.ShouldBindQuery(&p); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	groupTraining, ok := server.loadGroupTraining(ctx, req)
+	if !ok {
+		return
+	}
+
+	var result db.GroupTrainingTxResult
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.DeleteGroupTrainingTx(ctx, db.DeleteGroupTrainingTxParams{
+			OrganizationID:  groupTraining.OrganizationID,
+			GroupTrainingID: groupTraining.ID,
+			Propagate:       p.Propagate,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []db.AuditEntry{auditEntry(auditActionDelete, auditEntityGroupTraining, groupTraining.ID, groupTraining, nil)}
+		for _, t := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionDelete, auditEntityTraining, t.ID, t, nil))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// loadGroup gets an active group, writing the error response if it doesn't exist
+func (server *Server) loadGroup(ctx *gin.Context, id int64) (db.Group, bool) {
+	group, err := server.store.GetGroup(ctx, db.GetGroupParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_id", "group_id", id)))
+			return group, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return group, false
+	}
+
+	return group, true
+}
+
+// loadGroupTraining gets a training of a group, writing the error response if it doesn't exist
+func (server *Server) loadGroupTraining(ctx *gin.Context, req groupTrainingRequest) (db.GroupTraining, bool) {
+	groupTraining, err := server.store.GetGroupTraining(ctx, db.GetGroupTrainingParams{
+		OrganizationID: tenantID(ctx),
+		ID:             req.TrainingID,
+	})
+	if err == nil && groupTraining.GroupID != req.ID {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return groupTraining, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return groupTraining, false
+	}
+
+	return groupTraining, true
+}
+
+// groupCoach checks that the coach of a group is a coach or an admin,
+// writing the error response if it isn't
+func (server *Server) groupCoach(ctx *gin.Context, id *int64) (null.Int64, bool) {
+	var coachID null.Int64
+	if id == nil {
+		return coachID, true
+	}
+
+	user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: *id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_id", "coach_id", *id)))
+			return coachID, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return coachID, false
+	}
+	if user.Type != db.UserTypeCoach && user.Type != db.UserTypeAdmin {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("group.coach_type")))
+		return coachID, false
+	}
+
+	coachID.SetValid(user.ID)
+	return coachID, true
+}
@@ -0,0 +1,427 @@
+package api
+
+import (
+	"errors"
+	"database/sql"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityInjury = "injury"
+
+const (
+	pauseActionReschedule = "reschedule"
+	pauseActionCancel     = "cancel"
+)
+
+type injuryRequest struct {
+	Kind           db.InjuryKind     `json:"kind" binding:"required,oneof=injury illness"`
+	BodyPart       *string           `json:"body_part"`
+	Diagnosis      string            `json:"diagnosis" binding:"required"`
+	Onset          string            `json:"onset" binding:"required,datetime=2006-01-02"`
+	Severity       db.InjurySeverity `json:"severity" binding:"required,oneof=minor moderate severe"`
+	Status         db.InjuryStatus   `json:"status" binding:"omitempty,oneof=active recovering resolved"`
+	ExpectedReturn *string           `json:"expected_return" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (r *injuryRequest) toDB(organizationID, userID int64) (db.CreateInjuryParams, error) {
+	onset, err := time.Parse("2006-01-02", r.Onset)
+	if err != nil {
+		return db.CreateInjuryParams{}, err
+	}
+
+	arg := db.CreateInjuryParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Kind:           r.Kind,
+		Diagnosis:      r.Diagnosis,
+		Onset:          onset,
+		Severity:       r.Severity,
+		Status:         r.Status,
+	}
+	if arg.Status == "" {
+		arg.Status = db.InjuryStatusActive
+	}
+	if r.BodyPart != nil {
+		arg.BodyPart.SetValid(*r.BodyPart)
+	}
+	if r.ExpectedReturn != nil {
+		expected, err := time.Parse("2006-01-02", *r.ExpectedReturn)
+		if err != nil {
+			return db.CreateInjuryParams{}, err
+		}
+		if !expected.After(onset) {
+			return db.CreateInjuryParams{}, i18n.Errorf("injury.expected_return")
+		}
+		arg.ExpectedReturn.SetValid(expected)
+	}
+
+	return arg, nil
+}
+
+type createInjuryRequest struct {
+	UserID int64 `json:"user_id" binding:"required,min=1"`
+	injuryRequest
+}
+
+// injuryResponse is an injury along with the feedbacks reporting pain linked to it
+type injuryResponse struct {
+	db.Injury
+	PainReports []db.TrainingFeedback `json:"pain_reports"`
+}
+
+func (server *Server) listInjuriesByUser(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	injuries, err := server.store.ListInjuriesByUser(ctx, db.ListInjuriesByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, injuries)
+}
+
+func (server *Server) getInjury(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	injury, ok := server.loadInjury(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	feedbacks, err := server.store.ListInjuryFeedbacks(ctx, db.ListInjuryFeedbacksParams{
+		OrganizationID: injury.OrganizationID,
+		InjuryID:       null.NewInt64(injury.ID, true),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, injuryResponse{Injury: injury, PainReports: feedbacks})
+}
+
+func (server *Server) createInjury(ctx *gin.Context) {
+	var req createInjuryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.loadUser(ctx, req.UserID); !ok {
+		return
+	}
+
+	arg, err := req.toDB(tenantID(ctx), req.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var injury db.Injury
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		injury, err = store.CreateInjury(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityInjury, injury.ID, nil, injury)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, injury)
+}
+
+func (server *Server) updateInjury(ctx *gin.Context) {
+	var r idRequest
+	if err := ctx.ShouldBindUri(&r); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req injuryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	injury, ok := server.loadInjury(ctx, r.ID)
+	if !ok {
+		return
+	}
+
+	arg, err := req.toDB(injury.OrganizationID, injury.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var updated db.Injury
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateInjury(ctx, db.UpdateInjuryParams{
+			OrganizationID: injury.OrganizationID,
+			ID:             injury.ID,
+			Kind:           arg.Kind,
+			BodyPart:       arg.BodyPart,
+			Diagnosis:      arg.Diagnosis,
+			Onset:          arg.Onset,
+			Severity:       arg.Severity,
+			Status:         arg.Status,
+			ExpectedReturn: arg.ExpectedReturn,
+		})
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityInjury, injury.ID, injury, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (server *Server) deleteInjury(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	injury, ok := server.loadInjury(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	// The pain reports of the feedbacks are kept, without the link to the injury
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteInjury(ctx, db.DeleteInjuryParams{OrganizationID: injury.OrganizationID, ID: injury.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityInjury, injury.ID, injury, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// listInjuryTrainings returns the planned trainings falling inside the injury period
+func (server *Server) listInjuryTrainings(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	injury, ok := server.loadInjury(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	trainings, err := server.store.ListInjuryTrainings(ctx, db.ListInjuryTrainingsParams{
+		OrganizationID: injury.OrganizationID,
+		ID:             injury.ID,
+	})
+	if err == nil {
+		err = server.markOverdue(ctx, trainings)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trainings)
+}
+
+type pausePlanRequest struct {
+	Action string `json:"action" binding:"required,oneof=reschedule cancel"`
+	// Days the trainings are rescheduled by, by default the first one is moved to the expected return.
+	// They can't be moved less than that, the trainings would still fall inside the injury.
+	Days int `json:"days" binding:"omitempty,min=1,max=365"`
+}
+
+// pausePlan reschedules or cancels the planned trainings falling inside the injury period
+func (server *Server) pausePlan(ctx *gin.Context) {
+	var r idRequest
+	if err := ctx.ShouldBindUri(&r); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req pausePlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	injury, ok := server.loadInjury(ctx, r.ID)
+	if !ok {
+		return
+	}
+	if injury.Status == db.InjuryStatusResolved {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("injury.resolved")))
+		return
+	}
+
+	before, err := server.store.ListInjuryTrainings(ctx, db.ListInjuryTrainingsParams{
+		OrganizationID: injury.OrganizationID,
+		ID:             injury.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if len(before) == 0 {
+		ctx.JSON(http.StatusOK, []db.Training{})
+		return
+	}
+
+	arg := db.PauseTrainingsTxParams{
+		OrganizationID: injury.OrganizationID,
+		InjuryID:       injury.ID,
+	}
+	if req.Action == pauseActionReschedule {
+		// without an expected return there is no date past the injury to move the trainings to
+		if !injury.ExpectedReturn.Valid {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("injury.no_return")))
+			return
+		}
+		// the trainings are sorted by date
+		minDays := int(injury.ExpectedReturn.Time.Sub(before[0].Date).Hours() / 24)
+		arg.Days = req.Days
+		if arg.Days == 0 {
+			arg.Days = minDays
+		} else if arg.Days < minDays {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("injury.days_short", minDays)))
+			return
+		}
+	}
+
+	previous := map[int64]db.Training{}
+	for _, t := range before {
+		previous[t.ID] = t
+	}
+
+	var trainings []db.Training
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		trainings, err = store.PauseTrainingsTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]db.AuditEntry, 0, len(trainings))
+		for _, training := range trainings {
+			if req.Action == pauseActionCancel {
+				entries = append(entries, auditEntry(auditActionDelete, auditEntityTraining, training.ID, training, nil))
+			} else {
+				entries = append(entries, auditEntry(auditActionUpdate, auditEntityTraining, training.ID, previous[training.ID], training))
+			}
+		}
+		return entries, nil
+	})
+	if err != nil {
+		// a training was updated while it was rescheduled
+		if errors.Is(err, db.ErrVersionConflict) {
+			preconditionFailed(ctx)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trainings)
+}
+
+// loadInjury gets an injury of the current organization, writing the error response if it doesn't exist
+func (server *Server) loadInjury(ctx *gin.Context, id int64) (db.Injury, bool) {
+	injury, err := server.store.GetInjury(ctx, db.GetInjuryParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return injury, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return injury, false
+	}
+
+	return injury, true
+}
+
+// checkFeedbackInjury checks that the injury a feedback is linked to belongs to the athlete of the
+// training, writing the error response if it doesn't
+func (server *Server) checkFeedbackInjury(ctx *gin.Context, training db.Training, injuryID *int64) bool {
+	if injuryID == nil {
+		return true
+	}
+
+	injury, err := server.store.GetInjury(ctx, db.GetInjuryParams{OrganizationID: training.OrganizationID, ID: *injuryID})
+	if err == nil && injury.UserID != training.UserID {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.invalid_id", "injury_id", *injuryID)))
+			return false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return false
+	}
+
+	return true
+}
+
+// withInjuries flags the planned trainings falling inside an active injury period of the athlete
+func (server *Server) withInjuries(ctx *gin.Context, trainings []trainingResponse) error {
+	if len(trainings) == 0 {
+		return nil
+	}
+
+	injuries, err := server.store.ListActiveInjuriesByUser(ctx, db.ListActiveInjuriesByUserParams{
+		OrganizationID: trainings[0].OrganizationID,
+		UserID:         trainings[0].UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, t := range trainings {
+		if t.Status != db.TrainingStatusNew && t.Status != db.TrainingStatusNotified {
+			continue
+		}
+		for _, injury := range injuries {
+			if !t.Date.Before(injury.Onset) && (!injury.ExpectedReturn.Valid || t.Date.Before(injury.ExpectedReturn.Time)) {
+				trainings[i].InjuryID.SetValid(injury.ID)
+				break
+			}
+		}
+	}
+
+	return nil
+}
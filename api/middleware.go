@@ -0,0 +1,134 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	languageHeader      = "Accept-Language"
+	authorizationHeader = "Authorization"
+	requestIDHeader     = "X-Request-ID"
+
+	bearerPrefix = "Bearer "
+
+	actorKey        = "actor"
+	organizationKey = "organization"
+	requestIDKey    = "request_id"
+)
+
+// requestID tags every request with an id, reusing the one sent by the client if any
+func requestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestIDHeader)
+		if id == "" {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, err))
+				return
+			}
+			id = hex.EncodeToString(b)
+		}
+
+		ctx.Set(requestIDKey, id)
+		ctx.Header(requestIDHeader, id)
+		ctx.Next()
+	}
+}
+
+// authenticate identifies the user performing the request by the bearer token of the Authorization header.
+// The request is scoped to the organization of that user: every query made while handling it is restricted
+// to that organization.
+func (server *Server) authenticate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader(authorizationHeader)
+		if !strings.HasPrefix(header, bearerPrefix) || len(header) == len(bearerPrefix) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, i18n.Errorf("auth.missing_token")))
+			return
+		}
+
+		user, err := server.store.GetApiTokenUser(ctx, hashToken(strings.TrimPrefix(header, bearerPrefix)))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, i18n.Errorf("auth.invalid_token")))
+				return
+			}
+
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		organization, err := server.store.GetOrganization(ctx, user.OrganizationID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, i18n.Errorf("auth.invalid_token")))
+				return
+			}
+
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		ctx.Set(organizationKey, organization)
+		ctx.Set(actorKey, user)
+		ctx.Next()
+	}
+}
+
+// adminOnly rejects requests not performed by an admin
+func adminOnly() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, ok := currentActor(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(ctx, i18n.Errorf("actor.missing")))
+			return
+		}
+		if user.Type != db.UserTypeAdmin {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(ctx, i18n.Errorf("actor.admin_only")))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func currentActor(ctx *gin.Context) (db.User, bool) {
+	v, ok := ctx.Get(actorKey)
+	if !ok {
+		return db.User{}, false
+	}
+	user, ok := v.(db.User)
+	return user, ok
+}
+
+func currentOrganization(ctx *gin.Context) db.Organization {
+	return ctx.MustGet(organizationKey).(db.Organization)
+}
+
+// tenantID is the id of the organization the request is scoped to
+func tenantID(ctx *gin.Context) int64 {
+	return currentOrganization(ctx).ID
+}
+
+func nullActorID(user db.User) null.Int64 {
+	return null.NewInt64(user.ID, user.ID != 0)
+}
+
+// language is the language of the messages of a response, the one asked for in the Accept-Language header
+// or else the locale of the actor
+func language(ctx *gin.Context) string {
+	var locales []string
+	if user, ok := currentActor(ctx); ok {
+		locales = append(locales, user.Locale)
+	}
+	return i18n.Negotiate(ctx.GetHeader(languageHeader), locales...)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityOrganization = "organization"
+
+// organizationSettings holds the per-organization overrides stored in the settings column
+type organizationSettings struct {
+	// TrashRetentionDays overrides the configured trash retention when set
+	TrashRetentionDays int `json:"trash_retention_days" binding:"min=0"`
+	// StrictAvailability rejects the trainings planned when the athlete is unavailable instead
+	// of returning warnings
+	StrictAvailability bool `json:"strict_availability"`
+}
+
+func parseOrganizationSettings(organization db.Organization) organizationSettings {
+	var settings organizationSettings
+	if len(organization.Settings) > 0 {
+		// Unknown or malformed settings fall back to the defaults
+		_ = json.Unmarshal(organization.Settings, &settings)
+	}
+	return settings
+}
+
+// retention returns how long deleted rows of the organization are kept in the trash
+func (server *Server) retention(organization db.Organization) time.Duration {
+	settings := parseOrganizationSettings(organization)
+	if settings.TrashRetentionDays > 0 {
+		return time.Duration(settings.TrashRetentionDays) * 24 * time.Hour
+	}
+	return server.config.TrashRetention
+}
+
+func (server *Server) getOrganization(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, currentOrganization(ctx))
+}
+
+type updateOrganizationRequest struct {
+	Name     string               `json:"name" binding:"required"`
+	Settings organizationSettings `json:"settings"`
+}
+
+func (server *Server) updateOrganization(ctx *gin.Context) {
+	var req updateOrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	settings, err := json.Marshal(req.Settings)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	organization := currentOrganization(ctx)
+	arg := db.UpdateOrganizationParams{
+		ID:       organization.ID,
+		Name:     req.Name,
+		Settings: settings,
+	}
+
+	var updated db.Organization
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateOrganization(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityOrganization, organization.ID, organization, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
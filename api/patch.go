@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/patch"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bindPatch applies the patch of the request body to the current values of an entity, given as its PUT
+// request, and binds the result to req as if it had been sent whole. A plain JSON body is taken as a merge
+// patch. It writes the error response if the patch can't be applied or the result isn't valid.
+func bindPatch(ctx *gin.Context, current, req interface{}) bool {
+	mediaType := ctx.ContentType()
+	if mediaType == binding.MIMEJSON {
+		mediaType = patch.MergePatchType
+	}
+	if mediaType != patch.MergePatchType && mediaType != patch.JSONPatchType {
+		ctx.JSON(http.StatusUnsupportedMediaType, errorResponse(ctx, i18n.Errorf("patch.unsupported_type", mediaType)))
+		return false
+	}
+
+	body, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return false
+	}
+	doc, err := json.Marshal(current)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return false
+	}
+
+	patched, err := patch.Apply(mediaType, doc, body)
+	if err != nil {
+		if errors.Is(err, patch.ErrTestFailed) {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, i18n.Errorf("patch.test_failed")))
+			return false
+		}
+
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("patch.invalid", err)))
+		return false
+	}
+
+	if err := json.Unmarshal(patched, req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("patch.invalid", err)))
+		return false
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return false
+	}
+	return true
+}
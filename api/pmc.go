@@ -0,0 +1,152 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/load"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// pmcWarmUpDays are loaded before the start date to seed the averages
+	pmcWarmUpDays = 180
+	// pmcDefaultDays is the period shown when no start date is given
+	pmcDefaultDays = 90
+	// pmcMaxDays is the longest period that can be requested
+	pmcMaxDays = 3 * 365
+)
+
+type pmcRequest struct {
+	StartDate string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	// Project adds the estimated load of the planned trainings after today
+	Project bool `form:"project"`
+}
+
+// getPMC returns the daily training stress, fitness (CTL), fatigue (ATL) and form (TSB) of an athlete
+func (server *Server) getPMC(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req pmcRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	today := userToday(user)
+	end := today
+	if req.EndDate != "" {
+		// we can ignore the errors because the values were already validated
+		end, _ = time.Parse("2006-01-02", req.EndDate)
+	}
+	start := end.AddDate(0, 0, 1-pmcDefaultDays)
+	if req.StartDate != "" {
+		start, _ = time.Parse("2006-01-02", req.StartDate)
+	}
+	if end.Before(start) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.end_before_start")))
+		return
+	}
+	if end.Sub(start).Hours()/24 >= pmcMaxDays {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.period_too_long")))
+		return
+	}
+
+	from := start.AddDate(0, 0, -pmcWarmUpDays)
+	trainings, err := server.store.ListTrainingLoadsByUser(ctx, db.ListTrainingLoadsByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		StartDate:      from,
+		EndDate:        end,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	models, err := server.store.ListZoneModelsByUser(ctx, db.ListZoneModelsByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	loads := make([]float64, int(end.Sub(from).Hours()/24)+1)
+	for _, t := range trainings {
+		day := int(t.Date.Sub(from).Hours() / 24)
+		loads[day] += trainingLoad(t, models, today, req.Project)
+	}
+
+	days := load.Chart(from, loads, pmcWarmUpDays)
+	for i := range days {
+		days[i].Projected = days[i].Date.After(today)
+	}
+
+	ctx.JSON(http.StatusOK, days)
+}
+
+// trainingLoad returns the stress score of a training from its feedback, or the estimated score
+// of a planned training after today when projecting
+func trainingLoad(t db.ListTrainingLoadsByUserRow, models []db.ZoneModel, today time.Time, project bool) float64 {
+	if t.BorgScale == 0 {
+		if project && t.Status == db.TrainingStatusNew && t.Date.After(today) {
+			return load.PlannedTSS(int(t.PlannedDuration.Int32), t.Intensity.String)
+		}
+		return 0
+	}
+
+	session := load.Session{
+		Duration: int(t.Duration),
+		Distance: int(t.Distance),
+		AvgPower: int(t.AvgPower),
+		AvgHR:    int(t.AvgHr),
+		Borg:     int(t.BorgScale),
+	}
+	if session.Duration == 0 {
+		// Assume the session went as planned
+		session.Duration = int(t.PlannedDuration.Int32)
+	}
+
+	return load.TSS(load.Sport(t.Sport), session, thresholds(models, t.Sport, t.Date))
+}
+
+// thresholds returns the latest thresholds of each method effective on the date
+func thresholds(models []db.ZoneModel, sport string, date time.Time) load.Thresholds {
+	var th load.Thresholds
+	seen := make(map[db.ZoneMethod]bool)
+	for _, model := range models {
+		// The models are sorted by sport and method, latest first
+		if model.Sport != sport || seen[model.Method] || model.EffectiveFrom.After(date) {
+			continue
+		}
+		seen[model.Method] = true
+
+		switch model.Method {
+		case db.ZoneMethodPower:
+			th.FTP = int(model.Threshold)
+		case db.ZoneMethodPace:
+			th.Pace = int(model.Threshold)
+		case db.ZoneMethodCss:
+			th.CSS = int(model.Threshold)
+		case db.ZoneMethodHrLthr:
+			th.LTHR = int(model.Threshold)
+		}
+	}
+
+	return th
+}
@@ -0,0 +1,418 @@
+package api
+
+import (
+	"database/sql"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rondondev/runapp/calc"
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityRace = "race"
+
+// recentResults is how many of the latest race results are considered for predictions
+const recentResults = 3
+
+// predictionDistances are the distances always included in the predictions
+var predictionDistances = []int32{5000, 10000, 21098, 42195}
+
+type createRaceRequest struct {
+	UserID     int64           `json:"user_id" binding:"required,min=1"`
+	Name       string          `json:"name" binding:"required"`
+	Date       string          `json:"date" binding:"required,datetime=2006-01-02"`
+	Sport      string          `json:"sport" binding:"required"`
+	Distance   int32           `json:"distance" binding:"required,min=1"`
+	Priority   db.RacePriority `json:"priority" binding:"omitempty,oneof=A B C"`
+	GoalTime   *int32          `json:"goal_time" binding:"omitempty,min=1"`
+	ResultTime *int32          `json:"result_time" binding:"omitempty,min=1"`
+}
+
+func (r *createRaceRequest) toDB(organizationID int64) (db.CreateRaceParams, error) {
+	date, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return db.CreateRaceParams{}, err
+	}
+
+	arg := db.CreateRaceParams{
+		OrganizationID: organizationID,
+		UserID:         r.UserID,
+		Name:           r.Name,
+		Date:           date,
+		Sport:          r.Sport,
+		Distance:       r.Distance,
+		Priority:       r.Priority,
+	}
+	if arg.Priority == "" {
+		arg.Priority = db.RacePriorityC
+	}
+	if r.GoalTime != nil {
+		arg.GoalTime.SetValid(*r.GoalTime)
+	}
+	if r.ResultTime != nil {
+		arg.ResultTime.SetValid(*r.ResultTime)
+	}
+
+	return arg, nil
+}
+
+type updateRaceRequest struct {
+	Name       string          `json:"name" binding:"required"`
+	Date       string          `json:"date" binding:"required,datetime=2006-01-02"`
+	Sport      string          `json:"sport" binding:"required"`
+	Distance   int32           `json:"distance" binding:"required,min=1"`
+	Priority   db.RacePriority `json:"priority" binding:"required,oneof=A B C"`
+	GoalTime   *int32          `json:"goal_time" binding:"omitempty,min=1"`
+	ResultTime *int32          `json:"result_time" binding:"omitempty,min=1"`
+}
+
+func (r *updateRaceRequest) toDB(organizationID, id int64) (db.UpdateRaceParams, error) {
+	date, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return db.UpdateRaceParams{}, err
+	}
+
+	arg := db.UpdateRaceParams{
+		OrganizationID: organizationID,
+		ID:             id,
+		Name:           r.Name,
+		Date:           date,
+		Sport:          r.Sport,
+		Distance:       r.Distance,
+		Priority:       r.Priority,
+	}
+	if r.GoalTime != nil {
+		arg.GoalTime.SetValid(*r.GoalTime)
+	}
+	if r.ResultTime != nil {
+		arg.ResultTime.SetValid(*r.ResultTime)
+	}
+
+	return arg, nil
+}
+
+func (server *Server) listRacesByUser(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	races, err := server.store.ListRacesByUser(ctx, db.ListRacesByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, races)
+}
+
+func (server *Server) getRace(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	race, ok := server.loadRace(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, race)
+}
+
+func (server *Server) createRace(ctx *gin.Context) {
+	var req createRaceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.loadSport(ctx, req.Sport); !ok {
+		return
+	}
+
+	if _, ok := server.loadUser(ctx, req.UserID); !ok {
+		return
+	}
+
+	arg, err := req.toDB(tenantID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var race db.Race
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		race, err = store.CreateRace(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityRace, race.ID, nil, race)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, race)
+}
+
+func (server *Server) updateRace(ctx *gin.Context) {
+	var r idRequest
+	if err := ctx.ShouldBindUri(&r); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req updateRaceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.loadSport(ctx, req.Sport); !ok {
+		return
+	}
+
+	race, ok := server.loadRace(ctx, r.ID)
+	if !ok {
+		return
+	}
+
+	arg, err := req.toDB(race.OrganizationID, race.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var updated db.Race
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateRace(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityRace, race.ID, race, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (server *Server) deleteRace(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	race, ok := server.loadRace(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteRace(ctx, db.DeleteRaceParams{OrganizationID: race.OrganizationID, ID: race.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityRace, race.ID, race, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+type predictionRequest struct {
+	Distance int32 `form:"distance" binding:"omitempty,min=1"`
+}
+
+type racePrediction struct {
+	Distance   int32 `json:"distance"`
+	RiegelTime int32 `json:"riegel_time"`
+	VdotTime   int32 `json:"vdot_time"`
+}
+
+type predictionsResponse struct {
+	BasedOn     db.Race          `json:"based_on"`
+	Vdot        float64          `json:"vdot"`
+	Predictions []racePrediction `json:"predictions"`
+}
+
+// getPredictions predicts running race times from the best of the recent race results of an athlete
+func (server *Server) getPredictions(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req predictionRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	results, err := server.store.ListRaceResultsByUser(ctx, db.ListRaceResultsByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		Sport:          sports.Running,
+		Limit:          recentResults,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if len(results) == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("race.no_results")))
+		return
+	}
+
+	var rsp predictionsResponse
+	var best float64
+	for _, race := range results {
+		vdot := calc.VDOT(float64(race.Distance), float64(race.ResultTime.Int32))
+		if vdot > best {
+			rsp.BasedOn = race
+			best = vdot
+		}
+	}
+	rsp.Vdot = math.Round(best*10) / 10
+
+	distances := predictionDistances
+	if req.Distance > 0 {
+		distances = []int32{req.Distance}
+	} else {
+		// Include the distances of the upcoming races
+		upcoming, err := server.store.ListRacesByUserFrom(ctx, db.ListRacesByUserFromParams{
+			OrganizationID: user.OrganizationID,
+			UserID:         user.ID,
+			FromDate:       userToday(user),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		distances = raceDistances(distances, upcoming)
+	}
+
+	based := rsp.BasedOn
+	rsp.Predictions = make([]racePrediction, len(distances))
+	for i, d := range distances {
+		rsp.Predictions[i] = racePrediction{
+			Distance:   d,
+			RiegelTime: int32(math.Round(calc.Riegel(float64(based.Distance), float64(based.ResultTime.Int32), float64(d)))),
+			VdotTime:   int32(math.Round(calc.PredictTime(best, float64(d)))),
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// raceDistances adds the distances of the running races to the given ones, sorted and without duplicates
+func raceDistances(distances []int32, races []db.Race) []int32 {
+	seen := make(map[int32]bool)
+	out := make([]int32, 0, len(distances)+len(races))
+	add := func(d int32) {
+		if !seen[d] {
+			seen[d] = true
+			out = append(out, d)
+		}
+	}
+	for _, d := range distances {
+		add(d)
+	}
+	for _, race := range races {
+		if race.Sport == sports.Running {
+			add(race.Distance)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// trainingResponse is a training along with the next race and the readiness of the athlete on that day.
+// InjuryID flags a planned training falling inside an active injury period.
+type trainingResponse struct {
+	db.Training
+	RaceID     null.Int64 `json:"race_id"`
+	DaysToRace null.Int64 `json:"days_to_race"`
+	Readiness  null.Int64 `json:"readiness"`
+	InjuryID   null.Int64 `json:"injury_id"`
+	// Legs are the ones of a multisport training
+	Legs []db.TrainingLeg `json:"legs,omitempty"`
+}
+
+// withDaysToRace pairs each training with the first race of the athlete on or after the training date
+func (server *Server) withDaysToRace(ctx *gin.Context, trainings []db.Training) ([]trainingResponse, error) {
+	rsp := make([]trainingResponse, len(trainings))
+	if len(trainings) == 0 {
+		return rsp, nil
+	}
+
+	from := trainings[0].Date
+	for _, t := range trainings {
+		if t.Date.Before(from) {
+			from = t.Date
+		}
+	}
+
+	races, err := server.store.ListRacesByUserFrom(ctx, db.ListRacesByUserFromParams{
+		OrganizationID: trainings[0].OrganizationID,
+		UserID:         trainings[0].UserID,
+		FromDate:       from,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, t := range trainings {
+		rsp[i].Training = t
+
+		// races are sorted by date
+		j := sort.Search(len(races), func(j int) bool { return !races[j].Date.Before(t.Date) })
+		if j < len(races) {
+			rsp[i].RaceID.SetValid(races[j].ID)
+			rsp[i].DaysToRace.SetValid(int64(races[j].Date.Sub(t.Date).Hours() / 24))
+		}
+	}
+
+	return rsp, nil
+}
+
+// loadRace gets a race of the current organization, writing the error response if it doesn't exist
+func (server *Server) loadRace(ctx *gin.Context, id int64) (db.Race, bool) {
+	race, err := server.store.GetRace(ctx, db.GetRaceParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return race, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return race, false
+	}
+
+	return race, true
+}
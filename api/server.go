@@ -1,15 +1,27 @@
 package api
 
 import (
+	"reflect"
+	"strings"
+
 	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/fitness"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/rrule"
+	"github.com/rondondev/runapp/sports"
+	"github.com/rondondev/runapp/util"
+	"github.com/rondondev/runapp/zones"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 
 )
 
 // Server serves HTTP requests
 type Server struct {
-	store db.Querier
+	config util.Config
+	store  db.Store
 	router *gin.Engine
 }
 
@@ -18,9 +30,22 @@ type idRequest struct {
 }
 
 // NewServer creates a new HTTP server and setup routing
-func NewServer(store db.Querier) *Server {
-	server := &Server{store: store}
+func NewServer(config util.Config, store db.Store) *Server {
+	server := &Server{config: config, store: store}
+	registerFieldNames()
+	registerErrorKeys()
 	router := gin.Default()
+	router.Use(requestID(), server.authenticate())
+
+	// Organization
+	router.GET("/organization", server.getOrganization)
+	router.PUT("/organization", adminOnly(), server.updateOrganization)
+
+	// Sports
+	router.GET("/sports", server.listSports)
+	router.POST("/sport", adminOnly(), server.createSport)
+	router.PUT("/sport/:id", adminOnly(), server.updateSport)
+	router.DELETE("/sport/:id", adminOnly(), server.deleteSport)
 
 	// Users
 	router.GET("/users", server.listUsers)
@@ -29,7 +54,36 @@ func NewServer(store db.Querier) *Server {
 	router.GET("/user/:id", server.getUser)
 	router.POST("/user", server.createUser)
 	router.PUT("/user/:id", server.updateUser)
+	router.PATCH("/user/:id", server.patchUser)
 	router.DELETE("/user/:id", server.deleteUser)
+	router.GET("/user/:id/export", server.exportUser)
+	router.POST("/user/:id/erase", adminOnly(), server.eraseUser)
+	router.POST("/user/:id/token", adminOnly(), server.createUserToken)
+	router.DELETE("/user/:id/tokens", adminOnly(), server.revokeUserTokens)
+	router.GET("/user/:id/zones", server.listZoneModels)
+	router.POST("/user/:id/zones", server.createZoneModel)
+	router.DELETE("/user/:id/zone/:zone_model_id", server.deleteZoneModel)
+	router.GET("/user/:id/tests", server.listTestResultsByUser)
+	router.GET("/user/:id/races", server.listRacesByUser)
+	router.GET("/user/:id/predictions", server.getPredictions)
+	router.GET("/user/:id/pmc", server.getPMC)
+	router.GET("/user/:id/summary", server.getUserSummary)
+	router.GET("/user/:id/wellness", server.listWellness)
+	router.POST("/user/:id/wellness", server.createWellness)
+	router.GET("/user/:id/wellness/:date", server.getWellness)
+	router.PUT("/user/:id/wellness/:date", server.updateWellness)
+	router.DELETE("/user/:id/wellness/:date", server.deleteWellness)
+	router.GET("/user/:id/injuries", server.listInjuriesByUser)
+	router.GET("/user/:id/availability", server.listAvailability)
+	router.POST("/user/:id/availability", server.createAvailability)
+	router.DELETE("/user/:id/availability/:availability_id", server.deleteAvailability)
+	router.GET("/user/:id/blackouts", server.listBlackouts)
+	router.POST("/user/:id/blackouts", server.createBlackout)
+	router.DELETE("/user/:id/blackout/:blackout_id", server.deleteBlackout)
+	router.GET("/user/:id/conflicts", server.listConflicts)
+	router.GET("/user/:id/equipment", server.listEquipmentByUser)
+	router.GET("/user/:id/equipment/alerts", server.listEquipmentAlerts)
+	router.GET("/user/:id/strength", server.getStrength)
 
 	// User trainings
 	router.GET("/trainings/feedback/user/:id", server.listTrainingFeedbacksByUser)
@@ -38,17 +92,99 @@ func NewServer(store db.Querier) *Server {
 	router.GET("/training/:id/feedback", server.getTrainingFeedback)
 	router.POST("/training/:id/feedback", server.createTrainingFeedback)
 	router.PUT("/training/:id/feedback", server.updateTrainingFeedback)
+	router.PATCH("/training/:id/feedback", server.patchTrainingFeedback)
 	router.DELETE("/training/:id/feedback", server.deleteTrainingFeedback)
+	router.GET("/training/:id/test", server.getTestResult)
+	router.POST("/training/:id/test", server.createTestResult)
+	router.DELETE("/training/:id/test", server.deleteTestResult)
 
 	// Training feedbacks
 	router.GET("/trainings/user/:id", server.listTrainingsByUser)
+	router.GET("/trainings/user/:id/export", server.exportTrainings)
+	router.POST("/trainings/import", server.importTrainings)
+	router.POST("/trainings/copy", server.copyTrainings)
+	router.POST("/trainings/shift", server.shiftTrainings)
+
+	// Training series
+	router.GET("/trainings/series/:id", server.getTrainingSeries)
+	router.POST("/trainings/series", server.createTrainingSeries)
 
 	// Training
 	router.GET("/training/:id", server.getTraining)
 	router.POST("/training", server.createTraining)
 	router.PUT("/training/:id", server.updateTraining)
+	router.PATCH("/training/:id", server.patchTraining)
 	router.DELETE("/training/:id", server.deleteTraining)
-
+	router.GET("/training/:id/targets", server.getTrainingTargets)
+	router.GET("/training/:id/equipment", server.listTrainingEquipment)
+	router.POST("/training/:id/equipment", server.addTrainingEquipment)
+	router.DELETE("/training/:id/equipment/:equipment_id", server.removeTrainingEquipment)
+	router.GET("/training/:id/exercises", server.listTrainingExercises)
+	router.PUT("/training/:id/exercises", server.setTrainingExercises)
+	router.GET("/training/:id/sets", server.listTrainingSets)
+	router.POST("/training/:id/sets", server.createTrainingSet)
+	router.DELETE("/training/:id/set/:set_id", server.deleteTrainingSet)
+
+	// Races
+	router.GET("/race/:id", server.getRace)
+	router.POST("/race", server.createRace)
+	router.PUT("/race/:id", server.updateRace)
+	router.DELETE("/race/:id", server.deleteRace)
+
+	// Injuries
+	router.GET("/injury/:id", server.getInjury)
+	router.POST("/injury", server.createInjury)
+	router.PUT("/injury/:id", server.updateInjury)
+	router.DELETE("/injury/:id", server.deleteInjury)
+	router.GET("/injury/:id/trainings", server.listInjuryTrainings)
+	router.POST("/injury/:id/pause", server.pausePlan)
+
+	// Equipment
+	router.GET("/equipment/:id", server.getEquipment)
+	router.POST("/equipment", server.createEquipment)
+	router.PUT("/equipment/:id", server.updateEquipment)
+	router.DELETE("/equipment/:id", server.deleteEquipment)
+
+	// Exercises
+	router.GET("/exercises", server.listExercises)
+	router.POST("/exercise", server.createExercise)
+	router.PUT("/exercise/:id", server.updateExercise)
+	router.DELETE("/exercise/:id", server.deleteExercise)
+
+	// Groups
+	router.GET("/groups", server.listGroups)
+	router.GET("/group/:id", server.getGroup)
+	router.POST("/group", server.createGroup)
+	router.PUT("/group/:id", server.updateGroup)
+	router.DELETE("/group/:id", server.deleteGroup)
+	router.GET("/group/:id/members", server.listGroupMembers)
+	router.POST("/group/:id/members", server.addGroupMember)
+	router.DELETE("/group/:id/member/:user_id", server.removeGroupMember)
+	router.GET("/group/:id/trainings", server.listGroupTrainings)
+	router.GET("/group/:id/training/:training_id", server.getGroupTraining)
+	router.PUT("/group/:id/training/:training_id", server.updateGroupTraining)
+	router.DELETE("/group/:id/training/:training_id", server.deleteGroupTraining)
+
+	// Summaries
+	router.GET("/summary", server.getRosterSummary)
+
+	// Tools
+	tools := router.Group("/tools")
+	tools.GET("/pace", server.paceTool)
+	tools.GET("/vdot", server.vdotTool)
+	tools.GET("/swim", server.swimTool)
+	tools.GET("/cycling", server.cyclingTool)
+
+	// Audit
+	router.GET("/audit", adminOnly(), server.listAuditLogs)
+
+	// Trash
+	trash := router.Group("/trash", adminOnly())
+	trash.GET("/users", server.listDeletedUsers)
+	trash.GET("/trainings", server.listDeletedTrainings)
+	trash.POST("/user/:id/restore", server.restoreUser)
+	trash.POST("/training/:id/restore", server.restoreTraining)
+	trash.DELETE("", server.purgeTrash)
 
 	server.router = router
 
@@ -60,6 +196,38 @@ func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// errorResponse returns the message of the error in the language of the request
+func errorResponse(ctx *gin.Context, err error) gin.H {
+	return gin.H{"error": i18n.Translate(language(ctx), err)}
+}
+
+// registerErrorKeys gives the errors of the domain packages the messages of the catalogs, so they are
+// translated like the API ones
+func registerErrorKeys() {
+	i18n.RegisterError(sports.ErrInvalidSchema, "sport.invalid_metrics")
+	i18n.RegisterError(sports.ErrInvalidAttributes, "sport.invalid_attributes")
+	i18n.RegisterError(rrule.ErrInvalidRule, "series.invalid_rule")
+	i18n.RegisterError(rrule.ErrInvalidDate, "series.invalid_date")
+	i18n.RegisterError(rrule.ErrTooManyOccurrences, "series.too_many_dates")
+	i18n.RegisterError(rrule.ErrShiftOrdinal, "series.shift_ordinal")
+	i18n.RegisterError(zones.ErrInvalidModel, "zone.invalid_model")
+	i18n.RegisterError(zones.ErrInvalidRef, "zone.invalid_ref")
+	i18n.RegisterError(fitness.ErrInvalidInput, "fitness.invalid_input")
+}
+
+// registerFieldNames makes the validation errors name the fields as they are sent instead of by their Go name
+func registerFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
 }
\ No newline at end of file
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntitySport = "sport"
+
+type sportRequest struct {
+	Name    string          `json:"name" binding:"required"`
+	Metrics []sports.Metric `json:"metrics" binding:"dive"`
+}
+
+// metrics validates the metric schema and encodes it for the metrics column
+func (r *sportRequest) metrics() (json.RawMessage, error) {
+	if r.Metrics == nil {
+		r.Metrics = []sports.Metric{}
+	}
+	if err := sports.ValidateSchema(r.Metrics); err != nil {
+		return nil, err
+	}
+	return json.Marshal(r.Metrics)
+}
+
+type createSportRequest struct {
+	Slug string `json:"slug" binding:"required,max=32"`
+	sportRequest
+}
+
+func (server *Server) listSports(ctx *gin.Context) {
+	catalogue, err := server.store.ListSports(ctx, tenantID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, catalogue)
+}
+
+func (server *Server) createSport(ctx *gin.Context) {
+	var req createSportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if !sports.ValidSlug(req.Slug) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("sport.invalid_slug")))
+		return
+	}
+	metrics, err := req.metrics()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	_, err = server.store.GetSportBySlug(ctx, db.GetSportBySlugParams{OrganizationID: tenantID(ctx), Slug: req.Slug})
+	if err == nil {
+		ctx.JSON(http.StatusConflict, errorResponse(ctx, i18n.Errorf("sport.exists", req.Slug)))
+		return
+	}
+	if err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	var sport db.Sport
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		sport, err = store.CreateSport(ctx, db.CreateSportParams{
+			OrganizationID: null.NewInt64(tenantID(ctx), true),
+			Slug:           req.Slug,
+			Name:           req.Name,
+			Metrics:        metrics,
+		})
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntitySport, sport.ID, nil, sport)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sport)
+}
+
+// updateSport changes the name and metrics of a sport of the organization, the slug can't change
+// because the trainings refer to it and the built-in sports are read-only
+func (server *Server) updateSport(ctx *gin.Context) {
+	var r idRequest
+	if err := ctx.ShouldBindUri(&r); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req sportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	metrics, err := req.metrics()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	sport, ok := server.loadOwnSport(ctx, r.ID)
+	if !ok {
+		return
+	}
+
+	var updated db.Sport
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateSport(ctx, db.UpdateSportParams{
+			OrganizationID: sport.OrganizationID,
+			ID:             sport.ID,
+			Name:           req.Name,
+			Metrics:        metrics,
+		})
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntitySport, sport.ID, sport, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// deleteSport removes a sport from the catalogue, the trainings already planned keep it
+func (server *Server) deleteSport(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	sport, ok := server.loadOwnSport(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteSport(ctx, db.DeleteSportParams{OrganizationID: sport.OrganizationID, ID: sport.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntitySport, sport.ID, sport, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// loadOwnSport gets a sport added by the current organization, writing the error response if it doesn't
+// exist or is built-in
+func (server *Server) loadOwnSport(ctx *gin.Context, id int64) (db.Sport, bool) {
+	sport, err := server.store.GetSport(ctx, db.GetSportParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return sport, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return sport, false
+	}
+	if !sport.OrganizationID.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, i18n.Errorf("sport.built_in")))
+		return sport, false
+	}
+
+	return sport, true
+}
+
+// loadSport gets a sport of the catalogue by its slug, writing a bad request response if it isn't there
+func (server *Server) loadSport(ctx *gin.Context, slug string) (db.Sport, bool) {
+	sport, err := server.store.GetSportBySlug(ctx, db.GetSportBySlugParams{OrganizationID: tenantID(ctx), Slug: slug})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("sport.unknown", slug)))
+			return sport, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return sport, false
+	}
+
+	return sport, true
+}
+
+// checkTrainingSport checks that the sport of a training is in the catalogue and that its attributes match
+// the metrics of the sport, writing the error response if they don't
+func (server *Server) checkTrainingSport(ctx *gin.Context, slug string, attributes map[string]interface{}) bool {
+	sport, ok := server.loadSport(ctx, slug)
+	if !ok {
+		return false
+	}
+
+	if err := validateAttributes(sport, attributes); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return false
+	}
+
+	return true
+}
+
+func validateAttributes(sport db.Sport, attributes map[string]interface{}) error {
+	var metrics []sports.Metric
+	if err := json.Unmarshal(sport.Metrics, &metrics); err != nil {
+		return err
+	}
+	return sports.Validate(metrics, attributes)
+}
+
+// sportCatalogue returns the sports available to the current organization by slug
+func (server *Server) sportCatalogue(ctx *gin.Context) (map[string]db.Sport, error) {
+	catalogue, err := server.store.ListSports(ctx, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	bySlug := make(map[string]db.Sport, len(catalogue))
+	for _, s := range catalogue {
+		bySlug[s.Slug] = s
+	}
+	return bySlug, nil
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rondondev/runapp/calc"
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	summaryPeriodWeek  = "week"
+	summaryPeriodMonth = "month"
+	// summaryDefaultPeriods is how many periods are shown when no start date is given
+	summaryDefaultPeriods = 4
+	// summaryMaxDays is the longest range that can be requested
+	summaryMaxDays = 2 * 366
+)
+
+type summaryRequest struct {
+	Period    string `form:"period" binding:"omitempty,oneof=week month"`
+	StartDate string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	// Timezone is the IANA name of the zone used to know which day is today, by default the one of the
+	// athlete or UTC for a roster. The trainings due of each athlete always follow their own timezone.
+	Timezone string `form:"tz"`
+}
+
+type rosterSummaryRequest struct {
+	summaryRequest
+	GroupID int64 `form:"group_id" binding:"omitempty,min=1"`
+}
+
+// summaryRange is the validated range of a summary, aligned to whole periods
+type summaryRange struct {
+	period string
+	start  time.Time
+	end    time.Time
+	today  time.Time
+}
+
+func (r *summaryRequest) toRange(timezone string) (summaryRange, error) {
+	rng := summaryRange{period: r.Period}
+	if rng.period == "" {
+		rng.period = summaryPeriodWeek
+	}
+
+	if r.Timezone != "" {
+		timezone = r.Timezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return rng, i18n.Errorf("request.invalid_tz", err)
+	}
+	now := time.Now().In(loc)
+	rng.today = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	// we can ignore the errors because the values were already validated
+	end := rng.today
+	if r.EndDate != "" {
+		end, _ = time.Parse("2006-01-02", r.EndDate)
+	}
+	rng.end = periodEnd(rng.period, end)
+
+	if r.StartDate != "" {
+		start, _ := time.Parse("2006-01-02", r.StartDate)
+		rng.start = periodStart(rng.period, start)
+	} else {
+		rng.start = periodStart(rng.period, end)
+		for i := 1; i < summaryDefaultPeriods; i++ {
+			rng.start = periodStart(rng.period, rng.start.AddDate(0, 0, -1))
+		}
+	}
+
+	if rng.end.Before(rng.start) {
+		return rng, i18n.Errorf("request.end_before_start")
+	}
+	if rng.end.Sub(rng.start).Hours()/24 >= summaryMaxDays {
+		return rng, i18n.Errorf("request.period_too_long")
+	}
+
+	return rng, nil
+}
+
+// periodStart returns the first day of the ISO week or month of the date
+func periodStart(period string, date time.Time) time.Time {
+	if period == summaryPeriodMonth {
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	// ISO weeks start on Monday
+	offset := (int(date.Weekday()) + 6) % 7
+	return time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// periodEnd returns the last day of the ISO week or month of the date
+func periodEnd(period string, date time.Time) time.Time {
+	start := periodStart(period, date)
+	if period == summaryPeriodMonth {
+		return start.AddDate(0, 1, -1)
+	}
+	return start.AddDate(0, 0, 6)
+}
+
+// periodName returns the ISO week like "2021-W05" or the month like "2021-02" of the period start
+func periodName(period string, start time.Time) string {
+	if period == summaryPeriodMonth {
+		return start.Format("2006-01")
+	}
+	year, week := start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+type summaryStats struct {
+	Planned   int64 `json:"planned"`
+	Completed int64 `json:"completed"`
+	Overdue   int64 `json:"overdue"`
+	// PlannedDuration and ActualDuration are in seconds, ActualDistance in meters
+	PlannedDuration int64 `json:"planned_duration"`
+	ActualDuration  int64 `json:"actual_duration"`
+	ActualDistance  int64 `json:"actual_distance"`
+	// Distance is the actual distance in the unit of the athlete, km or mi
+	Distance     float64      `json:"distance"`
+	DistanceUnit string       `json:"distance_unit"`
+	AvgBorg      null.Float64 `json:"avg_borg"`
+	// Compliance is the percentage of the trainings due by today that were completed
+	Compliance null.Float64 `json:"compliance"`
+
+	due       int64
+	borgSum   int64
+	feedbacks int64
+}
+
+func (s *summaryStats) add(row db.ListTrainingSummariesRow) {
+	s.Planned += row.Planned
+	s.Completed += row.Completed
+	s.Overdue += row.Overdue
+	s.PlannedDuration += row.PlannedDuration
+	s.ActualDuration += row.ActualDuration
+	s.ActualDistance += row.ActualDistance
+	s.due += row.Due
+	s.borgSum += row.BorgSum
+	s.feedbacks += row.Feedbacks
+
+	if s.feedbacks > 0 {
+		s.AvgBorg.SetValid(calc.Round(float64(s.borgSum)/float64(s.feedbacks), 1))
+	}
+	if s.due > 0 {
+		s.Compliance.SetValid(calc.Round(float64(s.Completed)/float64(s.due)*100, 1))
+	}
+}
+
+// format sets the distance in the unit system of the athlete
+func (s *summaryStats) format(units db.UnitSystem) {
+	if units == db.UnitSystemImperial {
+		s.Distance = calc.Round(float64(s.ActualDistance)/calc.Mile, 2)
+		s.DistanceUnit = "mi"
+		return
+	}
+	s.Distance = calc.Round(float64(s.ActualDistance)/1000, 2)
+	s.DistanceUnit = "km"
+}
+
+type sportSummary struct {
+	Sport string `json:"sport"`
+	summaryStats
+}
+
+type periodSummary struct {
+	Period    string         `json:"period"`
+	StartDate string         `json:"start_date"`
+	EndDate   string         `json:"end_date"`
+	Total     summaryStats   `json:"total"`
+	Sports    []sportSummary `json:"sports"`
+}
+
+type athleteSummary struct {
+	UserID   int64           `json:"user_id"`
+	Name     string          `json:"name"`
+	Timezone string          `json:"timezone"`
+	Units    db.UnitSystem   `json:"units"`
+	Periods  []periodSummary `json:"periods"`
+}
+
+// getUserSummary returns the training summary of an athlete by week or month
+func (server *Server) getUserSummary(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req summaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	rng, err := req.toRange(user.Timezone)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	summaries, err := server.summaries(ctx, rng, []db.User{user})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summaries[0])
+}
+
+// getRosterSummary returns the training summaries of every active athlete, or of the members of a group
+func (server *Server) getRosterSummary(ctx *gin.Context) {
+	var req rosterSummaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	rng, err := req.toRange(defaultTimezone)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var users []db.User
+	if req.GroupID > 0 {
+		group, ok := server.loadGroup(ctx, req.GroupID)
+		if !ok {
+			return
+		}
+		users, err = server.store.ListGroupMembers(ctx, db.ListGroupMembersParams{
+			OrganizationID: group.OrganizationID,
+			GroupID:        group.ID,
+		})
+	} else {
+		users, err = server.store.ListActiveAthletes(ctx, tenantID(ctx))
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	summaries, err := server.summaries(ctx, rng, users)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summaries)
+}
+
+// summaries rolls up the trainings of the users, including every period of the range even when empty
+func (server *Server) summaries(ctx *gin.Context, rng summaryRange, users []db.User) ([]athleteSummary, error) {
+	ids := make([]int64, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+
+	rows, err := server.store.ListTrainingSummaries(ctx, db.ListTrainingSummariesParams{
+		OrganizationID: tenantID(ctx),
+		Period:         rng.period,
+		Now:            time.Now(),
+		UserIds:        ids,
+		StartDate:      rng.start,
+		EndDate:        rng.end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the rows are sorted by user, period and sport
+	byUser := make(map[int64][]db.ListTrainingSummariesRow)
+	for _, row := range rows {
+		byUser[row.UserID] = append(byUser[row.UserID], row)
+	}
+
+	summaries := make([]athleteSummary, len(users))
+	for i, user := range users {
+		summaries[i] = athleteSummary{
+			UserID:   user.ID,
+			Name:     user.Name,
+			Timezone: user.Timezone,
+			Units:    user.Units,
+			Periods:  []periodSummary{},
+		}
+		userRows := byUser[user.ID]
+		for start := rng.start; !start.After(rng.end); start = periodEnd(rng.period, start).AddDate(0, 0, 1) {
+			p := periodSummary{
+				Period:    periodName(rng.period, start),
+				StartDate: start.Format("2006-01-02"),
+				EndDate:   periodEnd(rng.period, start).Format("2006-01-02"),
+				Sports:    []sportSummary{},
+			}
+			for len(userRows) > 0 && userRows[0].PeriodStart.Format("2006-01-02") == p.StartDate {
+				row := userRows[0]
+				userRows = userRows[1:]
+
+				sport := sportSummary{Sport: row.Sport}
+				sport.add(row)
+				sport.format(user.Units)
+				p.Sports = append(p.Sports, sport)
+				p.Total.add(row)
+			}
+			p.Total.format(user.Units)
+			summaries[i].Periods = append(summaries[i].Periods, p)
+		}
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityApiToken = "api_token"
+
+// tokenBytes is the length of the random tokens before being hex encoded
+const tokenBytes = 32
+
+// tokenResponse is an issued token, the token itself is only known when it is issued
+type tokenResponse struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Token     string    `json:"token,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IssueToken creates a new API token for a user. Only its hash is stored, the token returned is the one
+// to send in the Authorization header.
+func IssueToken(ctx context.Context, q db.Querier, organizationID, userID int64) (db.ApiToken, string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return db.ApiToken{}, "", err
+	}
+	token := hex.EncodeToString(b)
+
+	apiToken, err := q.CreateApiToken(ctx, db.CreateApiTokenParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		TokenHash:      hashToken(token),
+	})
+	return apiToken, token, err
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// createUserToken issues a token for a user of the organization
+func (server *Server) createUserToken(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	var rsp tokenResponse
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		apiToken, token, err := IssueToken(ctx, store, user.OrganizationID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		rsp = tokenResponse{ID: apiToken.ID, UserID: apiToken.UserID, CreatedAt: apiToken.CreatedAt}
+		// the snapshot must not hold the token
+		entry := auditEntry(auditActionCreate, auditEntityApiToken, apiToken.ID, nil, rsp)
+		rsp.Token = token
+		return []db.AuditEntry{entry}, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// revokeUserTokens revokes every token of a user, the requests made with them are rejected from then on
+func (server *Server) revokeUserTokens(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		ids, err := store.RevokeUserApiTokens(ctx, db.RevokeUserApiTokensParams{
+			OrganizationID: user.OrganizationID,
+			UserID:         user.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]db.AuditEntry, 0, len(ids))
+		for _, id := range ids {
+			entries = append(entries, auditEntry(auditActionDelete, auditEntityApiToken, id, gin.H{"user_id": user.ID}, nil))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/rondondev/runapp/calc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type paceToolRequest struct {
+	Distance float64 `form:"distance" binding:"omitempty,gt=0"`
+	Time     float64 `form:"time" binding:"omitempty,gt=0"`
+	Pace     float64 `form:"pace" binding:"omitempty,gt=0"`
+}
+
+type paceToolResponse struct {
+	Distance    float64 `json:"distance"`
+	Time        float64 `json:"time"`
+	PacePerKm   float64 `json:"pace_per_km"`
+	PacePerMile float64 `json:"pace_per_mile"`
+	SpeedKmh    float64 `json:"speed_kmh"`
+	SpeedMph    float64 `json:"speed_mph"`
+}
+
+// paceTool converts between pace, speed and finish time given two of distance, time and pace.
+// Distances are in meters, times in seconds and the pace in seconds per km.
+func (server *Server) paceTool(ctx *gin.Context) {
+	var req paceToolRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var speed float64
+	switch {
+	case req.Distance > 0 && req.Time > 0:
+		speed = calc.Speed(req.Distance, req.Time)
+	case req.Distance > 0 && req.Pace > 0:
+		speed = calc.SpeedFromPace(req.Pace)
+		req.Time = calc.FinishTime(req.Distance, speed)
+	case req.Time > 0 && req.Pace > 0:
+		speed = calc.SpeedFromPace(req.Pace)
+		req.Distance = speed * req.Time
+	default:
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("tools.two_required")))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, paceToolResponse{
+		Distance:    calc.Round(req.Distance, 1),
+		Time:        calc.Round(req.Time, 1),
+		PacePerKm:   calc.Round(calc.PacePerKm(speed), 1),
+		PacePerMile: calc.Round(calc.PacePerMile(speed), 1),
+		SpeedKmh:    calc.Round(calc.Kmh(speed), 2),
+		SpeedMph:    calc.Round(calc.Mph(speed), 2),
+	})
+}
+
+type raceResultRequest struct {
+	Distance float64 `form:"distance" binding:"required,gt=0"`
+	Time     float64 `form:"time" binding:"required,gt=0"`
+}
+
+type vdotToolResponse struct {
+	Vdot  float64            `json:"vdot"`
+	Paces calc.TrainingPaces `json:"paces"`
+}
+
+// vdotTool returns the VDOT of a race result along with its training paces
+func (server *Server) vdotTool(ctx *gin.Context) {
+	var req raceResultRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	vdot := calc.VDOT(req.Distance, req.Time)
+	if vdot <= 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("tools.too_slow")))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, vdotToolResponse{
+		Vdot:  calc.Round(vdot, 1),
+		Paces: calc.Paces(vdot),
+	})
+}
+
+type swimToolResponse struct {
+	PacePer100m  float64 `json:"pace_per_100m"`
+	PacePer100yd float64 `json:"pace_per_100yd"`
+}
+
+// swimTool returns the swim paces of a distance in meters swum in the given seconds
+func (server *Server) swimTool(ctx *gin.Context) {
+	var req raceResultRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	per100m, per100yd := calc.SwimPace(req.Distance, req.Time)
+	ctx.JSON(http.StatusOK, swimToolResponse{
+		PacePer100m:  calc.Round(per100m, 1),
+		PacePer100yd: calc.Round(per100yd, 1),
+	})
+}
+
+type cyclingToolRequest struct {
+	Power float64  `form:"power" binding:"required,gt=0"`
+	Mass  *float64 `form:"mass" binding:"omitempty,gt=0"`
+	CdA   *float64 `form:"cda" binding:"omitempty,gt=0"`
+	Crr   *float64 `form:"crr" binding:"omitempty,gt=0"`
+	Grade float64  `form:"grade" binding:"min=-0.3,max=0.3"`
+}
+
+type cyclingToolResponse struct {
+	SpeedKmh float64 `json:"speed_kmh"`
+	SpeedMph float64 `json:"speed_mph"`
+}
+
+// cyclingTool estimates the speed held with a power, the grade being the road slope (0.05 for 5%)
+func (server *Server) cyclingTool(ctx *gin.Context) {
+	var req cyclingToolRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	rider := calc.Rider{Mass: calc.DefaultMass, CdA: calc.DefaultCdA, Crr: calc.DefaultCrr, Grade: req.Grade}
+	if req.Mass != nil {
+		rider.Mass = *req.Mass
+	}
+	if req.CdA != nil {
+		rider.CdA = *req.CdA
+	}
+	if req.Crr != nil {
+		rider.Crr = *req.Crr
+	}
+
+	speed := calc.CyclingSpeed(rider, req.Power)
+	ctx.JSON(http.StatusOK, cyclingToolResponse{
+		SpeedKmh: calc.Round(calc.Kmh(speed), 1),
+		SpeedMph: calc.Round(calc.Mph(speed), 1),
+	})
+}
@@ -2,11 +2,14 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"time"
 
 	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,7 +22,7 @@ type listTrainingRequest struct {
 func (server *Server) listTrainingsByUser(ctx *gin.Context) {
 	var u idRequest
 	if err := ctx.ShouldBindUri(&u); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -30,7 +33,7 @@ func (server *Server) listTrainingsByUser(ctx *gin.Context) {
 	if start == "" && end == "" {
 		period = false
 	} else if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -41,65 +44,138 @@ func (server *Server) listTrainingsByUser(ctx *gin.Context) {
 		start, _ := time.Parse("2006-01-02", req.StartDate)
 		end, _ := time.Parse("2006-01-02", req.EndDate)
 		arg := db.ListTrainingsByUserInPeriodParams{
-			UserID: u.ID,
-			Date:   start,
-			Date_2: end,
+			OrganizationID: tenantID(ctx),
+			UserID:         u.ID,
+			Date:           start,
+			Date_2:         end,
 		}
 
 		trainings, err = server.store.ListTrainingsByUserInPeriod(ctx, arg)
 	} else {
-		trainings, err = server.store.ListTrainingsByUser(ctx, u.ID)
+		trainings, err = server.store.ListTrainingsByUser(ctx, db.ListTrainingsByUserParams{
+			OrganizationID: tenantID(ctx),
+			UserID:         u.ID,
+		})
 	}
 
+	if err == nil {
+		err = server.markOverdue(ctx, trainings)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp, err := server.withDaysToRace(ctx, trainings)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err = server.withReadiness(ctx, rsp); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err = server.withInjuries(ctx, rsp); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err = server.withLegs(ctx, rsp); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, trainings)
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 func (server *Server) getTraining(ctx *gin.Context) {
 	var req idRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	training, err := server.store.GetTraining(ctx, req.ID)
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: req.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, training)
+	legs, err := server.listLegs(ctx, training)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	trainings := []db.Training{training}
+	if err = server.markOverdue(ctx, trainings); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	setETag(ctx, training.Version)
+	ctx.JSON(http.StatusOK, trainingLegsResponse{Training: trainings[0], Legs: legs})
+}
+
+// markOverdue sets the status of the trainings still to be done whose date is past in the timezone of their
+// athlete to overdue, as the status stored is the one last set by the coach or the athlete
+func (server *Server) markOverdue(ctx *gin.Context, trainings []db.Training) error {
+	today := map[int64]time.Time{}
+	for i, t := range trainings {
+		if t.Status != db.TrainingStatusNew && t.Status != db.TrainingStatusNotified {
+			continue
+		}
+
+		day, ok := today[t.UserID]
+		if !ok {
+			user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: t.OrganizationID, ID: t.UserID})
+			if err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			// the trainings of a deleted user are taken as in UTC
+			day = userToday(user)
+			today[t.UserID] = day
+		}
+
+		if t.Date.Before(day) {
+			trainings[i].Status = db.TrainingStatusOverdue
+		}
+	}
+
+	return nil
 }
 
 type createTrainingRequest struct {
-	UserID    int64             `json:"user_id" binding:"required"`
+	UserID    int64             `json:"user_id" binding:"required_without=GroupID"`
+	GroupID   int64             `json:"group_id" binding:"omitempty,min=1"`
 	Date      string            `json:"date" binding:"required,datetime=2006-01-02"`
-	Sport     db.TrainingSport  `json:"sport" binding:"required"`
+	Sport     string            `json:"sport" binding:"required"`
 	Type      *string           `json:"type"`
 	Intensity *string           `json:"intensity"`
 	Details   string            `json:"details" binding:"required"`
-	Status    db.TrainingStatus `json:"status"`
+	Status    db.TrainingStatus `json:"status" binding:"omitempty,oneof=new notified overdue done done_feedback"`
+	// PlannedDuration is in seconds
+	PlannedDuration *int32 `json:"planned_duration" binding:"omitempty,min=1"`
+	// Attributes are the values of the metrics of the sport
+	Attributes map[string]interface{} `json:"attributes"`
+	// Legs are the ordered legs of a multisport training
+	Legs []legRequest `json:"legs" binding:"omitempty,dive"`
 }
 
-func (r *createTrainingRequest) toDB() (db.CreateTrainingParams, error) {
+func (r *createTrainingRequest) toDB(organizationID int64) (db.CreateTrainingParams, error) {
 	d, err := time.Parse("2006-01-02", r.Date)
 	if err != nil {
 		return db.CreateTrainingParams{}, err
 	}
 	arg := db.CreateTrainingParams{
-		UserID:  r.UserID,
-		Date:    d,
-		Sport:   r.Sport,
-		Details: r.Details,
+		OrganizationID: organizationID,
+		UserID:         r.UserID,
+		Date:           d,
+		Sport:          r.Sport,
+		Details:        r.Details,
 	}
 	if r.Type != nil {
 		arg.Type.SetValid(*r.Type)
@@ -107,6 +183,14 @@ func (r *createTrainingRequest) toDB() (db.CreateTrainingParams, error) {
 	if r.Intensity != nil {
 		arg.Intensity.SetValid(*r.Intensity)
 	}
+	if r.PlannedDuration != nil {
+		arg.PlannedDuration.SetValid(*r.PlannedDuration)
+	}
+	if r.Attributes != nil {
+		if arg.Attributes, err = json.Marshal(r.Attributes); err != nil {
+			return db.CreateTrainingParams{}, err
+		}
+	}
 	if r.Status == "" {
 		arg.Status = db.TrainingStatusNew
 	} else {
@@ -119,65 +203,112 @@ func (r *createTrainingRequest) toDB() (db.CreateTrainingParams, error) {
 func (server *Server) createTraining(ctx *gin.Context) {
 	var req createTrainingRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if !server.checkTrainingSport(ctx, req.Sport, req.Attributes) {
+		return
+	}
+
+	// A group training is planned for every member of the group
+	if req.GroupID != 0 {
+		server.createGroupTraining(ctx, req)
 		return
 	}
 
 	// Check if the user exists
-	_, err := server.store.GetUser(ctx, req.UserID)
+	_, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: req.UserID})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("invalid user_id")))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_field", "user_id")))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	arg, err := req.toDB()
+	arg, err := req.toDB(tenantID(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	training, err := server.store.CreateTraining(ctx, arg)
+	legs, ok := server.trainingLegs(ctx, req.Sport, req.Legs)
+	if !ok {
+		return
+	}
+	if !arg.PlannedDuration.Valid {
+		arg.PlannedDuration = legsDuration(legs)
+	}
+
+	warnings, ok := server.checkAvailability(ctx, arg.UserID, arg.Date, arg.PlannedDuration.Int32)
+	if !ok {
+		return
+	}
+
+	var result db.TrainingTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.CreateTrainingTx(ctx, db.CreateTrainingTxParams{Training: arg, Legs: legs})
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityTraining, result.Training.ID, nil, result)}, err
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, training)
+	setETag(ctx, result.Training.Version)
+	ctx.JSON(http.StatusOK, trainingWarningsResponse{Training: result.Training, Legs: result.Legs, Warnings: warnings})
 }
 
 func (server *Server) deleteTraining(ctx *gin.Context) {
 	var req idRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var scope seriesScopeRequest
+	if err := ctx.ShouldBindQuery(&scope); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	// Check if the training exists
-	training, err := server.store.GetTraining(ctx, req.ID)
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: req.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// Trainings of a series are deleted along with the series changes
+	if training.SeriesID.Valid {
+		if scope.Scope == "" {
+			scope.Scope = seriesScopeThis
+		}
+		server.deleteSeriesTrainings(ctx, scope.Scope, training)
 		return
 	}
 
 	// Delete the training
-	err = server.store.DeleteTraining(ctx, training.ID)
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteTraining(ctx, db.DeleteTrainingParams{OrganizationID: training.OrganizationID, ID: training.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityTraining, training.ID, training, nil)}, err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -186,24 +317,33 @@ func (server *Server) deleteTraining(ctx *gin.Context) {
 
 type updateTrainingRequest struct {
 	Date      string            `json:"date" binding:"required,datetime=2006-01-02"`
-	Sport     db.TrainingSport  `json:"sport" binding:"required"`
+	Sport     string            `json:"sport" binding:"required"`
 	Type      *string           `json:"type"`
 	Intensity *string           `json:"intensity"`
 	Details   string            `json:"details" binding:"required"`
 	Status    db.TrainingStatus `json:"status" binding:"required,oneof=new notified overdue done done_feedback"`
+	// PlannedDuration is in seconds
+	PlannedDuration *int32 `json:"planned_duration" binding:"omitempty,min=1"`
+	// Attributes are the values of the metrics of the sport
+	Attributes map[string]interface{} `json:"attributes"`
+	// Legs replace the ones of a multisport training
+	Legs []legRequest `json:"legs" binding:"omitempty,dive"`
 }
 
-func (r *updateTrainingRequest) toDB(id int64) (db.UpdateTrainingParams, error) {
+// toDB returns the new values of the training, to be saved only if it is still at the version edited from
+func (r *updateTrainingRequest) toDB(training db.Training) (db.UpdateTrainingParams, error) {
 	d, err := time.Parse("2006-01-02", r.Date)
 	if err != nil {
 		return db.UpdateTrainingParams{}, err
 	}
 	arg := db.UpdateTrainingParams{
-		ID:      id,
-		Date:    d,
-		Sport:   r.Sport,
-		Details: r.Details,
-		Status:  r.Status,
+		OrganizationID: training.OrganizationID,
+		ID:             training.ID,
+		Date:           d,
+		Sport:          r.Sport,
+		Details:        r.Details,
+		Status:         r.Status,
+		Version:        training.Version,
 	}
 	if r.Type != nil {
 		arg.Type.SetValid(*r.Type)
@@ -211,6 +351,14 @@ func (r *updateTrainingRequest) toDB(id int64) (db.UpdateTrainingParams, error)
 	if r.Intensity != nil {
 		arg.Intensity.SetValid(*r.Intensity)
 	}
+	if r.PlannedDuration != nil {
+		arg.PlannedDuration.SetValid(*r.PlannedDuration)
+	}
+	if r.Attributes != nil {
+		if arg.Attributes, err = json.Marshal(r.Attributes); err != nil {
+			return db.UpdateTrainingParams{}, err
+		}
+	}
 
 	return arg, nil
 }
@@ -218,39 +366,210 @@ func (r *updateTrainingRequest) toDB(id int64) (db.UpdateTrainingParams, error)
 func (server *Server) updateTraining(ctx *gin.Context) {
 	var u idRequest
 	if err := ctx.ShouldBindUri(&u); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	var req updateTrainingRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	// Check if the user exists
-	user, err := server.store.GetUser(ctx, u.ID)
+	var scope seriesScopeRequest
+	if err := ctx.ShouldBindQuery(&scope); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the training exists
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: u.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	arg, err := req.toDB(user.ID)
+	if !checkIfMatch(ctx, training.Version) {
+		return
+	}
+
+	server.saveTraining(ctx, training, req, scope.Scope)
+}
+
+// newUpdateTrainingRequest returns the request that would update the training to its current values. The
+// planned duration of a multisport training derived from its legs is left out, to be derived again from the
+// patched legs.
+func newUpdateTrainingRequest(training db.Training, legs []db.TrainingLeg) (updateTrainingRequest, error) {
+	req := updateTrainingRequest{
+		Date:    training.Date.Format("2006-01-02"),
+		Sport:   training.Sport,
+		Details: training.Details,
+		Status:  training.Status,
+	}
+	if training.Type.Valid {
+		req.Type = &training.Type.String
+	}
+	if training.Intensity.Valid {
+		req.Intensity = &training.Intensity.String
+	}
+	if training.PlannedDuration.Valid {
+		req.PlannedDuration = &training.PlannedDuration.Int32
+	}
+	if len(training.Attributes) > 0 {
+		if err := json.Unmarshal(training.Attributes, &req.Attributes); err != nil {
+			return updateTrainingRequest{}, err
+		}
+	}
+
+	var derived int32
+	for _, l := range legs {
+		derived += l.PlannedDuration.Int32 + l.Transition.Int32
+		leg := legRequest{Sport: l.Sport}
+		if l.Details.Valid {
+			leg.Details = &l.Details.String
+		}
+		if l.PlannedDuration.Valid {
+			leg.PlannedDuration = &l.PlannedDuration.Int32
+		}
+		if l.Transition.Valid {
+			leg.Transition = &l.Transition.Int32
+		}
+		if len(l.Attributes) > 0 {
+			if err := json.Unmarshal(l.Attributes, &leg.Attributes); err != nil {
+				return updateTrainingRequest{}, err
+			}
+		}
+		req.Legs = append(req.Legs, leg)
+	}
+	if len(legs) > 0 && training.PlannedDuration.Valid && training.PlannedDuration.Int32 == derived {
+		req.PlannedDuration = nil
+	}
+
+	return req, nil
+}
+
+// patchTraining changes only the fields of the training in the patch, null clearing the optional ones
+func (server *Server) patchTraining(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var scope seriesScopeRequest
+	if err := ctx.ShouldBindQuery(&scope); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the training exists
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: u.ID})
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	updated, err := server.store.UpdateTraining(ctx, arg)
+	if !checkIfMatch(ctx, training.Version) {
+		return
+	}
+
+	legs, err := server.listLegs(ctx, training)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
+	current, err := newUpdateTrainingRequest(training, legs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	var req updateTrainingRequest
+	if !bindPatch(ctx, current, &req) {
+		return
+	}
+
+	server.saveTraining(ctx, training, req, scope.Scope)
+}
+
+// saveTraining updates the training with the values of the request, along with the following or all the
+// trainings of its series depending on the scope, writing the response
+func (server *Server) saveTraining(ctx *gin.Context, training db.Training, req updateTrainingRequest, scope string) {
+	if !server.checkTrainingSport(ctx, req.Sport, req.Attributes) {
+		return
+	}
+
+	arg, err := req.toDB(training)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// The change can be applied to the following trainings of the series or to all of them
+	if training.SeriesID.Valid && (scope == seriesScopeFollowing || scope == seriesScopeAll) {
+		if req.Sport == sports.Multisport {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("series.multisport")))
+			return
+		}
+		server.updateSeriesTrainings(ctx, scope, training, arg)
+		return
+	}
+
+	legs, ok := server.trainingLegs(ctx, req.Sport, req.Legs)
+	if !ok {
+		return
+	}
+	if !arg.PlannedDuration.Valid {
+		arg.PlannedDuration = legsDuration(legs)
+	}
+
+	warnings, ok := server.checkAvailability(ctx, training.UserID, arg.Date, arg.PlannedDuration.Int32)
+	if !ok {
+		return
+	}
+
+	var result db.TrainingTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.UpdateTrainingTx(ctx, db.UpdateTrainingTxParams{Training: arg, Legs: legs})
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityTraining, training.ID, training, result)}, err
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrVersionConflict) {
+			preconditionFailed(ctx)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	setETag(ctx, result.Training.Version)
+	ctx.JSON(http.StatusOK, trainingWarningsResponse{Training: result.Training, Legs: result.Legs, Warnings: warnings})
+}
+
+// loadTraining gets a training of the current organization, writing the error response if it doesn't exist
+func (server *Server) loadTraining(ctx *gin.Context, id int64) (db.Training, bool) {
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return training, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return training, false
+	}
 
-	ctx.JSON(http.StatusOK, updated)
+	return training, true
 }
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bulkTrainingsWarningsResponse is the result of a bulk change along with the availability conflicts of the
+// trainings it saved
+type bulkTrainingsWarningsResponse struct {
+	db.BulkTrainingsTxResult
+	Warnings []availabilityWarning `json:"warnings,omitempty"`
+}
+
+type copyTrainingsRequest struct {
+	UserID          int64   `json:"user_id" binding:"required,min=1"`
+	StartDate       string  `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate         string  `json:"end_date" binding:"required,datetime=2006-01-02"`
+	TargetStartDate string  `json:"target_start_date" binding:"required,datetime=2006-01-02"`
+	TargetUserIDs   []int64 `json:"target_user_ids" binding:"dive,min=1"`
+	FailOnConflict  bool    `json:"fail_on_conflict"`
+}
+
+func (server *Server) copyTrainings(ctx *gin.Context) {
+	var req copyTrainingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// we can ignore the errors because the values were already validated
+	start, _ := time.Parse("2006-01-02", req.StartDate)
+	end, _ := time.Parse("2006-01-02", req.EndDate)
+	target, _ := time.Parse("2006-01-02", req.TargetStartDate)
+	if end.Before(start) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.end_before_start")))
+		return
+	}
+
+	// Check if the users exist
+	for _, id := range append([]int64{req.UserID}, req.TargetUserIDs...) {
+		if !server.userExists(ctx, id) {
+			return
+		}
+	}
+
+	sources, err := server.store.ListTrainingsByUserInPeriod(ctx, db.ListTrainingsByUserInPeriodParams{
+		OrganizationID: tenantID(ctx),
+		UserID:         req.UserID,
+		Date:           start,
+		Date_2:         end,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	arg := db.CopyTrainingsTxParams{
+		OrganizationID: tenantID(ctx),
+		UserID:         req.UserID,
+		StartDate:      start,
+		EndDate:        end,
+		Days:           int(target.Sub(start).Hours() / 24),
+		TargetUserIDs:  req.TargetUserIDs,
+		FailOnConflict: req.FailOnConflict,
+	}
+	warnings, ok := server.checkTrainingsAvailability(ctx, bulkPlannedTrainings(sources, arg.UserID, arg.TargetUserIDs, arg.Days))
+	if !ok {
+		return
+	}
+
+	var result db.BulkTrainingsTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.CopyTrainingsTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]db.AuditEntry, 0, len(result.Trainings))
+		for _, training := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionCreate, auditEntityTraining, training.ID, nil, training))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		server.bulkTrainingsError(ctx, result, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bulkTrainingsWarningsResponse{BulkTrainingsTxResult: result, Warnings: warnings})
+}
+
+type shiftTrainingsRequest struct {
+	UserID    int64  `json:"user_id" binding:"required,min=1"`
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"required,datetime=2006-01-02"`
+	// Days is negative to move the trainings back
+	Days           int  `json:"days" binding:"required,min=-365,max=365"`
+	FailOnConflict bool `json:"fail_on_conflict"`
+}
+
+func (server *Server) shiftTrainings(ctx *gin.Context) {
+	var req shiftTrainingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// we can ignore the errors because the values were already validated
+	start, _ := time.Parse("2006-01-02", req.StartDate)
+	end, _ := time.Parse("2006-01-02", req.EndDate)
+	if end.Before(start) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.end_before_start")))
+		return
+	}
+
+	// Check if the user exists
+	if !server.userExists(ctx, req.UserID) {
+		return
+	}
+
+	before, err := server.store.ListTrainingsByUserInPeriod(ctx, db.ListTrainingsByUserInPeriodParams{
+		OrganizationID: tenantID(ctx),
+		UserID:         req.UserID,
+		Date:           start,
+		Date_2:         end,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// the completed trainings stay where they are
+	var sources []db.Training
+	for _, t := range before {
+		if t.Status != db.TrainingStatusDone && t.Status != db.TrainingStatusDoneFeedback {
+			sources = append(sources, t)
+		}
+	}
+	warnings, ok := server.checkTrainingsAvailability(ctx, bulkPlannedTrainings(sources, req.UserID, nil, req.Days))
+	if !ok {
+		return
+	}
+
+	arg := db.ShiftTrainingsTxParams{
+		OrganizationID: tenantID(ctx),
+		UserID:         req.UserID,
+		StartDate:      start,
+		EndDate:        end,
+		Days:           req.Days,
+		FailOnConflict: req.FailOnConflict,
+	}
+
+	previous := map[int64]db.Training{}
+	for _, t := range before {
+		previous[t.ID] = t
+	}
+
+	var result db.BulkTrainingsTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.ShiftTrainingsTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]db.AuditEntry, 0, len(result.Trainings))
+		for _, training := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionUpdate, auditEntityTraining, training.ID, previous[training.ID], training))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		server.bulkTrainingsError(ctx, result, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bulkTrainingsWarningsResponse{BulkTrainingsTxResult: result, Warnings: warnings})
+}
+
+// bulkPlannedTrainings returns where the trainings of a user land when they are moved by a number of days
+// to each of the target users, the user themselves if there is none
+func bulkPlannedTrainings(sources []db.Training, userID int64, targetUserIDs []int64, days int) []plannedTraining {
+	if len(targetUserIDs) == 0 {
+		targetUserIDs = []int64{userID}
+	}
+
+	planned := make([]plannedTraining, 0, len(sources)*len(targetUserIDs))
+	for _, id := range targetUserIDs {
+		for _, t := range sources {
+			planned = append(planned, plannedTraining{UserID: id, Date: t.Date.AddDate(0, 0, days), Duration: t.PlannedDuration.Int32})
+		}
+	}
+
+	return planned
+}
+
+// userExists checks if an active user exists, writing the error response if it doesn't
+func (server *Server) userExists(ctx *gin.Context, id int64) bool {
+	_, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_id", "user_id", id)))
+			return false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return false
+	}
+
+	return true
+}
+
+func (server *Server) bulkTrainingsError(ctx *gin.Context, result db.BulkTrainingsTxResult, err error) {
+	if errors.Is(err, db.ErrTrainingConflict) {
+		// nothing was saved, only the conflicts are reported
+		result.Trainings = []db.Training{}
+		ctx.JSON(http.StatusConflict, result)
+		return
+	}
+	// a training was updated while it was moved
+	if errors.Is(err, db.ErrVersionConflict) {
+		preconditionFailed(ctx)
+		return
+	}
+
+	ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+}
@@ -0,0 +1,360 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const maxImportRows = 5000
+
+// trainingCSVHeader are the columns of the CSV, the planned duration is in seconds and the attributes are a JSON object
+var trainingCSVHeader = []string{"athlete", "date", "sport", "type", "intensity", "details", "status", "planned_duration",
+	"attributes"}
+
+type importTrainingRequest struct {
+	DryRun bool `form:"dry_run"`
+}
+
+type importRowError struct {
+	Line   int      `json:"line"`
+	Errors []string `json:"errors"`
+}
+
+type importTrainingResponse struct {
+	DryRun bool             `json:"dry_run"`
+	Rows   int              `json:"rows"`
+	Errors []importRowError `json:"errors"`
+	// Warnings are the rows that don't fit the availability of the athlete, they are errors when the
+	// organization enforces it
+	Warnings  []importRowError `json:"warnings"`
+	Trainings []db.Training    `json:"trainings"`
+}
+
+func (server *Server) importTrainings(ctx *gin.Context) {
+	var req importTrainingRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// The CSV can be sent as a multipart "file" field or as the raw body
+	var body io.Reader = ctx.Request.Body
+	if file, err := ctx.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	r := csv.NewReader(body)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	if len(records) < 2 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("csv.empty")))
+		return
+	}
+	if len(records)-1 > maxImportRows {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("csv.too_many_rows", maxImportRows)))
+		return
+	}
+
+	columns, err := csvColumns(records[0])
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	res := importTrainingResponse{
+		DryRun:    req.DryRun,
+		Rows:      len(records) - 1,
+		Errors:    []importRowError{},
+		Warnings:  []importRowError{},
+		Trainings: []db.Training{},
+	}
+	catalogue, err := server.sportCatalogue(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	args := make([]db.CreateTrainingParams, 0, len(records)-1)
+	lines := make([]int, 0, len(records)-1)
+	athletes := map[string]int64{}
+	for i, record := range records[1:] {
+		// the header is line 1
+		line := i + 2
+		arg, errs := server.parseTrainingRecord(ctx, columns, record, athletes, catalogue)
+		if len(errs) > 0 {
+			res.Errors = append(res.Errors, importRowError{Line: line, Errors: errs})
+			continue
+		}
+		args = append(args, arg)
+		lines = append(lines, line)
+	}
+
+	planned := make([]plannedTraining, 0, len(args))
+	for _, arg := range args {
+		planned = append(planned, plannedTraining{UserID: arg.UserID, Date: arg.Date, Duration: arg.PlannedDuration.Int32})
+	}
+	conflicts, err := server.trainingsConflicts(ctx, planned)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	strict := parseOrganizationSettings(currentOrganization(ctx)).StrictAvailability
+	for i := range args {
+		if len(conflicts[i]) == 0 {
+			continue
+		}
+		rowError := importRowError{Line: lines[i]}
+		for _, conflict := range conflicts[i] {
+			rowError.Errors = append(rowError.Errors, conflict.Message)
+		}
+		if strict {
+			res.Errors = append(res.Errors, rowError)
+		} else {
+			res.Warnings = append(res.Warnings, rowError)
+		}
+	}
+	// the rows are reported in the order of the file
+	sort.Slice(res.Errors, func(i, j int) bool { return res.Errors[i].Line < res.Errors[j].Line })
+
+	if len(res.Errors) > 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, res)
+		return
+	}
+	if req.DryRun {
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		res.Trainings, err = store.CreateTrainingsTx(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]db.AuditEntry, 0, len(res.Trainings))
+		for _, training := range res.Trainings {
+			entries = append(entries, auditEntry(auditActionCreate, auditEntityTraining, training.ID, nil, training))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// csvColumns maps the known header names to their column index
+func csvColumns(header []string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"athlete", "date", "sport", "details"} {
+		if _, ok := columns[name]; !ok {
+			return nil, i18n.Errorf("csv.missing_column", name)
+		}
+	}
+
+	return columns, nil
+}
+
+// parseTrainingRecord validates a CSV row with the same rules of createTrainingRequest, the sport being one
+// of the catalogue
+func (server *Server) parseTrainingRecord(ctx *gin.Context, columns map[string]int, record []string, athletes map[string]int64, catalogue map[string]db.Sport) (db.CreateTrainingParams, []string) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	optional := func(name string) *string {
+		if v := field(name); v != "" {
+			return &v
+		}
+		return nil
+	}
+
+	var errs []string
+	athlete := field("athlete")
+	userID, ok := athletes[athlete]
+	if !ok {
+		var err error
+		userID, err = server.resolveAthlete(ctx, athlete)
+		if err != nil {
+			errs = append(errs, i18n.Translate(language(ctx), err))
+		} else {
+			athletes[athlete] = userID
+		}
+	}
+
+	req := createTrainingRequest{
+		UserID:    userID,
+		Date:      field("date"),
+		Sport:     field("sport"),
+		Type:      optional("type"),
+		Intensity: optional("intensity"),
+		Details:   field("details"),
+		Status:    db.TrainingStatus(field("status")),
+	}
+	if duration := field("planned_duration"); duration != "" {
+		d, err := strconv.ParseInt(duration, 10, 32)
+		if err != nil {
+			errs = append(errs, i18n.T(language(ctx), "csv.invalid_number", "planned_duration", duration))
+		} else {
+			plannedDuration := int32(d)
+			req.PlannedDuration = &plannedDuration
+		}
+	}
+	if attributes := field("attributes"); attributes != "" {
+		if err := json.Unmarshal([]byte(attributes), &req.Attributes); err != nil || req.Attributes == nil {
+			errs = append(errs, i18n.T(language(ctx), "csv.invalid_attributes"))
+		}
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		errs = append(errs, i18n.Translate(language(ctx), err))
+	}
+	if req.Sport != "" {
+		sport, ok := catalogue[req.Sport]
+		if !ok {
+			errs = append(errs, i18n.T(language(ctx), "sport.unknown", req.Sport))
+		} else if req.Sport == sports.Multisport {
+			// the legs can't be given in the CSV
+			errs = append(errs, i18n.T(language(ctx), "csv.multisport"))
+		} else if err := validateAttributes(sport, req.Attributes); err != nil {
+			errs = append(errs, i18n.Translate(language(ctx), err))
+		}
+	}
+	if len(errs) > 0 {
+		return db.CreateTrainingParams{}, errs
+	}
+
+	arg, err := req.toDB(tenantID(ctx))
+	if err != nil {
+		return db.CreateTrainingParams{}, []string{i18n.Translate(language(ctx), err)}
+	}
+
+	return arg, nil
+}
+
+// resolveAthlete finds an active athlete by id or email
+func (server *Server) resolveAthlete(ctx *gin.Context, athlete string) (int64, error) {
+	if athlete == "" {
+		return 0, i18n.Errorf("csv.missing_athlete")
+	}
+
+	var user db.User
+	var err error
+	if id, convErr := strconv.ParseInt(athlete, 10, 64); convErr == nil {
+		user, err = server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: id})
+	} else {
+		user, err = server.store.GetUserByEmail(ctx, db.GetUserByEmailParams{OrganizationID: tenantID(ctx), Email: athlete})
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, i18n.Errorf("csv.unknown_athlete", athlete)
+		}
+		return 0, err
+	}
+	if !user.Active || user.Type != db.UserTypeAthlete {
+		return 0, i18n.Errorf("csv.inactive_athlete", athlete)
+	}
+
+	return user.ID, nil
+}
+
+func (server *Server) exportTrainings(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req listTrainingRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// we can ignore the errors because the values were already validated
+	start, _ := time.Parse("2006-01-02", req.StartDate)
+	end, _ := time.Parse("2006-01-02", req.EndDate)
+	arg := db.ListTrainingsByUserInPeriodParams{
+		OrganizationID: tenantID(ctx),
+		UserID:         u.ID,
+		Date:           start,
+		Date_2:         end,
+	}
+
+	trainings, err := server.store.ListTrainingsByUserInPeriod(ctx, arg)
+	if err == nil {
+		err = server.markOverdue(ctx, trainings)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	records := [][]string{trainingCSVHeader}
+	for _, t := range trainings {
+		records = append(records, []string{
+			strconv.FormatInt(t.UserID, 10),
+			t.Date.Format("2006-01-02"),
+			t.Sport,
+			t.Type.String,
+			t.Intensity.String,
+			t.Details,
+			string(t.Status),
+			formatPlannedDuration(t.PlannedDuration),
+			string(t.Attributes),
+		})
+	}
+
+	filename := fmt.Sprintf("trainings-%d-%s-%s.csv", u.ID, req.StartDate, req.EndDate)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+	if err = w.WriteAll(records); err != nil {
+		_ = ctx.Error(err)
+	}
+}
+
+func formatPlannedDuration(d null.Int32) string {
+	if !d.Valid {
+		return ""
+	}
+	return strconv.FormatInt(int64(d.Int32), 10)
+}
@@ -2,12 +2,13 @@ package api
 
 import (
 	"database/sql"
-	"errors"
 	"net/http"
 	"time"
 
 	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
 
+	"github.com/emvi/null"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,7 +20,7 @@ type listTrainingFeedbackRequest struct {
 func (server *Server) listTrainingFeedbacksByUser(ctx *gin.Context) {
 	var u idRequest
 	if err := ctx.ShouldBindUri(&u); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -30,7 +31,7 @@ func (server *Server) listTrainingFeedbacksByUser(ctx *gin.Context) {
 	if start == "" && end == "" {
 		period = false
 	} else if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -41,18 +42,22 @@ func (server *Server) listTrainingFeedbacksByUser(ctx *gin.Context) {
 		start, _ := time.Parse("2006-01-02", req.StartDate)
 		end, _ := time.Parse("2006-01-02", req.EndDate)
 		arg := db.ListTrainingFeedbacksByUserInPeriodParams{
-			UserID: u.ID,
-			Date:   start,
-			Date_2: end,
+			OrganizationID: tenantID(ctx),
+			UserID:         u.ID,
+			Date:           start,
+			Date_2:         end,
 		}
 
 		feedbacks, err = server.store.ListTrainingFeedbacksByUserInPeriod(ctx, arg)
 	} else {
-		feedbacks, err = server.store.ListTrainingFeedbacksByUser(ctx, u.ID)
+		feedbacks, err = server.store.ListTrainingFeedbacksByUser(ctx, db.ListTrainingFeedbacksByUserParams{
+			OrganizationID: tenantID(ctx),
+			UserID:         u.ID,
+		})
 	}
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -62,116 +67,224 @@ func (server *Server) listTrainingFeedbacksByUser(ctx *gin.Context) {
 func (server *Server) getTrainingFeedback(ctx *gin.Context) {
 	var req idRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	feedback, err := server.store.GetTrainingFeedback(ctx, req.ID)
+	feedback, err := server.store.GetTrainingFeedback(ctx, db.GetTrainingFeedbackParams{
+		OrganizationID: tenantID(ctx),
+		TrainingID:     req.ID,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, feedback)
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: feedback.OrganizationID, ID: feedback.TrainingID})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	legs, err := server.listLegs(ctx, training)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	setETag(ctx, feedback.Version)
+	ctx.JSON(http.StatusOK, trainingFeedbackResponse{TrainingFeedback: feedback, Legs: legs})
 }
 
 type createTrainingFeedbackRequest struct {
 	BorgScale      int32            `json:"borg_scale" binding:"required,min=6,max=20"`
+	// Duration is in seconds and Distance in meters
+	Duration *int32 `json:"duration" binding:"omitempty,min=1"`
+	Distance *int32 `json:"distance" binding:"omitempty,min=1"`
+	AvgPower *int32 `json:"avg_power" binding:"omitempty,min=1"`
+	AvgHr    *int32 `json:"avg_hr" binding:"omitempty,min=1"`
+	// Pain is reported from 0 to 10, optionally linked to an injury of the athlete
+	Pain     *int32 `json:"pain" binding:"omitempty,min=0,max=10"`
+	InjuryID *int64 `json:"injury_id" binding:"omitempty,min=1"`
+	// Legs are the actual data of each leg of a multisport training, in order
+	Legs []legFeedbackRequest `json:"legs" binding:"omitempty,dive"`
 }
 
-func (r *createTrainingFeedbackRequest) toDB(trainingID int64) (db.CreateTrainingFeedbackParams, error) {
+// trainingFeedbackResponse is a feedback along with the actual data of the legs of a multisport training
+type trainingFeedbackResponse struct {
+	db.TrainingFeedback
+	Legs []db.TrainingLeg `json:"legs,omitempty"`
+}
+
+func (r *createTrainingFeedbackRequest) toDB(organizationID, trainingID int64) (db.CreateTrainingFeedbackParams, error) {
 	arg := db.CreateTrainingFeedbackParams{
-		TrainingID: trainingID,
-		BorgScale: r.BorgScale,
+		OrganizationID: organizationID,
+		TrainingID:     trainingID,
+		BorgScale:      r.BorgScale,
 	}
+	arg.Duration, arg.Distance, arg.AvgPower, arg.AvgHr = r.sessionData()
+	arg.Pain, arg.InjuryID = r.painReport()
 	return arg, nil
 }
 
+// sessionData returns the optional actual data of the session
+func (r *createTrainingFeedbackRequest) sessionData() (duration, distance, avgPower, avgHr null.Int32) {
+	if r.Duration != nil {
+		duration.SetValid(*r.Duration)
+	}
+	if r.Distance != nil {
+		distance.SetValid(*r.Distance)
+	}
+	if r.AvgPower != nil {
+		avgPower.SetValid(*r.AvgPower)
+	}
+	if r.AvgHr != nil {
+		avgHr.SetValid(*r.AvgHr)
+	}
+	return
+}
+
+// painReport returns the optional pain and the injury it is linked to
+func (r *createTrainingFeedbackRequest) painReport() (pain null.Int32, injuryID null.Int64) {
+	if r.Pain != nil {
+		pain.SetValid(*r.Pain)
+	}
+	if r.InjuryID != nil {
+		injuryID.SetValid(*r.InjuryID)
+	}
+	return
+}
+
 func (server *Server) createTrainingFeedback(ctx *gin.Context) {
 	var t idRequest
 	if err := ctx.ShouldBindUri(&t); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	var req createTrainingFeedbackRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	// Check if the training exists
-	_, err := server.store.GetTraining(ctx, t.ID)
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: t.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("invalid training_id")))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_field", "training_id")))
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if !server.checkFeedbackInjury(ctx, training, req.InjuryID) {
+		return
+	}
+	legs, ok := server.checkLegsFeedback(ctx, training, req.Legs)
+	if !ok {
 		return
 	}
 
-	arg, err := req.toDB(t.ID)
+	arg, err := req.toDB(tenantID(ctx), t.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
+	arg.Duration, arg.Distance = withLegsTotals(arg.Duration, arg.Distance, legs)
+
+	var rsp trainingFeedbackResponse
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		rsp.TrainingFeedback, err = store.CreateTrainingFeedback(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
 
-	training, err := server.store.CreateTrainingFeedback(ctx, arg)
+		if legs != nil {
+			rsp.Legs, err = store.UpdateTrainingLegsFeedbackTx(ctx, db.UpdateTrainingLegsFeedbackTxParams{
+				OrganizationID: training.OrganizationID,
+				TrainingID:     training.ID,
+				Legs:           legs,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityTrainingFeedback, rsp.ID, nil, rsp.TrainingFeedback)}, nil
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
+	server.checkTrainingEquipmentAlerts(ctx, training)
 
-	ctx.JSON(http.StatusOK, training)
+	setETag(ctx, rsp.Version)
+	ctx.JSON(http.StatusOK, rsp)
 }
 
 func (server *Server) deleteTrainingFeedback(ctx *gin.Context) {
 	var req idRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	// Check if the training exists
-	training, err := server.store.GetTraining(ctx, req.ID)
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: req.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	// Check if the feedback exists
-	_, err = server.store.GetTrainingFeedback(ctx, req.ID)
+	feedback, err := server.store.GetTrainingFeedback(ctx, db.GetTrainingFeedbackParams{
+		OrganizationID: tenantID(ctx),
+		TrainingID:     req.ID,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	// Delete the feedback
-	err = server.store.DeleteTrainingFeedback(ctx, training.ID)
+	// Delete the feedback, the actual data of the legs goes along with it
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		_, err := store.UpdateTrainingLegsFeedbackTx(ctx, db.UpdateTrainingLegsFeedbackTxParams{
+			OrganizationID: training.OrganizationID,
+			TrainingID:     training.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = store.DeleteTrainingFeedback(ctx, db.DeleteTrainingFeedbackParams{
+			OrganizationID: training.OrganizationID,
+			TrainingID:     training.ID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityTrainingFeedback, feedback.ID, feedback, nil)}, err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -182,50 +295,205 @@ type updateTrainingFeedbackRequest struct {
 	createTrainingFeedbackRequest
 }
 
-func (r *updateTrainingFeedbackRequest) toDB(trainingID int64) (db.UpdateTrainingFeedbackParams, error) {
+func (r *updateTrainingFeedbackRequest) toDB(feedback db.TrainingFeedback) (db.UpdateTrainingFeedbackParams, error) {
 	arg := db.UpdateTrainingFeedbackParams{
-		TrainingID: trainingID,
-		BorgScale: r.BorgScale,
+		OrganizationID: feedback.OrganizationID,
+		TrainingID:     feedback.TrainingID,
+		BorgScale:      r.BorgScale,
+		Version:        feedback.Version,
 	}
+	arg.Duration, arg.Distance, arg.AvgPower, arg.AvgHr = r.sessionData()
+	arg.Pain, arg.InjuryID = r.painReport()
 	return arg, nil
 }
 
 func (server *Server) updateTrainingFeedback(ctx *gin.Context) {
 	var t idRequest
 	if err := ctx.ShouldBindUri(&t); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	var req updateTrainingFeedbackRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	// Check if the training exists
-	training, err := server.store.GetTraining(ctx, t.ID)
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: t.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	arg, err := req.toDB(training.ID)
+	// Check if the feedback exists
+	feedback, err := server.store.GetTrainingFeedback(ctx, db.GetTrainingFeedbackParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	updated, err := server.store.UpdateTrainingFeedback(ctx, arg)
+	if !checkIfMatch(ctx, feedback.Version) {
+		return
+	}
+
+	server.saveTrainingFeedback(ctx, training, feedback, req)
+}
+
+// newUpdateTrainingFeedbackRequest returns the request that would update the feedback to its current values,
+// along with the actual data of the legs of a multisport training
+func newUpdateTrainingFeedbackRequest(feedback db.TrainingFeedback, legs []db.TrainingLeg) updateTrainingFeedbackRequest {
+	var req updateTrainingFeedbackRequest
+	req.BorgScale = feedback.BorgScale
+	if feedback.Duration.Valid {
+		req.Duration = &feedback.Duration.Int32
+	}
+	if feedback.Distance.Valid {
+		req.Distance = &feedback.Distance.Int32
+	}
+	if feedback.AvgPower.Valid {
+		req.AvgPower = &feedback.AvgPower.Int32
+	}
+	if feedback.AvgHr.Valid {
+		req.AvgHr = &feedback.AvgHr.Int32
+	}
+	if feedback.Pain.Valid {
+		req.Pain = &feedback.Pain.Int32
+	}
+	if feedback.InjuryID.Valid {
+		req.InjuryID = &feedback.InjuryID.Int64
+	}
+
+	for _, l := range legs {
+		var leg legFeedbackRequest
+		if l.Duration.Valid {
+			leg.Duration = &l.Duration.Int32
+		}
+		if l.Distance.Valid {
+			leg.Distance = &l.Distance.Int32
+		}
+		req.Legs = append(req.Legs, leg)
+	}
+	return req
+}
+
+// patchTrainingFeedback changes only the fields of the feedback in the patch, null clearing the optional ones
+func (server *Server) patchTrainingFeedback(ctx *gin.Context) {
+	var t idRequest
+	if err := ctx.ShouldBindUri(&t); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the training exists
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: t.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the feedback exists
+	feedback, err := server.store.GetTrainingFeedback(ctx, db.GetTrainingFeedbackParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if !checkIfMatch(ctx, feedback.Version) {
+		return
+	}
+
+	legs, err := server.listLegs(ctx, training)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, updated)
+	var req updateTrainingFeedbackRequest
+	if !bindPatch(ctx, newUpdateTrainingFeedbackRequest(feedback, legs), &req) {
+		return
+	}
+
+	server.saveTrainingFeedback(ctx, training, feedback, req)
+}
+
+// saveTrainingFeedback updates the feedback of the training with the values of the request, writing the response
+func (server *Server) saveTrainingFeedback(ctx *gin.Context, training db.Training, feedback db.TrainingFeedback, req updateTrainingFeedbackRequest) {
+	if !server.checkFeedbackInjury(ctx, training, req.InjuryID) {
+		return
+	}
+	legs, ok := server.checkLegsFeedback(ctx, training, req.Legs)
+	if !ok {
+		return
+	}
+
+	arg, err := req.toDB(feedback)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	arg.Duration, arg.Distance = withLegsTotals(arg.Duration, arg.Distance, legs)
+
+	var rsp trainingFeedbackResponse
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		rsp.TrainingFeedback, err = store.UpdateTrainingFeedback(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if legs != nil {
+			rsp.Legs, err = store.UpdateTrainingLegsFeedbackTx(ctx, db.UpdateTrainingLegsFeedbackTxParams{
+				OrganizationID: training.OrganizationID,
+				TrainingID:     training.ID,
+				Legs:           legs,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityTrainingFeedback, feedback.ID, feedback, rsp.TrainingFeedback)}, nil
+	})
+	if err != nil {
+		// the feedback was updated since it was loaded
+		if err == sql.ErrNoRows {
+			preconditionFailed(ctx)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.checkTrainingEquipmentAlerts(ctx, training)
+
+	setETag(ctx, rsp.Version)
+	ctx.JSON(http.StatusOK, rsp)
 }
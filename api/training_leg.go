@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+// minLegs is the number of legs a multisport training has at least
+const minLegs = 2
+
+type legRequest struct {
+	Sport   string  `json:"sport" binding:"required"`
+	Details *string `json:"details"`
+	// PlannedDuration and Transition are in seconds, the transition being the time planned to change
+	// from the previous leg
+	PlannedDuration *int32 `json:"planned_duration" binding:"omitempty,min=1"`
+	Transition      *int32 `json:"transition" binding:"omitempty,min=0"`
+	// Attributes are the values of the metrics of the sport of the leg
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type legFeedbackRequest struct {
+	// Duration is in seconds and Distance in meters
+	Duration *int32 `json:"duration" binding:"omitempty,min=1"`
+	Distance *int32 `json:"distance" binding:"omitempty,min=1"`
+}
+
+// trainingLegsResponse is a training along with its legs when it is a multisport one
+type trainingLegsResponse struct {
+	db.Training
+	Legs []db.TrainingLeg `json:"legs,omitempty"`
+}
+
+// trainingLegs checks that only multisport trainings have legs and that they are valid, writing the error
+// response if they aren't. It returns the legs to store in order.
+func (server *Server) trainingLegs(ctx *gin.Context, sport string, legs []legRequest) ([]db.CreateTrainingLegParams, bool) {
+	if sport != sports.Multisport {
+		if len(legs) > 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("leg.not_multisport")))
+			return nil, false
+		}
+		return nil, true
+	}
+	if len(legs) < minLegs {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("leg.min", minLegs)))
+		return nil, false
+	}
+
+	catalogue, err := server.sportCatalogue(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return nil, false
+	}
+
+	args := make([]db.CreateTrainingLegParams, len(legs))
+	for i, l := range legs {
+		s, ok := catalogue[l.Sport]
+		if !ok || l.Sport == sports.Multisport {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("leg.invalid_sport", l.Sport, i+1)))
+			return nil, false
+		}
+		if err := validateAttributes(s, l.Attributes); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("leg.invalid", i+1, err)))
+			return nil, false
+		}
+		if i == 0 && l.Transition != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("leg.first_transition")))
+			return nil, false
+		}
+
+		arg := db.CreateTrainingLegParams{Position: int32(i + 1), Sport: l.Sport}
+		if l.Details != nil {
+			arg.Details.SetValid(*l.Details)
+		}
+		if l.PlannedDuration != nil {
+			arg.PlannedDuration.SetValid(*l.PlannedDuration)
+		}
+		if l.Transition != nil {
+			arg.Transition.SetValid(*l.Transition)
+		}
+		if l.Attributes != nil {
+			if arg.Attributes, err = json.Marshal(l.Attributes); err != nil {
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+				return nil, false
+			}
+		}
+		args[i] = arg
+	}
+
+	return args, true
+}
+
+// legsDuration is the planned duration of a multisport training, its legs and transitions together
+func legsDuration(legs []db.CreateTrainingLegParams) null.Int32 {
+	var duration null.Int32
+	for _, l := range legs {
+		if l.PlannedDuration.Valid || l.Transition.Valid {
+			duration.SetValid(duration.Int32 + l.PlannedDuration.Int32 + l.Transition.Int32)
+		}
+	}
+	return duration
+}
+
+// checkLegsFeedback checks the actual data of the legs given with the feedback of a training, writing the error
+// response if they don't match its legs. It returns nil when no legs are given.
+func (server *Server) checkLegsFeedback(ctx *gin.Context, training db.Training, feedback []legFeedbackRequest) ([]db.UpdateTrainingLegFeedbackParams, bool) {
+	if feedback == nil {
+		return nil, true
+	}
+
+	legs, err := server.listLegs(ctx, training)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return nil, false
+	}
+	if len(legs) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("leg.none")))
+		return nil, false
+	}
+	if len(feedback) != len(legs) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("leg.count", len(legs))))
+		return nil, false
+	}
+
+	args := make([]db.UpdateTrainingLegFeedbackParams, len(feedback))
+	for i, f := range feedback {
+		args[i].Position = legs[i].Position
+		if f.Duration != nil {
+			args[i].Duration.SetValid(*f.Duration)
+		}
+		if f.Distance != nil {
+			args[i].Distance.SetValid(*f.Distance)
+		}
+	}
+	return args, true
+}
+
+// withLegsTotals fills the duration and distance of a feedback that weren't given with the sum of the legs
+func withLegsTotals(duration, distance null.Int32, legs []db.UpdateTrainingLegFeedbackParams) (null.Int32, null.Int32) {
+	var legsDuration, legsDistance null.Int32
+	for _, l := range legs {
+		if l.Duration.Valid {
+			legsDuration.SetValid(legsDuration.Int32 + l.Duration.Int32)
+		}
+		if l.Distance.Valid {
+			legsDistance.SetValid(legsDistance.Int32 + l.Distance.Int32)
+		}
+	}
+
+	if !duration.Valid {
+		duration = legsDuration
+	}
+	if !distance.Valid {
+		distance = legsDistance
+	}
+	return duration, distance
+}
+
+// withLegs adds their legs to the multisport trainings
+func (server *Server) withLegs(ctx *gin.Context, trainings []trainingResponse) error {
+	var ids []int64
+	for _, t := range trainings {
+		if t.Sport == sports.Multisport {
+			ids = append(ids, t.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	legs, err := server.store.ListTrainingLegsByTrainings(ctx, db.ListTrainingLegsByTrainingsParams{
+		OrganizationID: trainings[0].OrganizationID,
+		TrainingIds:    ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	byTraining := make(map[int64][]db.TrainingLeg, len(ids))
+	for _, l := range legs {
+		byTraining[l.TrainingID] = append(byTraining[l.TrainingID], l)
+	}
+	for i := range trainings {
+		trainings[i].Legs = byTraining[trainings[i].ID]
+	}
+
+	return nil
+}
+
+// listLegs returns the legs of a training, none when it isn't a multisport one
+func (server *Server) listLegs(ctx *gin.Context, training db.Training) ([]db.TrainingLeg, error) {
+	if training.Sport != sports.Multisport {
+		return nil, nil
+	}
+
+	return server.store.ListTrainingLegs(ctx, db.ListTrainingLegsParams{
+		OrganizationID: training.OrganizationID,
+		TrainingID:     training.ID,
+	})
+}
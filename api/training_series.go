@@ -0,0 +1,514 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/rrule"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityTrainingSeries = "training_series"
+
+// Scopes of a change made to a training that belongs to a series
+const (
+	seriesScopeThis      = "this"
+	seriesScopeFollowing = "following"
+	seriesScopeAll       = "all"
+)
+
+type seriesScopeRequest struct {
+	Scope string `form:"scope" binding:"omitempty,oneof=this following all"`
+}
+
+type createTrainingSeriesRequest struct {
+	UserID    int64             `json:"user_id" binding:"required"`
+	StartDate string            `json:"start_date" binding:"required,datetime=2006-01-02"`
+	Rrule     string            `json:"rrule" binding:"required"`
+	Exdates   []string          `json:"exdates" binding:"dive,datetime=2006-01-02"`
+	Sport     string            `json:"sport" binding:"required"`
+	Type      *string           `json:"type"`
+	Intensity *string           `json:"intensity"`
+	Details   string            `json:"details" binding:"required"`
+	Status    db.TrainingStatus `json:"status" binding:"omitempty,oneof=new notified overdue done done_feedback"`
+}
+
+func (r *createTrainingSeriesRequest) toDB(organizationID int64) (db.CreateTrainingSeriesTxParams, error) {
+	start, err := time.Parse("2006-01-02", r.StartDate)
+	if err != nil {
+		return db.CreateTrainingSeriesTxParams{}, err
+	}
+
+	rule, err := rrule.Parse(r.Rrule)
+	if err != nil {
+		return db.CreateTrainingSeriesTxParams{}, err
+	}
+
+	exdates := make([]time.Time, 0, len(r.Exdates))
+	for _, d := range r.Exdates {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return db.CreateTrainingSeriesTxParams{}, err
+		}
+		exdates = append(exdates, t)
+	}
+
+	dates, err := rule.All(start, exdates)
+	if err != nil {
+		return db.CreateTrainingSeriesTxParams{}, err
+	}
+	if len(dates) == 0 {
+		return db.CreateTrainingSeriesTxParams{}, i18n.Errorf("series.no_occurrences")
+	}
+
+	arg := db.CreateTrainingSeriesTxParams{
+		Series: db.CreateTrainingSeriesParams{
+			OrganizationID: organizationID,
+			UserID:         r.UserID,
+			Rrule:          rule.String(),
+			Dtstart:        start,
+			Exdate:         rrule.FormatDates(exdates),
+			Sport:          r.Sport,
+			Details:        r.Details,
+		},
+		Dates:  dates,
+		Status: r.Status,
+	}
+	if r.Type != nil {
+		arg.Series.Type.SetValid(*r.Type)
+	}
+	if r.Intensity != nil {
+		arg.Series.Intensity.SetValid(*r.Intensity)
+	}
+	if arg.Status == "" {
+		arg.Status = db.TrainingStatusNew
+	}
+
+	return arg, nil
+}
+
+// trainingSeriesWarningsResponse is a saved series along with the availability conflicts of its trainings
+type trainingSeriesWarningsResponse struct {
+	db.TrainingSeriesTxResult
+	Warnings []availabilityWarning `json:"warnings,omitempty"`
+}
+
+func (server *Server) createTrainingSeries(ctx *gin.Context) {
+	var req createTrainingSeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// the series have no attributes, so sports with required metrics can't be planned in series
+	if !server.checkTrainingSport(ctx, req.Sport, nil) {
+		return
+	}
+	if req.Sport == sports.Multisport {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("series.multisport")))
+		return
+	}
+
+	// Check if the user exists
+	_, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: req.UserID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_field", "user_id")))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	arg, err := req.toDB(tenantID(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	planned := make([]plannedTraining, 0, len(arg.Dates))
+	for _, d := range arg.Dates {
+		planned = append(planned, plannedTraining{UserID: req.UserID, Date: d})
+	}
+	warnings, ok := server.checkTrainingsAvailability(ctx, planned)
+	if !ok {
+		return
+	}
+
+	var result db.TrainingSeriesTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.CreateTrainingSeriesTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []db.AuditEntry{auditEntry(auditActionCreate, auditEntityTrainingSeries, result.Series.ID, nil, result.Series)}
+		for _, training := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionCreate, auditEntityTraining, training.ID, nil, training))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trainingSeriesWarningsResponse{TrainingSeriesTxResult: result, Warnings: warnings})
+}
+
+func (server *Server) getTrainingSeries(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	series, err := server.store.GetTrainingSeries(ctx, db.GetTrainingSeriesParams{OrganizationID: tenantID(ctx), ID: req.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	trainings, err := server.store.ListTrainingsBySeries(ctx, db.ListTrainingsBySeriesParams{
+		OrganizationID: series.OrganizationID,
+		SeriesID:       null.NewInt64(series.ID, true),
+	})
+	if err == nil {
+		err = server.markOverdue(ctx, trainings)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, db.TrainingSeriesTxResult{Series: series, Trainings: trainings})
+}
+
+// loadSeries returns the series of a training and its parsed rule and excluded dates
+func (server *Server) loadSeries(ctx *gin.Context, training db.Training) (db.TrainingSeries, rrule.Rule, []time.Time, error) {
+	series, err := server.store.GetTrainingSeries(ctx, db.GetTrainingSeriesParams{
+		OrganizationID: training.OrganizationID,
+		ID:             training.SeriesID.Int64,
+	})
+	if err != nil {
+		return series, rrule.Rule{}, nil, err
+	}
+
+	rule, err := rrule.Parse(series.Rrule)
+	if err != nil {
+		return series, rrule.Rule{}, nil, err
+	}
+
+	exdates, err := rrule.ParseDates(series.Exdate)
+	if err != nil {
+		return series, rrule.Rule{}, nil, err
+	}
+
+	return series, rule, exdates, nil
+}
+
+// updateSeriesTrainings applies the change made to a training to the following trainings
+// of its series, or to all of them. The following trainings are moved to a new series.
+// Completed trainings are history and keep their date and content.
+// When the sport changes the trainings take the attributes of the training, that were checked for it.
+func (server *Server) updateSeriesTrainings(ctx *gin.Context, scope string, training db.Training, arg db.UpdateTrainingParams) {
+	series, rule, exdates, err := server.loadSeries(ctx, training)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	before, err := server.store.ListTrainingsBySeries(ctx, db.ListTrainingsBySeriesParams{
+		OrganizationID: training.OrganizationID,
+		SeriesID:       training.SeriesID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// dates are moved by the same number of days the training was moved
+	days := int(arg.Date.Sub(training.Date).Hours() / 24)
+	if scope == seriesScopeFollowing && !training.Date.After(series.Dtstart) {
+		scope = seriesScopeAll
+	}
+	shifted, err := rule.Shift(days)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	txArg := db.UpdateTrainingSeriesTxParams{
+		Trainings: db.UpdateSeriesTrainingsParams{
+			OrganizationID: series.OrganizationID,
+			Days:           int32(days),
+			Sport:          arg.Sport,
+			Attributes:     arg.Attributes,
+			Type:           arg.Type,
+			Intensity:      arg.Intensity,
+			Details:        arg.Details,
+			SeriesID:       training.SeriesID,
+		},
+		Training: arg,
+	}
+
+	if scope == seriesScopeAll {
+		txArg.Series = db.UpdateTrainingSeriesParams{
+			OrganizationID: series.OrganizationID,
+			ID:             series.ID,
+			Rrule:          shifted.String(),
+			Dtstart:        series.Dtstart.AddDate(0, 0, days),
+			Exdate:         rrule.FormatDates(shiftDates(exdates, days)),
+			Sport:          arg.Sport,
+			Type:           arg.Type,
+			Intensity:      arg.Intensity,
+			Details:        arg.Details,
+		}
+	} else {
+		// the current series ends the day before the training
+		occurrences, err := rule.All(series.Dtstart, nil)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		remaining := 0
+		for _, d := range occurrences {
+			if !d.Before(training.Date) {
+				remaining++
+			}
+		}
+
+		truncated := rule
+		truncated.Count = 0
+		truncated.Until = training.Date.AddDate(0, 0, -1)
+		txArg.Series = db.UpdateTrainingSeriesParams{
+			OrganizationID: series.OrganizationID,
+			ID:             series.ID,
+			Rrule:          truncated.String(),
+			Dtstart:        series.Dtstart,
+			Exdate:         rrule.FormatDates(datesBefore(exdates, training.Date)),
+			Sport:          series.Sport,
+			Type:           series.Type,
+			Intensity:      series.Intensity,
+			Details:        series.Details,
+		}
+
+		split := shifted
+		if split.Count > 0 {
+			split.Count = remaining
+		}
+		txArg.Split = &db.CreateTrainingSeriesParams{
+			OrganizationID: series.OrganizationID,
+			UserID:         series.UserID,
+			Rrule:          split.String(),
+			Dtstart:        arg.Date,
+			Exdate:         rrule.FormatDates(shiftDates(datesFrom(exdates, training.Date), days)),
+			Sport:          arg.Sport,
+			Type:           arg.Type,
+			Intensity:      arg.Intensity,
+			Details:        arg.Details,
+		}
+		txArg.Trainings.FromDate = training.Date
+	}
+
+	// the training lands where it was moved and the pending ones the change applies to are moved along
+	planned := []plannedTraining{{UserID: training.UserID, Date: arg.Date, Duration: arg.PlannedDuration.Int32}}
+	for _, t := range before {
+		if t.ID == training.ID || t.Status == db.TrainingStatusDone || t.Status == db.TrainingStatusDoneFeedback {
+			continue
+		}
+		if scope == seriesScopeFollowing && t.Date.Before(training.Date) {
+			continue
+		}
+		planned = append(planned, plannedTraining{UserID: t.UserID, Date: t.Date.AddDate(0, 0, days), Duration: t.PlannedDuration.Int32})
+	}
+	warnings, ok := server.checkTrainingsAvailability(ctx, planned)
+	if !ok {
+		return
+	}
+
+	previous := map[int64]db.Training{}
+	for _, t := range before {
+		previous[t.ID] = t
+	}
+
+	var result db.TrainingSeriesTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.UpdateTrainingSeriesTx(ctx, txArg)
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []db.AuditEntry
+		if txArg.Split != nil {
+			updated, err := store.GetTrainingSeries(ctx, db.GetTrainingSeriesParams{
+				OrganizationID: series.OrganizationID,
+				ID:             series.ID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries,
+				auditEntry(auditActionUpdate, auditEntityTrainingSeries, series.ID, series, updated),
+				auditEntry(auditActionCreate, auditEntityTrainingSeries, result.Series.ID, nil, result.Series))
+		} else {
+			entries = append(entries, auditEntry(auditActionUpdate, auditEntityTrainingSeries, series.ID, series, result.Series))
+		}
+		for _, t := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionUpdate, auditEntityTraining, t.ID, previous[t.ID], t))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrVersionConflict) {
+			preconditionFailed(ctx)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	for _, t := range result.Trainings {
+		if t.ID == training.ID {
+			setETag(ctx, t.Version)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, trainingSeriesWarningsResponse{TrainingSeriesTxResult: result, Warnings: warnings})
+}
+
+// deleteSeriesTrainings deletes a training of a series, the following ones or all of them.
+// Completed trainings are history and are kept, detached from the series when it is deleted.
+func (server *Server) deleteSeriesTrainings(ctx *gin.Context, scope string, training db.Training) {
+	series, rule, exdates, err := server.loadSeries(ctx, training)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if scope == seriesScopeFollowing && !training.Date.After(series.Dtstart) {
+		scope = seriesScopeAll
+	}
+
+	arg := db.DeleteTrainingSeriesTxParams{
+		Series: db.UpdateTrainingSeriesParams{
+			OrganizationID: series.OrganizationID,
+			ID:             series.ID,
+			Rrule:          series.Rrule,
+			Dtstart:        series.Dtstart,
+			Exdate:         series.Exdate,
+			Sport:          series.Sport,
+			Type:           series.Type,
+			Intensity:      series.Intensity,
+			Details:        series.Details,
+		},
+	}
+	switch scope {
+	case seriesScopeThis:
+		arg.TrainingID = training.ID
+		arg.Series.Exdate = rrule.FormatDates(append(exdates, training.Date))
+	case seriesScopeFollowing:
+		truncated := rule
+		truncated.Count = 0
+		truncated.Until = training.Date.AddDate(0, 0, -1)
+		arg.Series.Rrule = truncated.String()
+		arg.Series.Exdate = rrule.FormatDates(datesBefore(exdates, training.Date))
+		arg.FromDate = training.Date
+	case seriesScopeAll:
+		arg.DeleteSeries = true
+	}
+
+	// the completed trainings left are detached from a deleted series
+	previous := map[int64]db.Training{}
+	if arg.DeleteSeries {
+		before, err := server.store.ListTrainingsBySeries(ctx, db.ListTrainingsBySeriesParams{
+			OrganizationID: training.OrganizationID,
+			SeriesID:       training.SeriesID,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		for _, t := range before {
+			previous[t.ID] = t
+		}
+	}
+
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		result, err := store.DeleteTrainingSeriesTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []db.AuditEntry
+		if arg.DeleteSeries {
+			entries = append(entries, auditEntry(auditActionDelete, auditEntityTrainingSeries, series.ID, series, nil))
+		} else {
+			updated, err := store.GetTrainingSeries(ctx, db.GetTrainingSeriesParams{
+				OrganizationID: series.OrganizationID,
+				ID:             series.ID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, auditEntry(auditActionUpdate, auditEntityTrainingSeries, series.ID, series, updated))
+		}
+		for _, t := range result.Deleted {
+			entries = append(entries, auditEntry(auditActionDelete, auditEntityTraining, t.ID, t, nil))
+		}
+		for _, t := range result.Detached {
+			entries = append(entries, auditEntry(auditActionUpdate, auditEntityTraining, t.ID, previous[t.ID], t))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func shiftDates(dates []time.Time, days int) []time.Time {
+	shifted := make([]time.Time, len(dates))
+	for i, d := range dates {
+		shifted[i] = d.AddDate(0, 0, days)
+	}
+	return shifted
+}
+
+func datesBefore(dates []time.Time, date time.Time) []time.Time {
+	var before []time.Time
+	for _, d := range dates {
+		if d.Before(date) {
+			before = append(before, d)
+		}
+	}
+	return before
+}
+
+func datesFrom(dates []time.Time, date time.Time) []time.Time {
+	var from []time.Time
+	for _, d := range dates {
+		if !d.Before(date) {
+			from = append(from, d)
+		}
+	}
+	return from
+}
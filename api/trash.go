@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (server *Server) listDeletedUsers(ctx *gin.Context) {
+	var req listUserRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.ListDeletedUsersParams{
+		OrganizationID: tenantID(ctx),
+		Limit:          req.PageSize,
+		Offset:         (req.PageID - 1) * req.PageSize,
+	}
+
+	users, err := server.store.ListDeletedUsers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, users)
+}
+
+func (server *Server) listDeletedTrainings(ctx *gin.Context) {
+	var req listUserRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.ListDeletedTrainingsParams{
+		OrganizationID: tenantID(ctx),
+		Limit:          req.PageSize,
+		Offset:         (req.PageID - 1) * req.PageSize,
+	}
+
+	trainings, err := server.store.ListDeletedTrainings(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, trainings)
+}
+
+type restoreUserRequest struct {
+	Trainings bool `form:"trainings"`
+}
+
+func (server *Server) restoreUser(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req restoreUserRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the user is in the trash
+	user, err := server.store.GetDeletedUser(ctx, db.GetDeletedUserParams{OrganizationID: tenantID(ctx), ID: u.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.RestoreUserTxParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		Trainings:      req.Trainings,
+	}
+
+	var result db.RestoreUserTxResult
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		result, err = store.RestoreUserTx(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := []db.AuditEntry{auditEntry(auditActionRestore, auditEntityUser, user.ID, user, result.User)}
+		for _, training := range result.Trainings {
+			entries = append(entries, auditEntry(auditActionRestore, auditEntityTraining, training.ID, nil, training))
+		}
+		return entries, nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) restoreTraining(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the training is in the trash
+	training, err := server.store.GetDeletedTraining(ctx, db.GetDeletedTrainingParams{OrganizationID: tenantID(ctx), ID: req.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// A training can't be restored while its user is deleted
+	_, err = server.store.GetUser(ctx, db.GetUserParams{OrganizationID: training.OrganizationID, ID: training.UserID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusConflict, errorResponse(ctx, i18n.Errorf("trash.user_deleted")))
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	var restored db.Training
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		restored, err = store.RestoreTraining(ctx, db.RestoreTrainingParams{
+			OrganizationID: training.OrganizationID,
+			ID:             training.ID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionRestore, auditEntityTraining, training.ID, training, restored)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, restored)
+}
+
+type purgeTrashRequest struct {
+	// OlderThan overrides the configured retention, e.g. "720h"
+	OlderThan string `form:"older_than"`
+}
+
+func (server *Server) purgeTrash(ctx *gin.Context) {
+	var req purgeTrashRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	organization := currentOrganization(ctx)
+	retention := server.retention(organization)
+	if req.OlderThan != "" {
+		d, err := time.ParseDuration(req.OlderThan)
+		if err != nil || d <= 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.invalid_field", "older_than")))
+			return
+		}
+		retention = d
+	}
+	if retention <= 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("trash.no_retention")))
+		return
+	}
+
+	result, err := server.purgeDeleted(ctx, organization.ID, retention, ctx.GetString(requestIDKey))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RunRetention periodically purges the rows that have been in the trash for longer than
+// the retention of their organization
+func (server *Server) RunRetention() {
+	ticker := time.NewTicker(server.config.RetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		organizations, err := server.store.ListOrganizations(context.Background())
+		if err != nil {
+			log.Printf("cannot purge trash: %v", err)
+		}
+
+		for _, organization := range organizations {
+			retention := server.retention(organization)
+			if retention <= 0 {
+				continue
+			}
+
+			result, err := server.purgeDeleted(context.Background(), organization.ID, retention, "retention")
+			if err != nil {
+				log.Printf("cannot purge trash of organization %d: %v", organization.ID, err)
+				continue
+			}
+			log.Printf("purged %d users, %d trainings and %d feedbacks from trash of organization %d",
+				len(result.UserIDs), len(result.TrainingIDs), len(result.TrainingFeedbackIDs), organization.ID)
+		}
+
+		<-ticker.C
+	}
+}
+
+func (server *Server) purgeDeleted(ctx context.Context, organizationID int64, retention time.Duration, requestID string) (db.PurgeDeletedTxResult, error) {
+	arg := db.PurgeDeletedTxParams{
+		OrganizationID: organizationID,
+		Before:         time.Now().Add(-retention),
+		Audit: db.CreateAuditLogParams{
+			Action:    auditActionPurge,
+			RequestID: requestID,
+		},
+	}
+	if gctx, ok := ctx.(*gin.Context); ok {
+		if user, ok := currentActor(gctx); ok {
+			arg.Audit.ActorID.SetValid(user.ID)
+		}
+	}
+
+	return server.store.PurgeDeletedTx(ctx, arg)
+}
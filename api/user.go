@@ -3,10 +3,13 @@ package api
 import (
 	"database/sql"
 	"net/http"
+	"regexp"
 	"time"
 
 	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
 
+	"github.com/emvi/null"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,15 +19,46 @@ type createUserRequest struct {
 	Gender db.GenderType `json:"gender" binding:"required,oneof=M F"`
 	Email  string        `json:"email" binding:"required"`
 	Phone  *string       `json:"phone"`
-	Birth  *string       `json:"birth" binding:"datetime=2006-01-02"`
+	Birth  *string       `json:"birth" binding:"omitempty,datetime=2006-01-02"`
+	// Timezone is the IANA name of the zone of the user, Locale a language tag like "pt-BR"
+	Timezone *string        `json:"timezone" binding:"omitempty,timezone"`
+	Locale   *string        `json:"locale"`
+	Units    *db.UnitSystem `json:"units" binding:"omitempty,oneof=metric imperial"`
 }
 
-func (r *createUserRequest) toDB() (db.CreateUserParams, error) {
+const (
+	defaultTimezone = "UTC"
+	defaultLocale   = "en"
+	defaultUnits    = db.UnitSystemMetric
+)
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// preferences returns the timezone, locale and unit system of the request, the given ones where they
+// weren't sent
+func (r *createUserRequest) preferences(timezone, locale string, units db.UnitSystem) (string, string, db.UnitSystem, error) {
+	if r.Timezone != nil {
+		timezone = *r.Timezone
+	}
+	if r.Locale != nil {
+		if !localePattern.MatchString(*r.Locale) {
+			return "", "", "", i18n.Errorf("user.invalid_locale")
+		}
+		locale = *r.Locale
+	}
+	if r.Units != nil {
+		units = *r.Units
+	}
+	return timezone, locale, units, nil
+}
+
+func (r *createUserRequest) toDB(organizationID int64) (db.CreateUserParams, error) {
 	arg := db.CreateUserParams{
-		Type:  r.Type,
-		Name:  r.Name,
-		Email: r.Email,
-		Gender: r.Gender,
+		OrganizationID: organizationID,
+		Type:           r.Type,
+		Name:           r.Name,
+		Email:          r.Email,
+		Gender:         null.NewString(string(r.Gender), true),
 	}
 	if r.Phone != nil {
 		arg.Phone.SetValid(*r.Phone)
@@ -37,49 +71,62 @@ func (r *createUserRequest) toDB() (db.CreateUserParams, error) {
 		arg.Birth.SetValid(t)
 	}
 
+	var err error
+	arg.Timezone, arg.Locale, arg.Units, err = r.preferences(defaultTimezone, defaultLocale, defaultUnits)
+	if err != nil {
+		return db.CreateUserParams{}, err
+	}
+
 	return arg, nil
 }
 
 func (server *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	arg, err := req.toDB()
+	arg, err := req.toDB(tenantID(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	var user db.User
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		user, err = store.CreateUser(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityUser, user.ID, nil, user)}, err
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	setETag(ctx, user.Version)
 	ctx.JSON(http.StatusOK, user)
 }
 
 func (server *Server) getUser(ctx *gin.Context) {
 	var req idRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	user, err := server.store.GetUser(ctx, req.ID)
+	user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: req.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	setETag(ctx, user.Version)
 	ctx.JSON(http.StatusOK, user)
 }
 
@@ -91,18 +138,19 @@ type listUserRequest struct {
 func (server *Server) listUsers(ctx *gin.Context) {
 	var req listUserRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	arg := db.ListUsersParams{
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		OrganizationID: tenantID(ctx),
+		Limit:          req.PageSize,
+		Offset:         (req.PageID - 1) * req.PageSize,
 	}
 
 	users, err := server.store.ListUsers(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -112,18 +160,19 @@ func (server *Server) listUsers(ctx *gin.Context) {
 func (server *Server) listActiveUsers(ctx *gin.Context) {
 	var req listUserRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	arg := db.ListActiveUsersParams{
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		OrganizationID: tenantID(ctx),
+		Limit:          req.PageSize,
+		Offset:         (req.PageID - 1) * req.PageSize,
 	}
 
 	users, err := server.store.ListActiveUsers(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -133,18 +182,19 @@ func (server *Server) listActiveUsers(ctx *gin.Context) {
 func (server *Server) listAllUsers(ctx *gin.Context) {
 	var req listUserRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	arg := db.ListAllUsersParams{
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		OrganizationID: tenantID(ctx),
+		Limit:          req.PageSize,
+		Offset:         (req.PageID - 1) * req.PageSize,
 	}
 
 	users, err := server.store.ListAllUsers(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -154,31 +204,34 @@ func (server *Server) listAllUsers(ctx *gin.Context) {
 func (server *Server) deleteUser(ctx *gin.Context) {
 	var req idRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	// Check if the user exists
-	user, err := server.store.GetUser(ctx, req.ID)
+	user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: req.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	// Delete the user
-	err = server.store.DeleteUser(ctx, user.ID)
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteUser(ctx, db.DeleteUserParams{OrganizationID: user.OrganizationID, ID: user.ID})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityUser, user.ID, user, nil)}, err
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -190,14 +243,17 @@ type updateUserRequest struct {
 	Active *bool `json:"active" binding:"required"`
 }
 
-func (r *updateUserRequest) toDB(id int64) (db.UpdateUserParams, error) {
+// toDB returns the new values of the user, the preferences not sent stay as they are
+func (r *updateUserRequest) toDB(user db.User) (db.UpdateUserParams, error) {
 	arg := db.UpdateUserParams{
-		ID:     id,
-		Type:   r.Type,
-		Name:   r.Name,
-		Gender: r.Gender,
-		Email:  r.Email,
-		Active: *r.Active,
+		OrganizationID: user.OrganizationID,
+		ID:             user.ID,
+		Type:           r.Type,
+		Name:           r.Name,
+		Gender:         null.NewString(string(r.Gender), true),
+		Email:          r.Email,
+		Active:         *r.Active,
+		Version:        user.Version,
 	}
 	if r.Phone != nil {
 		arg.Phone.SetValid(*r.Phone)
@@ -210,45 +266,155 @@ func (r *updateUserRequest) toDB(id int64) (db.UpdateUserParams, error) {
 		arg.Birth.SetValid(t)
 	}
 
+	var err error
+	arg.Timezone, arg.Locale, arg.Units, err = r.preferences(user.Timezone, user.Locale, user.Units)
+	if err != nil {
+		return db.UpdateUserParams{}, err
+	}
+
 	return arg, nil
 }
 
 func (server *Server) updateUser(ctx *gin.Context) {
 	var u idRequest
 	if err := ctx.ShouldBindUri(&u); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	var req updateUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	// Check if the user exists
-	user, err := server.store.GetUser(ctx, u.ID)
+	user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: u.ID})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, nil)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	arg, err := req.toDB(user.ID)
+	if !checkIfMatch(ctx, user.Version) {
+		return
+	}
+
+	server.saveUser(ctx, user, req)
+}
+
+// newUpdateUserRequest returns the request that would update the user to its current values
+func newUpdateUserRequest(user db.User) updateUserRequest {
+	req := updateUserRequest{
+		createUserRequest: createUserRequest{
+			Type:     user.Type,
+			Name:     user.Name,
+			Gender:   db.GenderType(user.Gender.String),
+			Email:    user.Email,
+			Timezone: &user.Timezone,
+			Locale:   &user.Locale,
+			Units:    &user.Units,
+		},
+		Active: &user.Active,
+	}
+	if user.Phone.Valid {
+		req.Phone = &user.Phone.String
+	}
+	if user.Birth.Valid {
+		birth := user.Birth.Time.Format("2006-01-02")
+		req.Birth = &birth
+	}
+	return req
+}
+
+// patchUser changes only the fields of the user in the patch, null clearing the optional ones
+func (server *Server) patchUser(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// Check if the user exists
+	user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: u.ID})
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if !checkIfMatch(ctx, user.Version) {
+		return
+	}
+
+	var req updateUserRequest
+	if !bindPatch(ctx, newUpdateUserRequest(user), &req) {
+		return
+	}
+
+	server.saveUser(ctx, user, req)
+}
+
+// saveUser updates the user with the values of the request, writing the response
+func (server *Server) saveUser(ctx *gin.Context, user db.User, req updateUserRequest) {
+	arg, err := req.toDB(user)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	updated, err := server.store.UpdateUser(ctx, arg)
+	var updated db.User
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateUser(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityUser, user.ID, user, updated)}, err
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		// the user was updated since it was loaded
+		if err == sql.ErrNoRows {
+			preconditionFailed(ctx)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	setETag(ctx, updated.Version)
 	ctx.JSON(http.StatusOK, updated)
 }
+
+// loadUser gets a user of the current organization, writing the error response if it doesn't exist
+func (server *Server) loadUser(ctx *gin.Context, id int64) (db.User, bool) {
+	user, err := server.store.GetUser(ctx, db.GetUserParams{OrganizationID: tenantID(ctx), ID: id})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, i18n.Errorf("request.invalid_id", "user_id", id)))
+			return user, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return user, false
+	}
+
+	return user, true
+}
+
+// userToday returns the current date in the timezone of the user
+func userToday(user db.User) time.Time {
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		// the timezone was validated when saved, the server may lack it in its zone database
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
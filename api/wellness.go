@@ -0,0 +1,368 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/wellness"
+
+	"github.com/emvi/null"
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityWellness = "wellness"
+
+// wellnessDefaultDays is the period listed when no start date is given
+const wellnessDefaultDays = 28
+
+type wellnessDateRequest struct {
+	ID   int64  `uri:"id" binding:"required,min=1"`
+	Date string `uri:"date" binding:"required,datetime=2006-01-02"`
+}
+
+type wellnessRequest struct {
+	// Weight is in kg and SleepHours in hours
+	Weight       *float64 `json:"weight" binding:"omitempty,min=20,max=300"`
+	RestingHr    *int32   `json:"resting_hr" binding:"omitempty,min=25,max=120"`
+	Hrv          *int32   `json:"hrv" binding:"omitempty,min=1,max=300"`
+	SleepHours   *float64 `json:"sleep_hours" binding:"omitempty,min=0,max=24"`
+	SleepQuality *int32   `json:"sleep_quality" binding:"omitempty,min=1,max=5"`
+	Soreness     *int32   `json:"soreness" binding:"omitempty,min=1,max=5"`
+	Stress       *int32   `json:"stress" binding:"omitempty,min=1,max=5"`
+	CyclePhase   *string  `json:"cycle_phase" binding:"omitempty,oneof=menstrual follicular ovulation luteal"`
+}
+
+func (r *wellnessRequest) toDB(organizationID, userID int64, date time.Time) db.CreateWellnessParams {
+	arg := db.CreateWellnessParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Date:           date,
+	}
+	if r.Weight != nil {
+		arg.Weight.SetValid(*r.Weight)
+	}
+	if r.RestingHr != nil {
+		arg.RestingHr.SetValid(*r.RestingHr)
+	}
+	if r.Hrv != nil {
+		arg.Hrv.SetValid(*r.Hrv)
+	}
+	if r.SleepHours != nil {
+		arg.SleepHours.SetValid(*r.SleepHours)
+	}
+	if r.SleepQuality != nil {
+		arg.SleepQuality.SetValid(*r.SleepQuality)
+	}
+	if r.Soreness != nil {
+		arg.Soreness.SetValid(*r.Soreness)
+	}
+	if r.Stress != nil {
+		arg.Stress.SetValid(*r.Stress)
+	}
+	if r.CyclePhase != nil {
+		arg.CyclePhase.SetValid(*r.CyclePhase)
+	}
+
+	return arg
+}
+
+type createWellnessRequest struct {
+	Date string `json:"date" binding:"required,datetime=2006-01-02"`
+	wellnessRequest
+}
+
+// wellnessResponse is a check-in along with its readiness score
+type wellnessResponse struct {
+	db.Wellness
+	Readiness null.Int64 `json:"readiness"`
+}
+
+type listWellnessRequest struct {
+	StartDate string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+func (server *Server) listWellness(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req listWellnessRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	// we can ignore the errors because the values were already validated
+	end := userToday(user)
+	if req.EndDate != "" {
+		end, _ = time.Parse("2006-01-02", req.EndDate)
+	}
+	start := end.AddDate(0, 0, 1-wellnessDefaultDays)
+	if req.StartDate != "" {
+		start, _ = time.Parse("2006-01-02", req.StartDate)
+	}
+	if end.Before(start) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, i18n.Errorf("request.end_before_start")))
+		return
+	}
+
+	rsp, err := server.wellnessInPeriod(ctx, user, start, end)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) getWellness(ctx *gin.Context) {
+	var req wellnessDateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	date, _ := time.Parse("2006-01-02", req.Date)
+	rsp, err := server.wellnessInPeriod(ctx, user, date, date)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if len(rsp) == 0 {
+		ctx.JSON(http.StatusNotFound, nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp[0])
+}
+
+func (server *Server) createWellness(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createWellnessRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	date, _ := time.Parse("2006-01-02", req.Date)
+	_, err := server.store.GetWellness(ctx, db.GetWellnessParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		Date:           date,
+	})
+	if err == nil {
+		ctx.JSON(http.StatusConflict, errorResponse(ctx, i18n.Errorf("wellness.exists")))
+		return
+	}
+	if err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	var created db.Wellness
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		created, err = store.CreateWellness(ctx, req.toDB(user.OrganizationID, user.ID, date))
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityWellness, created.ID, nil, created)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, created)
+}
+
+func (server *Server) updateWellness(ctx *gin.Context) {
+	var r wellnessDateRequest
+	if err := ctx.ShouldBindUri(&r); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req wellnessRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	existing, ok := server.loadWellness(ctx, r)
+	if !ok {
+		return
+	}
+
+	arg := req.toDB(existing.OrganizationID, existing.UserID, existing.Date)
+	var updated db.Wellness
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		updated, err = store.UpdateWellness(ctx, db.UpdateWellnessParams(arg))
+		return []db.AuditEntry{auditEntry(auditActionUpdate, auditEntityWellness, existing.ID, existing, updated)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (server *Server) deleteWellness(ctx *gin.Context) {
+	var req wellnessDateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	existing, ok := server.loadWellness(ctx, req)
+	if !ok {
+		return
+	}
+
+	err := server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteWellness(ctx, db.DeleteWellnessParams{
+			OrganizationID: existing.OrganizationID,
+			UserID:         existing.UserID,
+			Date:           existing.Date,
+		})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityWellness, existing.ID, existing, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// loadWellness gets the check-in of a user of the current organization on a date, writing the error
+// response if it doesn't exist
+func (server *Server) loadWellness(ctx *gin.Context, req wellnessDateRequest) (db.Wellness, bool) {
+	date, _ := time.Parse("2006-01-02", req.Date)
+	w, err := server.store.GetWellness(ctx, db.GetWellnessParams{
+		OrganizationID: tenantID(ctx),
+		UserID:         req.ID,
+		Date:           date,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return w, false
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return w, false
+	}
+
+	return w, true
+}
+
+// wellnessInPeriod returns the check-ins of a user between two dates with their readiness, loading
+// the days before start for the baseline
+func (server *Server) wellnessInPeriod(ctx *gin.Context, user db.User, start, end time.Time) ([]wellnessResponse, error) {
+	entries, err := server.store.ListWellnessByUserInPeriod(ctx, db.ListWellnessByUserInPeriodParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+		StartDate:      start.AddDate(0, 0, -wellness.BaselineDays),
+		EndDate:        end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := []wellnessResponse{}
+	for i, e := range entries {
+		if e.Date.Before(start) {
+			continue
+		}
+		rsp = append(rsp, wellnessResponse{Wellness: e, Readiness: readiness(entries[:i], e)})
+	}
+
+	return rsp, nil
+}
+
+// readiness scores a check-in against the ones of the baseline days before it, previous being sorted by date
+func readiness(previous []db.Wellness, w db.Wellness) null.Int64 {
+	from := w.Date.AddDate(0, 0, -wellness.BaselineDays)
+	var baseline []wellness.CheckIn
+	for _, p := range previous {
+		if !p.Date.Before(from) {
+			baseline = append(baseline, checkIn(p))
+		}
+	}
+
+	var score null.Int64
+	if s, ok := wellness.Readiness(checkIn(w), baseline); ok {
+		score.SetValid(int64(s))
+	}
+	return score
+}
+
+func checkIn(w db.Wellness) wellness.CheckIn {
+	return wellness.CheckIn{
+		RestingHR:    int(w.RestingHr.Int32),
+		HRV:          int(w.Hrv.Int32),
+		SleepHours:   w.SleepHours.Float64,
+		SleepQuality: int(w.SleepQuality.Int32),
+		Soreness:     int(w.Soreness.Int32),
+		Stress:       int(w.Stress.Int32),
+	}
+}
+
+// withReadiness adds the readiness of the day to each training response
+func (server *Server) withReadiness(ctx *gin.Context, trainings []trainingResponse) error {
+	if len(trainings) == 0 {
+		return nil
+	}
+
+	start, end := trainings[0].Date, trainings[0].Date
+	for _, t := range trainings {
+		if t.Date.Before(start) {
+			start = t.Date
+		}
+		if t.Date.After(end) {
+			end = t.Date
+		}
+	}
+
+	user := db.User{OrganizationID: trainings[0].OrganizationID, ID: trainings[0].UserID}
+	entries, err := server.wellnessInPeriod(ctx, user, start, end)
+	if err != nil {
+		return err
+	}
+
+	byDate := make(map[string]null.Int64, len(entries))
+	for _, e := range entries {
+		byDate[e.Date.Format("2006-01-02")] = e.Readiness
+	}
+	for i := range trainings {
+		trainings[i].Readiness = byDate[trainings[i].Date.Format("2006-01-02")]
+	}
+
+	return nil
+}
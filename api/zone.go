@@ -0,0 +1,301 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/i18n"
+	"github.com/rondondev/runapp/sports"
+	"github.com/rondondev/runapp/zones"
+
+	"github.com/gin-gonic/gin"
+)
+
+const auditEntityZoneModel = "zone_model"
+
+// zoneMethodSports lists the sports each zone method applies to, heart rate zones apply to all of them
+var zoneMethodSports = map[db.ZoneMethod][]string{
+	db.ZoneMethodPace:  {sports.Running},
+	db.ZoneMethodPower: {sports.Cycling},
+	db.ZoneMethodCss:   {sports.Swimming},
+}
+
+type zoneModelRequest struct {
+	ID          int64 `uri:"id" binding:"required,min=1"`
+	ZoneModelID int64 `uri:"zone_model_id" binding:"required,min=1"`
+}
+
+type createZoneModelRequest struct {
+	Sport         string        `json:"sport" binding:"required"`
+	Method        db.ZoneMethod `json:"method" binding:"required,oneof=hr_max hr_lthr hr_karvonen pace power css"`
+	Threshold     int32         `json:"threshold" binding:"required,min=1"`
+	Resting       *int32        `json:"resting" binding:"omitempty,min=1"`
+	EffectiveFrom string        `json:"effective_from" binding:"required,datetime=2006-01-02"`
+}
+
+func (r *createZoneModelRequest) toDB(organizationID, userID int64) (db.CreateZoneModelParams, error) {
+	if sports, ok := zoneMethodSports[r.Method]; ok && !containsSport(sports, r.Sport) {
+		return db.CreateZoneModelParams{}, i18n.Errorf("zone.method_sport", r.Method, r.Sport)
+	}
+
+	effectiveFrom, err := time.Parse("2006-01-02", r.EffectiveFrom)
+	if err != nil {
+		return db.CreateZoneModelParams{}, err
+	}
+
+	arg := db.CreateZoneModelParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Sport:          r.Sport,
+		Method:         r.Method,
+		Threshold:      r.Threshold,
+		EffectiveFrom:  effectiveFrom,
+	}
+	if r.Resting != nil {
+		arg.Resting.SetValid(*r.Resting)
+	}
+
+	if err = zoneModel(db.ZoneModel{Method: arg.Method, Threshold: arg.Threshold, Resting: arg.Resting}).Validate(); err != nil {
+		return db.CreateZoneModelParams{}, err
+	}
+
+	return arg, nil
+}
+
+// zoneModelResponse is a zone model along with the zones computed from it
+type zoneModelResponse struct {
+	db.ZoneModel
+	Zones []zones.Zone `json:"zones"`
+}
+
+func newZoneModelResponse(model db.ZoneModel) (zoneModelResponse, error) {
+	computed, err := zoneModel(model).Zones()
+	return zoneModelResponse{ZoneModel: model, Zones: computed}, err
+}
+
+func (server *Server) listZoneModels(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.loadUser(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	models, err := server.store.ListZoneModelsByUser(ctx, db.ListZoneModelsByUserParams{
+		OrganizationID: user.OrganizationID,
+		UserID:         user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]zoneModelResponse, len(models))
+	for i, model := range models {
+		if rsp[i], err = newZoneModelResponse(model); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) createZoneModel(ctx *gin.Context) {
+	var u idRequest
+	if err := ctx.ShouldBindUri(&u); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createZoneModelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, ok := server.loadSport(ctx, req.Sport); !ok {
+		return
+	}
+
+	user, ok := server.loadUser(ctx, u.ID)
+	if !ok {
+		return
+	}
+
+	arg, err := req.toDB(user.OrganizationID, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var model db.ZoneModel
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		var err error
+		model, err = store.CreateZoneModel(ctx, arg)
+		return []db.AuditEntry{auditEntry(auditActionCreate, auditEntityZoneModel, model.ID, nil, model)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp, err := newZoneModelResponse(model)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) deleteZoneModel(ctx *gin.Context) {
+	var req zoneModelRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	model, err := server.store.GetZoneModel(ctx, db.GetZoneModelParams{
+		OrganizationID: tenantID(ctx),
+		ID:             req.ZoneModelID,
+	})
+	if err == nil && model.UserID != req.ID {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	err = server.auditTx(ctx, func(store db.Store) ([]db.AuditEntry, error) {
+		err := store.DeleteZoneModel(ctx, db.DeleteZoneModelParams{
+			OrganizationID: model.OrganizationID,
+			ID:             model.ID,
+		})
+		return []db.AuditEntry{auditEntry(auditActionDelete, auditEntityZoneModel, model.ID, model, nil)}, err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// trainingTarget is a zone reference of a training resolved with one of the athlete zone models
+type trainingTarget struct {
+	ZoneModelID int64         `json:"zone_model_id"`
+	Method      db.ZoneMethod `json:"method"`
+	zones.Zone
+}
+
+type trainingTargetsResponse struct {
+	TrainingID int64            `json:"training_id"`
+	Intensity  string           `json:"intensity"`
+	Targets    []trainingTarget `json:"targets"`
+}
+
+func (server *Server) getTrainingTargets(ctx *gin.Context) {
+	var req idRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	training, err := server.store.GetTraining(ctx, db.GetTrainingParams{OrganizationID: tenantID(ctx), ID: req.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, nil)
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := trainingTargetsResponse{
+		TrainingID: training.ID,
+		Intensity:  training.Intensity.String,
+		Targets:    []trainingTarget{},
+	}
+
+	// Free text intensities have no numeric targets
+	if _, _, err = zones.ParseRef(training.Intensity.String); err != nil {
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	rsp.Targets, err = server.resolveTargets(ctx, training)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// resolveTargets resolves the zone reference of a training with each zone model of the athlete
+// effective on the training date
+func (server *Server) resolveTargets(ctx *gin.Context, training db.Training) ([]trainingTarget, error) {
+	models, err := server.store.ListEffectiveZoneModels(ctx, db.ListEffectiveZoneModelsParams{
+		OrganizationID: training.OrganizationID,
+		UserID:         training.UserID,
+		Sport:          training.Sport,
+		OnDate:         training.Date,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []trainingTarget{}
+	seen := make(map[db.ZoneMethod]bool)
+	for _, model := range models {
+		// The models are sorted by method, latest first
+		if seen[model.Method] {
+			continue
+		}
+		seen[model.Method] = true
+
+		zone, err := zoneModel(model).Resolve(training.Intensity.String)
+		if err != nil {
+			if errors.Is(err, zones.ErrInvalidRef) {
+				// The reference has more zones than the model
+				continue
+			}
+			return nil, err
+		}
+		targets = append(targets, trainingTarget{ZoneModelID: model.ID, Method: model.Method, Zone: zone})
+	}
+
+	return targets, nil
+}
+
+func zoneModel(model db.ZoneModel) zones.Model {
+	return zones.Model{
+		Method:    zones.Method(model.Method),
+		Threshold: int(model.Threshold),
+		Resting:   int(model.Resting.Int32),
+	}
+}
+
+func containsSport(sports []string, sport string) bool {
+	for _, s := range sports {
+		if s == sport {
+			return true
+		}
+	}
+	return false
+}
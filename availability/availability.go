@@ -0,0 +1,103 @@
+// Package availability checks the trainings planned for an athlete against the weekly windows
+// they declared they can train in and the one-off periods they can't train, like a trip.
+//
+// Minutes are counted from midnight and durations are in seconds. An athlete without any window
+// is considered available every day.
+package availability
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kinds of conflicts
+const (
+	KindBlackout       = "blackout"
+	KindUnavailableDay = "unavailable_day"
+	KindTooLong        = "too_long"
+)
+
+// Window is a recurring weekly time window
+type Window struct {
+	Weekday     time.Weekday
+	StartMinute int
+	EndMinute   int
+	// MaxDuration limits the training time in the window, 0 means the whole window
+	MaxDuration int
+}
+
+// Duration returns the training time available in the window
+func (w Window) Duration() int {
+	length := (w.EndMinute - w.StartMinute) * 60
+	if w.MaxDuration > 0 && w.MaxDuration < length {
+		return w.MaxDuration
+	}
+	return length
+}
+
+// Blackout is a period the athlete can't train, both dates included
+type Blackout struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Reason    string
+}
+
+// Calendar holds the availability of an athlete
+type Calendar struct {
+	Windows   []Window
+	Blackouts []Blackout
+}
+
+// Conflict is the reason a training doesn't fit the calendar
+type Conflict struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Check returns the conflicts of a training on date lasting duration, an unknown duration being 0
+func (c Calendar) Check(date time.Time, duration int) []Conflict {
+	var conflicts []Conflict
+
+	day := date.Format("2006-01-02")
+	for _, b := range c.Blackouts {
+		if day < b.StartDate.Format("2006-01-02") || day > b.EndDate.Format("2006-01-02") {
+			continue
+		}
+		msg := fmt.Sprintf("the athlete is unavailable from %s to %s", b.StartDate.Format("2006-01-02"), b.EndDate.Format("2006-01-02"))
+		if b.Reason != "" {
+			msg += ": " + b.Reason
+		}
+		conflicts = append(conflicts, Conflict{Kind: KindBlackout, Message: msg})
+	}
+
+	if len(c.Windows) == 0 {
+		return conflicts
+	}
+
+	var available int
+	var windows int
+	for _, w := range c.Windows {
+		if w.Weekday == date.Weekday() {
+			available += w.Duration()
+			windows++
+		}
+	}
+
+	weekday := strings.ToLower(date.Weekday().String())
+	switch {
+	case windows == 0:
+		conflicts = append(conflicts, Conflict{
+			Kind:    KindUnavailableDay,
+			Message: fmt.Sprintf("the athlete isn't available on %ss", weekday),
+		})
+	case duration > available:
+		conflicts = append(conflicts, Conflict{
+			Kind: KindTooLong,
+			Message: fmt.Sprintf("the training lasts %s but the athlete has %s on %ss",
+				time.Duration(duration)*time.Second, time.Duration(available)*time.Second, weekday),
+		})
+	}
+
+	return conflicts
+}
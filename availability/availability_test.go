@@ -0,0 +1,62 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowDuration(t *testing.T) {
+	require.Equal(t, 5400, Window{StartMinute: 360, EndMinute: 450}.Duration())
+	require.Equal(t, 3600, Window{StartMinute: 360, EndMinute: 450, MaxDuration: 3600}.Duration())
+	require.Equal(t, 5400, Window{StartMinute: 360, EndMinute: 450, MaxDuration: 7200}.Duration())
+}
+
+func TestCheck(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	calendar := Calendar{
+		Windows: []Window{
+			{Weekday: time.Monday, StartMinute: 360, EndMinute: 420},
+			{Weekday: time.Monday, StartMinute: 1080, EndMinute: 1140, MaxDuration: 1800},
+			{Weekday: time.Saturday, StartMinute: 480, EndMinute: 720},
+		},
+		Blackouts: []Blackout{
+			{StartDate: date("2021-06-10"), EndDate: date("2021-06-14"), Reason: "trip"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		calendar Calendar
+		date     string
+		duration int
+		kinds    []string
+	}{
+		{"available", calendar, "2021-06-07", 3600, nil},
+		{"unknown duration", calendar, "2021-06-07", 0, nil},
+		{"sum of the windows", calendar, "2021-06-07", 5400, nil},
+		{"too long", calendar, "2021-06-07", 5401, []string{KindTooLong}},
+		{"unavailable day", calendar, "2021-06-08", 0, []string{KindUnavailableDay}},
+		{"blackout", calendar, "2021-06-12", 3600, []string{KindBlackout}},
+		{"blackout on an unavailable day", calendar, "2021-06-10", 0, []string{KindBlackout, KindUnavailableDay}},
+		{"last day of the blackout", calendar, "2021-06-14", 3600, []string{KindBlackout}},
+		{"no window", Calendar{}, "2021-06-08", 36000, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var kinds []string
+			for _, c := range tc.calendar.Check(date(tc.date), tc.duration) {
+				require.NotEmpty(t, c.Message)
+				kinds = append(kinds, c.Kind)
+			}
+			require.Equal(t, tc.kinds, kinds)
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package calc holds the running, swimming and cycling calculators shared by the API.
+//
+// Distances are in meters, times in seconds and speeds in meters per second unless a
+// function says otherwise.
+package calc
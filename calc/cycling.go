@@ -0,0 +1,49 @@
+package calc
+
+import "math"
+
+// Default conditions of the cycling speed estimate
+const (
+	DefaultMass = 80.0
+	DefaultCdA  = 0.32
+	DefaultCrr  = 0.005
+
+	gravity       = 9.80665
+	airDensity    = 1.225
+	drivetrainEff = 0.976
+)
+
+// Rider describes a rider and bike for the cycling speed estimate. Mass is the total mass in kg,
+// CdA the drag area in m², Crr the rolling resistance coefficient and Grade the road slope,
+// e.g. 0.05 for 5%.
+type Rider struct {
+	Mass  float64
+	CdA   float64
+	Crr   float64
+	Grade float64
+}
+
+// Power returns the power in watts the rider needs to hold a speed, without wind
+func (r Rider) Power(speed float64) float64 {
+	angle := math.Atan(r.Grade)
+	resistance := gravity * r.Mass * (r.Crr*math.Cos(angle) + math.Sin(angle))
+	drag := 0.5 * airDensity * r.CdA * speed * speed
+
+	return (resistance + drag) * speed / drivetrainEff
+}
+
+// CyclingSpeed estimates the speed the rider holds with the given power
+func CyclingSpeed(r Rider, power float64) float64 {
+	// the power grows with the speed, so the speed can be found by bisection
+	low, high := 0.0, 40.0
+	for i := 0; i < 100 && high-low > 0.0001; i++ {
+		mid := (low + high) / 2
+		if r.Power(mid) < power {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return (low + high) / 2
+}
@@ -0,0 +1,21 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCyclingSpeed(t *testing.T) {
+	flat := Rider{Mass: DefaultMass, CdA: DefaultCdA, Crr: DefaultCrr}
+	climb := flat
+	climb.Grade = 0.05
+
+	speed := CyclingSpeed(flat, 200)
+	require.InDelta(t, 200, flat.Power(speed), 0.1)
+	require.InDelta(t, 33.5, Kmh(speed), 0.5)
+
+	// climbing is slower and more power is faster
+	require.Less(t, CyclingSpeed(climb, 200), speed)
+	require.Greater(t, CyclingSpeed(flat, 300), speed)
+}
@@ -0,0 +1,58 @@
+package calc
+
+import "math"
+
+// Distance units and common race distances, in meters
+const (
+	Mile         = 1609.344
+	Yard         = 0.9144
+	HalfMarathon = 21097.5
+	Marathon     = 42195.0
+)
+
+// Speed returns the speed of a distance covered in the given time
+func Speed(distance, seconds float64) float64 {
+	return distance / seconds
+}
+
+// FinishTime returns the time it takes to cover a distance at the given speed
+func FinishTime(distance, speed float64) float64 {
+	return distance / speed
+}
+
+// PacePerKm converts a speed to a pace in seconds per km
+func PacePerKm(speed float64) float64 {
+	return 1000 / speed
+}
+
+// PacePerMile converts a speed to a pace in seconds per mile
+func PacePerMile(speed float64) float64 {
+	return Mile / speed
+}
+
+// SpeedFromPace converts a pace in seconds per km to a speed
+func SpeedFromPace(pacePerKm float64) float64 {
+	return 1000 / pacePerKm
+}
+
+// Kmh converts a speed to km/h
+func Kmh(speed float64) float64 {
+	return speed * 3.6
+}
+
+// Mph converts a speed to miles per hour
+func Mph(speed float64) float64 {
+	return speed * 3600 / Mile
+}
+
+// SwimPace returns the paces per 100m and per 100yd of a swim
+func SwimPace(distance, seconds float64) (per100m, per100yd float64) {
+	speed := Speed(distance, seconds)
+	return 100 / speed, 100 * Yard / speed
+}
+
+// Round rounds a value to the given number of decimals
+func Round(value float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(value*p) / p
+}
@@ -0,0 +1,29 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPace(t *testing.T) {
+	// 10k in 50:00
+	speed := Speed(10000, 3000)
+	require.InDelta(t, 300, PacePerKm(speed), 0.001)
+	require.InDelta(t, 482.8, PacePerMile(speed), 0.1)
+	require.InDelta(t, 12, Kmh(speed), 0.001)
+	require.InDelta(t, 7.46, Mph(speed), 0.01)
+	require.InDelta(t, 3000, FinishTime(10000, SpeedFromPace(300)), 0.001)
+	require.InDelta(t, 12658.5, FinishTime(Marathon, SpeedFromPace(300)), 0.001)
+}
+
+func TestSwimPace(t *testing.T) {
+	per100m, per100yd := SwimPace(400, 360)
+	require.InDelta(t, 90, per100m, 0.001)
+	require.InDelta(t, 82.3, per100yd, 0.01)
+}
+
+func TestRound(t *testing.T) {
+	require.Equal(t, 3.14, Round(3.14159, 2))
+	require.Equal(t, 3.0, Round(3.14159, 0))
+}
@@ -0,0 +1,11 @@
+package calc
+
+import "math"
+
+// RiegelExponent is the fatigue factor of the Riegel formula
+const RiegelExponent = 1.06
+
+// Riegel predicts the time over a target distance from the time run over another distance
+func Riegel(distance, seconds, target float64) float64 {
+	return seconds * math.Pow(target/distance, RiegelExponent)
+}
@@ -0,0 +1,13 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiegel(t *testing.T) {
+	// 10k in 40:00 predicts a half marathon around 1:28:15
+	require.InDelta(t, 5295, Riegel(10000, 2400, 21097.5), 1)
+	require.InDelta(t, 2400, Riegel(10000, 2400, 10000), 0.001)
+}
@@ -0,0 +1,72 @@
+package calc
+
+import "math"
+
+// Fractions of the VDOT the training paces are run at, after Daniels' Running Formula
+const (
+	easyLow    = 0.62
+	easyHigh   = 0.70
+	threshold  = 0.88
+	interval   = 0.975
+	repetition = 1.07
+)
+
+// TrainingPaces are the Daniels' training paces of a VDOT, in seconds per km. Easy runs have a
+// range, from the fastest to the slowest pace.
+type TrainingPaces struct {
+	EasyFast   float64 `json:"easy_fast"`
+	EasySlow   float64 `json:"easy_slow"`
+	Marathon   float64 `json:"marathon"`
+	Threshold  float64 `json:"threshold"`
+	Interval   float64 `json:"interval"`
+	Repetition float64 `json:"repetition"`
+}
+
+// VDOT returns the Jack Daniels' VDOT of a race result, using the Daniels-Gilbert oxygen
+// cost and drop dead formulas
+func VDOT(distance, seconds float64) float64 {
+	minutes := seconds / 60
+	velocity := distance / minutes
+
+	vo2 := -4.60 + 0.182258*velocity + 0.000104*velocity*velocity
+	fraction := 0.8 + 0.1894393*math.Exp(-0.012778*minutes) + 0.2989558*math.Exp(-0.1932605*minutes)
+
+	return vo2 / fraction
+}
+
+// PredictTime returns the time a runner of the given VDOT is expected to run the distance in
+func PredictTime(vdot, distance float64) float64 {
+	// VDOT decreases as the time grows, so the time can be found by bisection
+	low, high := 1.0, 24*60*60.0
+	for i := 0; i < 100 && high-low > 0.01; i++ {
+		mid := (low + high) / 2
+		if VDOT(distance, mid) > vdot {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return (low + high) / 2
+}
+
+// Paces returns the training paces of a VDOT
+func Paces(vdot float64) TrainingPaces {
+	return TrainingPaces{
+		EasyFast:   vdotPace(vdot, easyHigh),
+		EasySlow:   vdotPace(vdot, easyLow),
+		Marathon:   math.Round(PredictTime(vdot, Marathon) / (Marathon / 1000)),
+		Threshold:  vdotPace(vdot, threshold),
+		Interval:   vdotPace(vdot, interval),
+		Repetition: vdotPace(vdot, repetition),
+	}
+}
+
+// vdotPace returns the pace in seconds per km at which the oxygen cost is the given fraction of the VDOT
+func vdotPace(vdot, fraction float64) float64 {
+	// solve the oxygen cost formula for the velocity, in meters per minute
+	a, b, c := 0.000104, 0.182258, -4.60-fraction*vdot
+	velocity := (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+
+	return math.Round(60000 / velocity)
+}
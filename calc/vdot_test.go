@@ -0,0 +1,53 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVDOT(t *testing.T) {
+	testCases := []struct {
+		name     string
+		distance float64
+		seconds  float64
+		vdot     float64
+	}{
+		// values from the Daniels' Running Formula tables
+		{"5k 20:00", 5000, 20 * 60, 49.8},
+		{"10k 40:00", 10000, 40 * 60, 51.9},
+		{"marathon 3:00:00", 42195, 3 * 60 * 60, 53.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.InDelta(t, tc.vdot, VDOT(tc.distance, tc.seconds), 0.1)
+		})
+	}
+}
+
+func TestPredictTime(t *testing.T) {
+	vdot := VDOT(5000, 20*60)
+	require.InDelta(t, 20*60, PredictTime(vdot, 5000), 0.5)
+
+	// a faster runner runs the same distance in less time
+	require.Less(t, PredictTime(vdot+5, 10000), PredictTime(vdot, 10000))
+}
+
+func TestPaces(t *testing.T) {
+	// VDOT 50 paces from the Daniels' Running Formula tables, within a couple of seconds per km
+	paces := Paces(50)
+	require.InDelta(t, 307, paces.EasyFast, 3)
+	require.InDelta(t, 338, paces.EasySlow, 3)
+	require.InDelta(t, 271, paces.Marathon, 3)
+	require.InDelta(t, 255, paces.Threshold, 3)
+	require.InDelta(t, 235, paces.Interval, 3)
+	require.InDelta(t, 218, paces.Repetition, 3)
+
+	// paces get faster with the intensity
+	require.Greater(t, paces.EasySlow, paces.EasyFast)
+	require.Greater(t, paces.EasyFast, paces.Marathon)
+	require.Greater(t, paces.Marathon, paces.Threshold)
+	require.Greater(t, paces.Threshold, paces.Interval)
+	require.Greater(t, paces.Interval, paces.Repetition)
+}
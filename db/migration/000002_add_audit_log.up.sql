@@ -0,0 +1,18 @@
+CREATE TABLE "audit_log"
+(
+    "id"          bigserial PRIMARY KEY,
+    "actor_id"    bigint,
+    "action"      varchar     NOT NULL,
+    "entity_type" varchar     NOT NULL,
+    "entity_id"   bigint      NOT NULL,
+    "before"      jsonb,
+    "after"       jsonb,
+    "request_id"  varchar     NOT NULL,
+    "created_at"  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "audit_log" ("entity_type", "entity_id");
+
+CREATE INDEX ON "audit_log" ("actor_id");
+
+CREATE INDEX ON "audit_log" ("created_at");
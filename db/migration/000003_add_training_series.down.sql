@@ -0,0 +1,2 @@
+ALTER TABLE training DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS training_series;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE "training_series"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint         NOT NULL,
+    "rrule"      varchar        NOT NULL,
+    "dtstart"    date           NOT NULL,
+    "exdate"     varchar        NOT NULL DEFAULT '',
+    "sport"      training_sport NOT NULL,
+    "type"       varchar,
+    "intensity"  varchar,
+    "details"    varchar        NOT NULL,
+    "created_at" timestamptz    NOT NULL DEFAULT now(),
+    "deleted_at" timestamptz
+);
+
+ALTER TABLE "training_series"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE INDEX ON "training_series" ("user_id");
+
+ALTER TABLE "training"
+    ADD COLUMN "series_id" bigint REFERENCES "training_series" ("id");
+
+CREATE INDEX ON "training" ("series_id");
@@ -0,0 +1,4 @@
+ALTER TABLE training DROP COLUMN IF EXISTS group_training_id;
+DROP TABLE IF EXISTS group_training;
+DROP TABLE IF EXISTS group_member;
+DROP TABLE IF EXISTS groups;
\ No newline at end of file
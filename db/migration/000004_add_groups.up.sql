@@ -0,0 +1,50 @@
+CREATE TABLE "groups"
+(
+    "id"         bigserial PRIMARY KEY,
+    "name"       varchar     NOT NULL,
+    "coach_id"   bigint,
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    "deleted_at" timestamptz
+);
+
+CREATE TABLE "group_member"
+(
+    "group_id"   bigint      NOT NULL,
+    "user_id"    bigint      NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY ("group_id", "user_id")
+);
+
+CREATE TABLE "group_training"
+(
+    "id"         bigserial PRIMARY KEY,
+    "group_id"   bigint         NOT NULL,
+    "date"       date           NOT NULL,
+    "sport"      training_sport NOT NULL,
+    "type"       varchar,
+    "intensity"  varchar,
+    "details"    varchar        NOT NULL,
+    "created_at" timestamptz    NOT NULL DEFAULT now(),
+    "deleted_at" timestamptz
+);
+
+ALTER TABLE "groups"
+    ADD FOREIGN KEY ("coach_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "group_member"
+    ADD FOREIGN KEY ("group_id") REFERENCES "groups" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "group_member"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "group_training"
+    ADD FOREIGN KEY ("group_id") REFERENCES "groups" ("id");
+
+CREATE INDEX ON "group_member" ("user_id");
+
+CREATE INDEX ON "group_training" ("group_id");
+
+ALTER TABLE "training"
+    ADD COLUMN "group_training_id" bigint REFERENCES "group_training" ("id");
+
+CREATE INDEX ON "training" ("group_training_id");
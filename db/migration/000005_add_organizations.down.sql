@@ -0,0 +1,8 @@
+ALTER TABLE audit_log DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE group_training DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE groups DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE training_series DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE training DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE users DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization;
\ No newline at end of file
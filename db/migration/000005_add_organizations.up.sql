@@ -0,0 +1,61 @@
+CREATE TABLE "organization"
+(
+    "id"         bigserial PRIMARY KEY,
+    "name"       varchar     NOT NULL,
+    "slug"       varchar     NOT NULL,
+    "settings"   jsonb       NOT NULL DEFAULT '{}',
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    "deleted_at" timestamptz
+);
+
+CREATE UNIQUE INDEX ON "organization" ("slug");
+
+-- existing rows are moved to a default organization
+INSERT INTO "organization" ("name", "slug")
+VALUES ('Default', 'default');
+
+ALTER TABLE "users"
+    ADD COLUMN "organization_id" bigint REFERENCES "organization" ("id");
+ALTER TABLE "training"
+    ADD COLUMN "organization_id" bigint REFERENCES "organization" ("id");
+ALTER TABLE "training_feedback"
+    ADD COLUMN "organization_id" bigint REFERENCES "organization" ("id");
+ALTER TABLE "training_series"
+    ADD COLUMN "organization_id" bigint REFERENCES "organization" ("id");
+ALTER TABLE "groups"
+    ADD COLUMN "organization_id" bigint REFERENCES "organization" ("id");
+ALTER TABLE "group_training"
+    ADD COLUMN "organization_id" bigint REFERENCES "organization" ("id");
+ALTER TABLE "audit_log"
+    ADD COLUMN "organization_id" bigint REFERENCES "organization" ("id");
+
+UPDATE "users" SET "organization_id" = (SELECT "id" FROM "organization" WHERE "slug" = 'default');
+UPDATE "training" SET "organization_id" = (SELECT "id" FROM "organization" WHERE "slug" = 'default');
+UPDATE "training_feedback" SET "organization_id" = (SELECT "id" FROM "organization" WHERE "slug" = 'default');
+UPDATE "training_series" SET "organization_id" = (SELECT "id" FROM "organization" WHERE "slug" = 'default');
+UPDATE "groups" SET "organization_id" = (SELECT "id" FROM "organization" WHERE "slug" = 'default');
+UPDATE "group_training" SET "organization_id" = (SELECT "id" FROM "organization" WHERE "slug" = 'default');
+UPDATE "audit_log" SET "organization_id" = (SELECT "id" FROM "organization" WHERE "slug" = 'default');
+
+ALTER TABLE "users"
+    ALTER COLUMN "organization_id" SET NOT NULL;
+ALTER TABLE "training"
+    ALTER COLUMN "organization_id" SET NOT NULL;
+ALTER TABLE "training_feedback"
+    ALTER COLUMN "organization_id" SET NOT NULL;
+ALTER TABLE "training_series"
+    ALTER COLUMN "organization_id" SET NOT NULL;
+ALTER TABLE "groups"
+    ALTER COLUMN "organization_id" SET NOT NULL;
+ALTER TABLE "group_training"
+    ALTER COLUMN "organization_id" SET NOT NULL;
+ALTER TABLE "audit_log"
+    ALTER COLUMN "organization_id" SET NOT NULL;
+
+CREATE INDEX ON "users" ("organization_id");
+CREATE INDEX ON "training" ("organization_id");
+CREATE INDEX ON "training_feedback" ("organization_id");
+CREATE INDEX ON "training_series" ("organization_id");
+CREATE INDEX ON "groups" ("organization_id");
+CREATE INDEX ON "group_training" ("organization_id");
+CREATE INDEX ON "audit_log" ("organization_id");
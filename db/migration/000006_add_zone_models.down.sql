@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS zone_model;
+DROP TYPE IF EXISTS zone_method;
\ No newline at end of file
@@ -0,0 +1,32 @@
+CREATE TYPE "zone_method" AS ENUM (
+    'hr_max',
+    'hr_lthr',
+    'hr_karvonen',
+    'pace',
+    'power',
+    'css'
+    );
+
+CREATE TABLE "zone_model"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint         NOT NULL,
+    "user_id"         bigint         NOT NULL,
+    "sport"           training_sport NOT NULL,
+    "method"          zone_method    NOT NULL,
+    "threshold"       int            NOT NULL,
+    "resting"         int,
+    "effective_from"  date           NOT NULL,
+    "created_at"      timestamptz    NOT NULL DEFAULT now(),
+    "deleted_at"      timestamptz
+);
+
+ALTER TABLE "zone_model"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "zone_model"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "zone_model" ("organization_id");
+
+CREATE INDEX ON "zone_model" ("user_id", "sport", "effective_from");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS test_result;
+DROP TYPE IF EXISTS test_protocol;
\ No newline at end of file
@@ -0,0 +1,39 @@
+CREATE TYPE "test_protocol" AS ENUM (
+    'cooper',
+    'tt30',
+    'ftp20',
+    'css'
+    );
+
+CREATE TABLE "test_result"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint        NOT NULL,
+    "user_id"         bigint        NOT NULL,
+    "training_id"     bigint        NOT NULL,
+    "protocol"        test_protocol NOT NULL,
+    "distance"        int,
+    "avg_power"       int,
+    "avg_hr"          int,
+    "time_400m"       int,
+    "time_200m"       int,
+    "vo2max"          double precision,
+    "threshold_pace"  int,
+    "ftp"             int,
+    "css"             int,
+    "lthr"            int,
+    "created_at"      timestamptz   NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "test_result"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "test_result"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "test_result"
+    ADD FOREIGN KEY ("training_id") REFERENCES "training" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "test_result" ("training_id");
+
+CREATE INDEX ON "test_result" ("user_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS race;
+DROP TYPE IF EXISTS race_priority;
\ No newline at end of file
@@ -0,0 +1,29 @@
+CREATE TYPE "race_priority" AS ENUM (
+    'A',
+    'B',
+    'C'
+    );
+
+CREATE TABLE "race"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint         NOT NULL,
+    "user_id"         bigint         NOT NULL,
+    "name"            varchar        NOT NULL,
+    "date"            date           NOT NULL,
+    "sport"           training_sport NOT NULL,
+    "distance"        int            NOT NULL,
+    "priority"        race_priority  NOT NULL DEFAULT 'C',
+    "goal_time"       int,
+    "result_time"     int,
+    "created_at"      timestamptz    NOT NULL DEFAULT now(),
+    "deleted_at"      timestamptz
+);
+
+ALTER TABLE "race"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "race"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "race" ("user_id", "date");
@@ -0,0 +1,5 @@
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS avg_hr;
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS avg_power;
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS distance;
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS duration;
+ALTER TABLE training DROP COLUMN IF EXISTS planned_duration;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- planned duration in seconds, used to project the training load
+ALTER TABLE "training"
+    ADD COLUMN "planned_duration" int;
+
+-- actual data of the session, duration in seconds and distance in meters
+ALTER TABLE "training_feedback"
+    ADD COLUMN "duration"  int,
+    ADD COLUMN "distance"  int,
+    ADD COLUMN "avg_power" int,
+    ADD COLUMN "avg_hr"    int;
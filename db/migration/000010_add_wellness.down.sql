@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS wellness;
+DROP TYPE IF EXISTS cycle_phase;
\ No newline at end of file
@@ -0,0 +1,31 @@
+CREATE TYPE "cycle_phase" AS ENUM (
+    'menstrual',
+    'follicular',
+    'ovulation',
+    'luteal'
+    );
+
+CREATE TABLE "wellness"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint      NOT NULL,
+    "user_id"         bigint      NOT NULL,
+    "date"            date        NOT NULL,
+    "weight"          double precision,
+    "resting_hr"      int,
+    "hrv"             int,
+    "sleep_hours"     double precision,
+    "sleep_quality"   int,
+    "soreness"        int,
+    "stress"          int,
+    "cycle_phase"     cycle_phase,
+    "created_at"      timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "wellness"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "wellness"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "wellness" ("user_id", "date");
@@ -0,0 +1,6 @@
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS injury_id;
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS pain;
+DROP TABLE IF EXISTS injury;
+DROP TYPE IF EXISTS injury_status;
+DROP TYPE IF EXISTS injury_severity;
+DROP TYPE IF EXISTS injury_kind;
\ No newline at end of file
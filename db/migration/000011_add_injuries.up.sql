@@ -0,0 +1,44 @@
+CREATE TYPE "injury_kind" AS ENUM (
+    'injury',
+    'illness'
+    );
+
+CREATE TYPE "injury_severity" AS ENUM (
+    'minor',
+    'moderate',
+    'severe'
+    );
+
+CREATE TYPE "injury_status" AS ENUM (
+    'active',
+    'recovering',
+    'resolved'
+    );
+
+CREATE TABLE "injury"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint          NOT NULL,
+    "user_id"         bigint          NOT NULL,
+    "kind"            injury_kind     NOT NULL,
+    "body_part"       varchar,
+    "diagnosis"       varchar         NOT NULL,
+    "onset"           date            NOT NULL,
+    "severity"        injury_severity NOT NULL,
+    "status"          injury_status   NOT NULL DEFAULT 'active',
+    "expected_return" date,
+    "created_at"      timestamptz     NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "injury"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "injury"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "injury" ("user_id");
+
+-- pain reported with the feedback, from 0 to 10
+ALTER TABLE "training_feedback"
+    ADD COLUMN "pain"      int,
+    ADD COLUMN "injury_id" bigint REFERENCES "injury" ("id") ON DELETE SET NULL;
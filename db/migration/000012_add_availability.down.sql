@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS blackout;
+DROP TABLE IF EXISTS availability;
\ No newline at end of file
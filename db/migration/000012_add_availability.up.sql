@@ -0,0 +1,40 @@
+-- recurring weekly windows an athlete can train in, the weekday is 0 for Sunday
+CREATE TABLE "availability"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint      NOT NULL,
+    "user_id"         bigint      NOT NULL,
+    "weekday"         int         NOT NULL,
+    "start_minute"    int         NOT NULL,
+    "end_minute"      int         NOT NULL,
+    "max_duration"    int,
+    "created_at"      timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "availability"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "availability"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "availability" ("user_id");
+
+-- one-off periods an athlete can't train, like a trip
+CREATE TABLE "blackout"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint      NOT NULL,
+    "user_id"         bigint      NOT NULL,
+    "start_date"      date        NOT NULL,
+    "end_date"        date        NOT NULL,
+    "reason"          varchar,
+    "created_at"      timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "blackout"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "blackout"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "blackout" ("user_id", "start_date");
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS equipment_alert;
+DROP TABLE IF EXISTS training_equipment;
+DROP TABLE IF EXISTS equipment;
+DROP TYPE IF EXISTS equipment_type;
\ No newline at end of file
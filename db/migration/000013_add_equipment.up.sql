@@ -0,0 +1,68 @@
+CREATE TYPE "equipment_type" AS ENUM (
+    'shoes',
+    'bike',
+    'chain',
+    'wetsuit',
+    'other'
+    );
+
+-- the thresholds are the distance in meters and the duration in seconds the equipment should be
+-- retired or serviced at
+CREATE TABLE "equipment"
+(
+    "id"                 bigserial PRIMARY KEY,
+    "organization_id"    bigint         NOT NULL,
+    "user_id"            bigint         NOT NULL,
+    "type"               equipment_type NOT NULL,
+    "brand"              varchar        NOT NULL,
+    "model"              varchar        NOT NULL,
+    "start_date"         date           NOT NULL,
+    "threshold_distance" int,
+    "threshold_duration" int,
+    "retired_on"         date,
+    "created_at"         timestamptz    NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "equipment"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "equipment"
+    ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "equipment" ("user_id");
+
+CREATE TABLE "training_equipment"
+(
+    "training_id"  bigint      NOT NULL,
+    "equipment_id" bigint      NOT NULL,
+    "created_at"   timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY ("training_id", "equipment_id")
+);
+
+ALTER TABLE "training_equipment"
+    ADD FOREIGN KEY ("training_id") REFERENCES "training" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "training_equipment"
+    ADD FOREIGN KEY ("equipment_id") REFERENCES "equipment" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "training_equipment" ("equipment_id");
+
+-- raised once per threshold when the usage of an equipment crosses it
+CREATE TABLE "equipment_alert"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint      NOT NULL,
+    "equipment_id"    bigint      NOT NULL,
+    "metric"          varchar     NOT NULL,
+    "threshold"       int         NOT NULL,
+    "value"           bigint      NOT NULL,
+    "created_at"      timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "equipment_alert"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "equipment_alert"
+    ADD FOREIGN KEY ("equipment_id") REFERENCES "equipment" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "equipment_alert" ("equipment_id", "metric", "threshold");
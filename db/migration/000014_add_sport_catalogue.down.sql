@@ -0,0 +1,17 @@
+ALTER TABLE training DROP COLUMN IF EXISTS attributes;
+
+-- fails when trainings use sports added to the catalogue
+CREATE TYPE "training_sport" AS ENUM (
+    'running',
+    'cycling',
+    'swimming',
+    'weight'
+    );
+
+ALTER TABLE race ALTER COLUMN sport TYPE training_sport USING sport::training_sport;
+ALTER TABLE zone_model ALTER COLUMN sport TYPE training_sport USING sport::training_sport;
+ALTER TABLE group_training ALTER COLUMN sport TYPE training_sport USING sport::training_sport;
+ALTER TABLE training_series ALTER COLUMN sport TYPE training_sport USING sport::training_sport;
+ALTER TABLE training ALTER COLUMN sport TYPE training_sport USING sport::training_sport;
+
+DROP TABLE IF EXISTS sport;
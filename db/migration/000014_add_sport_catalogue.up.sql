@@ -0,0 +1,48 @@
+-- the sports a training can have, the built-in ones have no organization. The metrics are the schema of the
+-- sport-specific attributes of the trainings
+CREATE TABLE "sport"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint,
+    "slug"            varchar     NOT NULL,
+    "name"            varchar     NOT NULL,
+    "metrics"         jsonb       NOT NULL DEFAULT '[]',
+    "created_at"      timestamptz NOT NULL DEFAULT now(),
+    "deleted_at"      timestamptz
+);
+
+ALTER TABLE "sport"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+CREATE UNIQUE INDEX ON "sport" (COALESCE("organization_id", 0), "slug") WHERE "deleted_at" IS NULL;
+
+INSERT INTO "sport" ("slug", "name", "metrics")
+VALUES ('running', 'Running',
+        '[{"key": "terrain", "name": "Terrain", "type": "string", "values": ["road", "trail", "track", "treadmill"]}]'),
+       ('cycling', 'Cycling',
+        '[{"key": "indoor", "name": "Indoor", "type": "boolean"}]'),
+       ('swimming', 'Swimming',
+        '[{"key": "pool_length", "name": "Pool length", "type": "integer", "unit": "m", "min": 10, "max": 100}, {"key": "open_water", "name": "Open water", "type": "boolean"}]'),
+       ('weight', 'Weight training',
+        '[{"key": "sets", "name": "Sets", "type": "integer", "min": 1, "max": 50}, {"key": "reps", "name": "Repetitions", "type": "integer", "min": 1, "max": 100}]');
+
+ALTER TABLE "training"
+    ALTER COLUMN "sport" TYPE varchar USING "sport"::text;
+
+ALTER TABLE "training_series"
+    ALTER COLUMN "sport" TYPE varchar USING "sport"::text;
+
+ALTER TABLE "group_training"
+    ALTER COLUMN "sport" TYPE varchar USING "sport"::text;
+
+ALTER TABLE "zone_model"
+    ALTER COLUMN "sport" TYPE varchar USING "sport"::text;
+
+ALTER TABLE "race"
+    ALTER COLUMN "sport" TYPE varchar USING "sport"::text;
+
+DROP TYPE "training_sport";
+
+-- values of the metrics of the sport
+ALTER TABLE "training"
+    ADD COLUMN "attributes" jsonb;
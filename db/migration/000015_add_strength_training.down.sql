@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS exercise_log;
+DROP TABLE IF EXISTS exercise_prescription;
+DROP TABLE IF EXISTS exercise;
\ No newline at end of file
@@ -0,0 +1,74 @@
+CREATE TABLE "exercise"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint      NOT NULL,
+    "name"            varchar     NOT NULL,
+    "muscle_groups"   varchar[]   NOT NULL DEFAULT '{}',
+    "equipment"       varchar,
+    "video_url"       varchar,
+    "created_at"      timestamptz NOT NULL DEFAULT now(),
+    "deleted_at"      timestamptz
+);
+
+ALTER TABLE "exercise"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+-- the exercises planned for a training, the intensity is either a percentage of the 1RM or the RPE and
+-- the rest is in seconds
+CREATE TABLE "exercise_prescription"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint      NOT NULL,
+    "training_id"     bigint      NOT NULL,
+    "exercise_id"     bigint      NOT NULL,
+    "position"        int         NOT NULL,
+    "sets"            int         NOT NULL,
+    "reps"            int         NOT NULL,
+    "percent_1rm"     double precision,
+    "rpe"             double precision,
+    "rest"            int,
+    "notes"           varchar,
+    "created_at"      timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "exercise_prescription"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "exercise_prescription"
+    ADD FOREIGN KEY ("training_id") REFERENCES "training" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "exercise_prescription"
+    ADD FOREIGN KEY ("exercise_id") REFERENCES "exercise" ("id");
+
+CREATE INDEX ON "exercise_prescription" ("training_id");
+
+-- the sets done by the athlete, the weight is in kg
+CREATE TABLE "exercise_log"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint           NOT NULL,
+    "training_id"     bigint           NOT NULL,
+    "exercise_id"     bigint           NOT NULL,
+    "prescription_id" bigint,
+    "set_number"      int              NOT NULL,
+    "reps"            int              NOT NULL,
+    "weight"          double precision NOT NULL,
+    "rpe"             double precision,
+    "created_at"      timestamptz      NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "exercise_log"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "exercise_log"
+    ADD FOREIGN KEY ("training_id") REFERENCES "training" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "exercise_log"
+    ADD FOREIGN KEY ("exercise_id") REFERENCES "exercise" ("id");
+
+ALTER TABLE "exercise_log"
+    ADD FOREIGN KEY ("prescription_id") REFERENCES "exercise_prescription" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "exercise_log" ("training_id");
+
+CREATE INDEX ON "exercise_log" ("exercise_id");
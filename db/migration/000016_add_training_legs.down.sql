@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS training_leg;
+
+DELETE
+FROM sport
+WHERE organization_id IS NULL
+  AND slug = 'multisport';
\ No newline at end of file
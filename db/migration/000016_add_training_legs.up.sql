@@ -0,0 +1,28 @@
+INSERT INTO "sport" ("slug", "name")
+VALUES ('multisport', 'Multisport');
+
+-- the ordered legs of a multisport training, like the bike and the run of a brick. The transition is the time
+-- planned to change from the previous leg and the duration and distance are the actual values of the feedback
+CREATE TABLE "training_leg"
+(
+    "id"               bigserial PRIMARY KEY,
+    "organization_id"  bigint      NOT NULL,
+    "training_id"      bigint      NOT NULL,
+    "position"         int         NOT NULL,
+    "sport"            varchar     NOT NULL,
+    "details"          varchar,
+    "planned_duration" int,
+    "transition"       int,
+    "attributes"       jsonb,
+    "duration"         int,
+    "distance"         int,
+    "created_at"       timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE "training_leg"
+    ADD FOREIGN KEY ("organization_id") REFERENCES "organization" ("id");
+
+ALTER TABLE "training_leg"
+    ADD FOREIGN KEY ("training_id") REFERENCES "training" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "training_leg" ("training_id", "position");
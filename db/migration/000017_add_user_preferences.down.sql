@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN IF EXISTS units;
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
+
+DROP TYPE IF EXISTS unit_system;
\ No newline at end of file
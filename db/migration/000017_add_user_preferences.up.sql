@@ -0,0 +1,14 @@
+CREATE TYPE "unit_system" AS ENUM (
+    'metric',
+    'imperial'
+    );
+
+-- the timezone is the IANA name of the zone the dates of the athlete are in, like which day is today
+ALTER TABLE "users"
+    ADD COLUMN "timezone" varchar NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE "users"
+    ADD COLUMN "locale" varchar NOT NULL DEFAULT 'en';
+
+ALTER TABLE "users"
+    ADD COLUMN "units" unit_system NOT NULL DEFAULT 'metric';
@@ -0,0 +1,3 @@
+ALTER TABLE training_feedback DROP COLUMN IF EXISTS version;
+ALTER TABLE training DROP COLUMN IF EXISTS version;
+ALTER TABLE users DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- the version is bumped by every update and checked by the ones made from the API, so concurrent
+-- edits don't overwrite each other
+ALTER TABLE "users"
+    ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+
+ALTER TABLE "training"
+    ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+
+ALTER TABLE "training_feedback"
+    ADD COLUMN "version" integer NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+-- the requests are authenticated by the tokens of the users, the organization and actor of a request being the
+-- ones of its token. Only the SHA-256 of a token is kept, the token itself is shown once when issued.
+CREATE TABLE "api_token"
+(
+    "id"              bigserial PRIMARY KEY,
+    "organization_id" bigint      NOT NULL REFERENCES "organization" ("id"),
+    "user_id"         bigint      NOT NULL REFERENCES "users" ("id"),
+    "token_hash"      bytea       NOT NULL,
+    "created_at"      timestamptz NOT NULL DEFAULT now(),
+    "revoked_at"      timestamptz
+);
+
+CREATE UNIQUE INDEX ON "api_token" ("token_hash");
+CREATE INDEX ON "api_token" ("user_id");
@@ -0,0 +1,2 @@
+UPDATE users SET gender = 'M' WHERE gender IS NULL;
+ALTER TABLE users ALTER COLUMN gender SET NOT NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- the gender of an erased user is no longer known
+ALTER TABLE "users"
+    ALTER COLUMN "gender" DROP NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE group_training DROP COLUMN IF EXISTS planned_duration;
\ No newline at end of file
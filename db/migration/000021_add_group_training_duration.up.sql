@@ -0,0 +1,3 @@
+-- the planned duration in seconds, given to the trainings of the members
+ALTER TABLE "group_training"
+    ADD COLUMN "planned_duration" int;
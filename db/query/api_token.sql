@@ -0,0 +1,31 @@
+-- name: CreateApiToken :one
+INSERT INTO api_token (organization_id, user_id, token_hash)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetApiTokenUser :one
+-- the user a token was issued for, as long as the token isn't revoked and the user can still sign in
+SELECT u.*
+FROM api_token a
+         JOIN users u ON u.id = a.user_id
+         JOIN organization o ON o.id = a.organization_id
+WHERE a.token_hash = $1
+  AND a.revoked_at IS NULL
+  AND u.active = true
+  AND u.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+LIMIT 1;
+
+-- name: RevokeUserApiTokens :many
+UPDATE api_token
+SET revoked_at = now()
+WHERE organization_id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL
+RETURNING id;
+
+-- name: DeleteAllApiTokensByUser :exec
+DELETE
+FROM api_token
+WHERE organization_id = $1
+  AND user_id = $2;
@@ -0,0 +1,43 @@
+-- name: CreateAuditLog :one
+INSERT INTO audit_log (organization_id, actor_id, action, entity_type, entity_id, before, after, request_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListAuditLogs :many
+SELECT *
+FROM audit_log
+WHERE organization_id = sqlc.arg(organization_id)
+  AND (sqlc.arg(actor_id)::bigint = 0 OR actor_id = sqlc.arg(actor_id))
+  AND (sqlc.arg(action)::varchar = '' OR action = sqlc.arg(action))
+  AND (sqlc.arg(entity_type)::varchar = '' OR entity_type = sqlc.arg(entity_type))
+  AND (sqlc.arg(entity_id)::bigint = 0 OR entity_id = sqlc.arg(entity_id))
+  AND created_at >= sqlc.arg(start_time)::timestamptz
+  AND created_at < sqlc.arg(end_time)::timestamptz
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: RedactUserAuditLogs :exec
+UPDATE audit_log
+SET before = NULL,
+    after  = NULL
+WHERE organization_id = sqlc.arg(organization_id)
+  AND ((entity_type = 'user' AND entity_id = sqlc.arg(user_id))
+    OR sqlc.arg(user_id)::bigint IN ((before ->> 'user_id')::bigint, (after ->> 'user_id')::bigint,
+                                     (before -> 'training' ->> 'user_id')::bigint,
+                                     (after -> 'training' ->> 'user_id')::bigint)
+    OR (entity_type = 'training' AND entity_id IN (SELECT t.id FROM training t WHERE t.user_id = sqlc.arg(user_id)))
+    OR (entity_type = 'training_feedback' AND entity_id IN (SELECT f.id
+                                                            FROM training_feedback f
+                                                                     JOIN training t ON t.id = f.training_id
+                                                            WHERE t.user_id = sqlc.arg(user_id))));
+
+-- name: ListAllAuditLogsByUser :many
+SELECT *
+FROM audit_log
+WHERE organization_id = sqlc.arg(organization_id)
+  AND (actor_id = sqlc.arg(user_id)
+    OR (entity_type = 'user' AND entity_id = sqlc.arg(user_id))
+    OR sqlc.arg(user_id)::bigint IN ((before ->> 'user_id')::bigint, (after ->> 'user_id')::bigint,
+                                     (before -> 'training' ->> 'user_id')::bigint,
+                                     (after -> 'training' ->> 'user_id')::bigint))
+ORDER BY id;
@@ -0,0 +1,70 @@
+-- name: CreateAvailability :one
+INSERT INTO availability (organization_id, user_id, weekday, start_minute, end_minute, max_duration)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: DeleteAvailability :exec
+DELETE
+FROM availability
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetAvailability :one
+SELECT *
+FROM availability
+WHERE organization_id = $1
+  AND id = $2
+LIMIT 1;
+
+-- name: ListAvailabilityByUser :many
+SELECT *
+FROM availability
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY weekday, start_minute, id;
+
+-- name: DeleteAllAvailabilityByUser :exec
+DELETE
+FROM availability
+WHERE organization_id = $1
+  AND user_id = $2;
+
+-- name: CreateBlackout :one
+INSERT INTO blackout (organization_id, user_id, start_date, end_date, reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: DeleteBlackout :exec
+DELETE
+FROM blackout
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetBlackout :one
+SELECT *
+FROM blackout
+WHERE organization_id = $1
+  AND id = $2
+LIMIT 1;
+
+-- name: ListBlackoutsByUser :many
+SELECT *
+FROM blackout
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY start_date, id;
+
+-- name: ListBlackoutsByUserInPeriod :many
+SELECT *
+FROM blackout
+WHERE organization_id = $1
+  AND user_id = $2
+  AND end_date >= sqlc.arg(start_date)
+  AND start_date <= sqlc.arg(end_date)
+ORDER BY start_date, id;
+
+-- name: DeleteAllBlackoutsByUser :exec
+DELETE
+FROM blackout
+WHERE organization_id = $1
+  AND user_id = $2;
@@ -0,0 +1,101 @@
+-- name: CreateEquipment :one
+INSERT INTO equipment (organization_id, user_id, type, brand, model, start_date, threshold_distance,
+                       threshold_duration, retired_on)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: DeleteEquipment :exec
+DELETE
+FROM equipment
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetEquipment :one
+SELECT *
+FROM equipment
+WHERE organization_id = $1
+  AND id = $2
+LIMIT 1;
+
+-- name: ListEquipmentByUser :many
+SELECT *
+FROM equipment
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY retired_on DESC NULLS FIRST, start_date DESC, id;
+
+-- name: UpdateEquipment :one
+UPDATE equipment
+SET type               = $3,
+    brand              = $4,
+    model              = $5,
+    start_date         = $6,
+    threshold_distance = $7,
+    threshold_duration = $8,
+    retired_on         = $9
+WHERE organization_id = $1
+  AND id = $2
+RETURNING *;
+
+-- name: AddTrainingEquipment :exec
+INSERT INTO training_equipment (training_id, equipment_id)
+VALUES ($1, $2)
+ON CONFLICT (training_id, equipment_id) DO NOTHING;
+
+-- name: RemoveTrainingEquipment :exec
+DELETE
+FROM training_equipment
+WHERE training_id = $1
+  AND equipment_id = $2;
+
+-- name: ListTrainingEquipment :many
+SELECT e.*
+FROM equipment e
+         JOIN training_equipment te ON te.equipment_id = e.id
+WHERE e.organization_id = $1
+  AND te.training_id = $2
+ORDER BY e.id;
+
+-- name: ListEquipmentUsage :many
+-- Only the trainings since the equipment was put in use count. Of a multisport training only the legs of the
+-- sport the equipment is used for count, the whole training for the equipment of no sport in particular.
+SELECT te.equipment_id,
+       count(t.id)                  AS trainings,
+       COALESCE(sum(CASE WHEN t.sport = 'multisport' AND e.type <> 'other' THEN l.distance
+                         ELSE tf.distance END), 0)::bigint AS distance,
+       COALESCE(sum(CASE WHEN t.sport = 'multisport' AND e.type <> 'other' THEN l.duration
+                         ELSE tf.duration END), 0)::bigint AS duration
+FROM training_equipment te
+         JOIN equipment e ON e.id = te.equipment_id
+         JOIN training t ON t.id = te.training_id
+         LEFT JOIN training_feedback tf ON tf.training_id = t.id
+         LEFT JOIN LATERAL (
+    SELECT sum(tl.distance) AS distance, sum(tl.duration) AS duration
+    FROM training_leg tl
+    WHERE tl.training_id = t.id
+      AND tl.sport = CASE e.type
+                         WHEN 'shoes' THEN 'running'
+                         WHEN 'bike' THEN 'cycling'
+                         WHEN 'chain' THEN 'cycling'
+                         WHEN 'wetsuit' THEN 'swimming'
+        END
+    ) l ON true
+WHERE t.organization_id = $1
+  AND te.equipment_id = ANY(sqlc.arg(equipment_ids)::bigint[])
+  AND t.date >= e.start_date
+  AND t.deleted_at IS NULL
+GROUP BY te.equipment_id
+ORDER BY te.equipment_id;
+
+-- name: CreateEquipmentAlert :one
+INSERT INTO equipment_alert (organization_id, equipment_id, metric, threshold, value)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListEquipmentAlertsByUser :many
+SELECT a.*
+FROM equipment_alert a
+         JOIN equipment e ON e.id = a.equipment_id
+WHERE a.organization_id = $1
+  AND e.user_id = $2
+ORDER BY a.created_at DESC, a.id DESC;
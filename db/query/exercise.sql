@@ -0,0 +1,106 @@
+-- name: CreateExercise :one
+INSERT INTO exercise (organization_id, name, muscle_groups, equipment, video_url)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: DeleteExercise :exec
+UPDATE exercise
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetExercise :one
+SELECT *
+FROM exercise
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListExercises :many
+SELECT *
+FROM exercise
+WHERE organization_id = $1
+  AND deleted_at IS NULL
+ORDER BY name, id;
+
+-- name: UpdateExercise :one
+UPDATE exercise
+SET name          = $3,
+    muscle_groups = $4,
+    equipment     = $5,
+    video_url     = $6
+WHERE organization_id = $1
+  AND id = $2
+RETURNING *;
+
+-- name: CreateExercisePrescription :one
+INSERT INTO exercise_prescription (organization_id, training_id, exercise_id, position, sets, reps, percent_1rm, rpe,
+                                   rest, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: DeleteTrainingPrescriptions :exec
+DELETE
+FROM exercise_prescription
+WHERE organization_id = $1
+  AND training_id = $2;
+
+-- name: ListTrainingPrescriptions :many
+SELECT *
+FROM exercise_prescription
+WHERE organization_id = $1
+  AND training_id = $2
+ORDER BY position, id;
+
+-- name: CreateExerciseLog :one
+INSERT INTO exercise_log (organization_id, training_id, exercise_id, prescription_id, set_number, reps, weight, rpe)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: DeleteExerciseLog :exec
+DELETE
+FROM exercise_log
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetExerciseLog :one
+SELECT *
+FROM exercise_log
+WHERE organization_id = $1
+  AND id = $2
+LIMIT 1;
+
+-- name: ListTrainingExerciseLogs :many
+SELECT *
+FROM exercise_log
+WHERE organization_id = $1
+  AND training_id = $2
+ORDER BY exercise_id, set_number, id;
+
+-- name: ListExerciseHistory :many
+SELECT l.exercise_id, l.reps, l.weight, l.rpe, t.date
+FROM exercise_log l
+         JOIN training t ON t.id = l.training_id
+WHERE l.organization_id = $1
+  AND t.user_id = $2
+  AND t.date >= sqlc.arg(start_date)
+  AND t.date <= sqlc.arg(end_date)
+  AND t.deleted_at IS NULL
+ORDER BY t.date, l.id;
+
+-- name: ListAllExerciseLogsByUser :many
+SELECT l.*
+FROM exercise_log l
+         JOIN training t ON t.id = l.training_id
+WHERE l.organization_id = $1
+  AND t.user_id = $2
+ORDER BY l.id;
+
+-- name: ListAllExercisePrescriptionsByUser :many
+SELECT p.*
+FROM exercise_prescription p
+         JOIN training t ON t.id = p.training_id
+WHERE p.organization_id = $1
+  AND t.user_id = $2
+ORDER BY p.id;
@@ -0,0 +1,63 @@
+-- name: CreateGroup :one
+INSERT INTO groups (organization_id, name, coach_id)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: DeleteGroup :exec
+UPDATE groups
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetGroup :one
+SELECT *
+FROM groups
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListGroups :many
+SELECT *
+FROM groups
+WHERE organization_id = $1
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateGroup :one
+UPDATE groups
+SET name     = $3,
+    coach_id = $4
+WHERE organization_id = $1
+  AND id = $2
+RETURNING *;
+
+-- name: AddGroupMember :one
+INSERT INTO group_member (group_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (group_id, user_id) DO UPDATE SET group_id = excluded.group_id
+RETURNING *;
+
+-- name: RemoveGroupMember :exec
+DELETE
+FROM group_member
+WHERE group_id = $1
+  AND user_id = $2;
+
+-- name: ListGroupMembers :many
+SELECT u.*
+FROM users u
+         JOIN group_member gm ON gm.user_id = u.id
+WHERE u.organization_id = $1
+  AND gm.group_id = $2
+  AND u.deleted_at IS NULL
+ORDER BY u.id;
+
+-- name: ListAllGroupMembershipsByUser :many
+SELECT gm.*
+FROM group_member gm
+         JOIN groups g ON g.id = gm.group_id
+WHERE g.organization_id = $1
+  AND gm.user_id = $2
+ORDER BY gm.group_id;
@@ -0,0 +1,38 @@
+-- name: CreateGroupTraining :one
+INSERT INTO group_training (organization_id, group_id, date, sport, type, intensity, details, planned_duration)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: DeleteGroupTraining :exec
+UPDATE group_training
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetGroupTraining :one
+SELECT *
+FROM group_training
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListGroupTrainings :many
+SELECT *
+FROM group_training
+WHERE organization_id = $1
+  AND group_id = $2
+  AND deleted_at IS NULL
+ORDER BY date, id;
+
+-- name: UpdateGroupTraining :one
+UPDATE group_training
+SET date             = $3,
+    sport            = $4,
+    type             = $5,
+    intensity        = $6,
+    details          = $7,
+    planned_duration = $8
+WHERE organization_id = $1
+  AND id = $2
+RETURNING *;
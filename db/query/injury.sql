@@ -0,0 +1,77 @@
+-- name: CreateInjury :one
+INSERT INTO injury (organization_id, user_id, kind, body_part, diagnosis, onset, severity, status, expected_return)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: DeleteInjury :exec
+DELETE
+FROM injury
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetInjury :one
+SELECT *
+FROM injury
+WHERE organization_id = $1
+  AND id = $2
+LIMIT 1;
+
+-- name: ListInjuriesByUser :many
+SELECT *
+FROM injury
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY onset DESC, id DESC;
+
+-- name: ListActiveInjuriesByUser :many
+SELECT *
+FROM injury
+WHERE organization_id = $1
+  AND user_id = $2
+  AND status <> 'resolved'
+ORDER BY onset, id;
+
+-- name: UpdateInjury :one
+UPDATE injury
+SET kind            = $3,
+    body_part       = $4,
+    diagnosis       = $5,
+    onset           = $6,
+    severity        = $7,
+    status          = $8,
+    expected_return = $9
+WHERE organization_id = $1
+  AND id = $2
+RETURNING *;
+
+-- name: ListInjuryTrainings :many
+SELECT t.*
+FROM training t
+         JOIN injury i ON i.user_id = t.user_id
+WHERE i.organization_id = $1
+  AND i.id = $2
+  AND t.status IN ('new', 'notified')
+  AND t.date >= i.onset
+  AND (i.expected_return IS NULL OR t.date < i.expected_return)
+  AND t.deleted_at IS NULL
+ORDER BY t.date, t.id;
+
+-- name: ListInjuryFeedbacks :many
+SELECT *
+FROM training_feedback
+WHERE organization_id = $1
+  AND injury_id = $2
+ORDER BY id;
+
+-- name: DeleteAllInjuriesByUser :exec
+DELETE
+FROM injury
+WHERE organization_id = $1
+  AND user_id = $2;
+
+-- name: ListAllInjuriesByUser :many
+SELECT *
+FROM injury
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY id;
@@ -0,0 +1,31 @@
+-- name: CreateOrganization :one
+INSERT INTO organization (name, slug, settings)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetOrganization :one
+SELECT *
+FROM organization
+WHERE id = $1
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetOrganizationBySlug :one
+SELECT *
+FROM organization
+WHERE slug = $1
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListOrganizations :many
+SELECT *
+FROM organization
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+-- name: UpdateOrganization :one
+UPDATE organization
+SET name     = $2,
+    settings = $3
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,72 @@
+-- name: CreateRace :one
+INSERT INTO race (organization_id, user_id, name, date, sport, distance, priority, goal_time, result_time)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: DeleteRace :exec
+UPDATE race
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetRace :one
+SELECT *
+FROM race
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListRacesByUser :many
+SELECT *
+FROM race
+WHERE organization_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL
+ORDER BY date, id;
+
+-- name: ListRacesByUserFrom :many
+SELECT *
+FROM race
+WHERE organization_id = $1
+  AND user_id = $2
+  AND date >= sqlc.arg(from_date)
+  AND deleted_at IS NULL
+ORDER BY date, id;
+
+-- name: ListRaceResultsByUser :many
+SELECT *
+FROM race
+WHERE organization_id = $1
+  AND user_id = $2
+  AND sport = $3
+  AND result_time IS NOT NULL
+  AND deleted_at IS NULL
+ORDER BY date DESC, id DESC
+LIMIT $4;
+
+-- name: UpdateRace :one
+UPDATE race
+SET name        = $3,
+    date        = $4,
+    sport       = $5,
+    distance    = $6,
+    priority    = $7,
+    goal_time   = $8,
+    result_time = $9
+WHERE organization_id = $1
+  AND id = $2
+RETURNING *;
+
+-- name: ListAllRacesByUser :many
+SELECT *
+FROM race
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY id;
+
+-- name: DeleteAllRacesByUser :exec
+DELETE
+FROM race
+WHERE organization_id = $1
+  AND user_id = $2;
@@ -0,0 +1,42 @@
+-- name: CreateSport :one
+INSERT INTO sport (organization_id, slug, name, metrics)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: DeleteSport :exec
+UPDATE sport
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetSport :one
+SELECT *
+FROM sport
+WHERE (organization_id = sqlc.arg(organization_id)::bigint OR organization_id IS NULL)
+  AND id = sqlc.arg(id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetSportBySlug :one
+SELECT *
+FROM sport
+WHERE (organization_id = sqlc.arg(organization_id)::bigint OR organization_id IS NULL)
+  AND slug = sqlc.arg(slug)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListSports :many
+SELECT *
+FROM sport
+WHERE (organization_id = sqlc.arg(organization_id)::bigint OR organization_id IS NULL)
+  AND deleted_at IS NULL
+ORDER BY organization_id NULLS FIRST, slug;
+
+-- name: UpdateSport :one
+UPDATE sport
+SET name    = $3,
+    metrics = $4
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+RETURNING *;
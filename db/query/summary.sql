@@ -0,0 +1,41 @@
+-- name: ListTrainingSummaries :many
+-- today is the current day in the timezone of each athlete. Every training is a session of its sport, the
+-- multisport ones included, and the volume of a multisport training is counted for the sport of each leg.
+SELECT t.user_id,
+       date_trunc(sqlc.arg(period)::text, t.date::timestamp)::date AS period_start,
+       v.sport::varchar AS sport,
+       count(DISTINCT t.id) FILTER (WHERE v.session) AS planned,
+       count(DISTINCT t.id) FILTER (WHERE v.session
+           AND t.date <= (sqlc.arg(now)::timestamptz AT TIME ZONE u.timezone)::date) AS due,
+       count(DISTINCT t.id) FILTER (WHERE v.session AND t.status IN ('done', 'done_feedback')) AS completed,
+       count(DISTINCT t.id) FILTER (WHERE v.session
+           AND (t.status = 'overdue'
+               OR (t.status IN ('new', 'notified')
+                   AND t.date < (sqlc.arg(now)::timestamptz AT TIME ZONE u.timezone)::date))) AS overdue,
+       COALESCE(sum(v.planned_duration), 0)::bigint AS planned_duration,
+       COALESCE(sum(v.duration), 0)::bigint AS actual_duration,
+       COALESCE(sum(v.distance), 0)::bigint AS actual_distance,
+       COALESCE(sum(tf.borg_scale) FILTER (WHERE v.session), 0)::bigint AS borg_sum,
+       count(tf.id) FILTER (WHERE v.session) AS feedbacks
+FROM training t
+         JOIN users u ON u.id = t.user_id
+         LEFT JOIN training_feedback tf ON tf.training_id = t.id
+         JOIN LATERAL (
+    -- the session, with the volume of the training unless it's split in legs
+    SELECT true AS session, t.sport, t.planned_duration, tf.duration, tf.distance
+    WHERE NOT EXISTS(SELECT 1 FROM training_leg l WHERE l.training_id = t.id)
+    UNION ALL
+    SELECT true, t.sport, NULL, NULL, NULL
+    WHERE EXISTS(SELECT 1 FROM training_leg l WHERE l.training_id = t.id)
+    UNION ALL
+    SELECT false, l.sport, l.planned_duration, l.duration, l.distance
+    FROM training_leg l
+    WHERE l.training_id = t.id
+    ) v ON true
+WHERE t.organization_id = $1
+  AND t.user_id = ANY (sqlc.arg(user_ids)::bigint[])
+  AND t.date >= sqlc.arg(start_date)
+  AND t.date <= sqlc.arg(end_date)
+  AND t.deleted_at IS NULL
+GROUP BY t.user_id, period_start, v.sport
+ORDER BY t.user_id, period_start, sport;
@@ -0,0 +1,40 @@
+-- name: CreateTestResult :one
+INSERT INTO test_result (organization_id, user_id, training_id, protocol, distance, avg_power, avg_hr, time_400m,
+                         time_200m, vo2max, threshold_pace, ftp, css, lthr)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING *;
+
+-- name: DeleteTestResult :exec
+DELETE
+FROM test_result
+WHERE organization_id = $1
+  AND training_id = $2;
+
+-- name: GetTestResult :one
+SELECT *
+FROM test_result
+WHERE organization_id = $1
+  AND training_id = $2
+LIMIT 1;
+
+-- name: ListTestResultsByUser :many
+SELECT tr.*
+FROM test_result tr
+         JOIN training t ON tr.training_id = t.id
+WHERE tr.organization_id = $1
+  AND tr.user_id = $2
+  AND t.deleted_at IS NULL
+ORDER BY t.date, tr.id;
+
+-- name: ListAllTestResultsByUser :many
+SELECT *
+FROM test_result
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY id;
+
+-- name: DeleteAllTestResultsByUser :exec
+DELETE
+FROM test_result
+WHERE organization_id = $1
+  AND user_id = $2;
@@ -1,42 +1,214 @@
 -- name: CreateTraining :one
-INSERT INTO training (user_id, date, sport, type, intensity, details, status)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO training (organization_id, user_id, date, sport, type, intensity, details, status, series_id,
+                      group_training_id, planned_duration, attributes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 RETURNING *;
 
 -- name: DeleteTraining :exec
 UPDATE training
 SET deleted_at = now()
-WHERE id = $1;
+WHERE organization_id = $1
+  AND id = $2;
 
 -- name: GetTraining :one
 SELECT *
 FROM training
-WHERE id = $1
+WHERE organization_id = $1
+  AND id = $2
   AND deleted_at IS NULL
 LIMIT 1;
 
 -- name: ListTrainingsByUserInPeriod :many
 SELECT *
 FROM training
-WHERE user_id = $1
-  AND date between $2 AND $3
+WHERE organization_id = $1
+  AND user_id = $2
+  AND date between $3 AND $4
   AND deleted_at IS NULL
 ORDER BY id;
 
 -- name: ListTrainingsByUser :many
 SELECT *
 FROM training
-WHERE user_id = $1
+WHERE organization_id = $1
+  AND user_id = $2
   AND deleted_at IS NULL
 ORDER BY id;
 
 -- name: UpdateTraining :one
 UPDATE training
-SET date      = $2,
-    sport     = $3,
-    type      = $4,
-    intensity = $5,
-    details   = $6,
-    status    = $7
-WHERE id = $1
+SET date             = $3,
+    sport            = $4,
+    type             = $5,
+    intensity        = $6,
+    details          = $7,
+    status           = $8,
+    planned_duration = $9,
+    attributes       = $10,
+    version          = version + 1
+WHERE organization_id = $1
+  AND id = $2
+  AND version = $11
 RETURNING *;
+
+-- name: TouchTraining :exec
+UPDATE training
+SET version = version + 1
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: MoveTraining :one
+UPDATE training
+SET date    = $3,
+    version = version + 1
+WHERE organization_id = $1
+  AND id = $2
+  AND version = $4
+RETURNING *;
+
+-- name: ListDeletedTrainings :many
+SELECT *
+FROM training
+WHERE organization_id = $1
+  AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetDeletedTraining :one
+SELECT *
+FROM training
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+LIMIT 1;
+
+-- name: RestoreTraining :one
+UPDATE training
+SET deleted_at = NULL
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: RestoreTrainingsByUser :many
+UPDATE training
+SET deleted_at = NULL
+WHERE organization_id = $1
+  AND user_id = $2
+  AND deleted_at >= $3
+RETURNING *;
+
+-- name: PurgeTrainings :many
+DELETE
+FROM training t
+WHERE t.organization_id = $1
+  AND (t.deleted_at < $2
+    OR t.user_id IN (SELECT u.id FROM users u WHERE u.deleted_at < $2))
+RETURNING t.id;
+
+-- name: ListAllTrainingsByUser :many
+SELECT *
+FROM training
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY id;
+
+-- name: ListTrainingsBySeries :many
+SELECT *
+FROM training
+WHERE organization_id = $1
+  AND series_id = $2
+  AND deleted_at IS NULL
+ORDER BY date, id;
+
+-- name: UpdateSeriesTrainings :many
+UPDATE training
+SET date       = date + sqlc.arg(days)::int,
+    -- the attributes only make sense for the sport they were given for
+    attributes = CASE WHEN sport = sqlc.arg(sport) THEN attributes ELSE sqlc.arg(attributes)::jsonb END,
+    sport      = sqlc.arg(sport),
+    type       = sqlc.arg(type),
+    intensity  = sqlc.arg(intensity),
+    details    = sqlc.arg(details),
+    series_id  = sqlc.arg(new_series_id),
+    version    = version + 1
+WHERE organization_id = sqlc.arg(organization_id)
+  AND series_id = sqlc.arg(series_id)
+  AND date >= sqlc.arg(from_date)::date
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteSeriesTrainings :many
+UPDATE training
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND series_id = $2
+  AND date >= $3
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DetachSeriesTrainings :many
+UPDATE training
+SET series_id = NULL,
+    version   = version + 1
+WHERE organization_id = $1
+  AND series_id = $2
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListTrainingsByGroupTraining :many
+SELECT *
+FROM training
+WHERE organization_id = $1
+  AND group_training_id = $2
+  AND deleted_at IS NULL
+ORDER BY user_id;
+
+-- name: UpdatePendingGroupTrainings :many
+UPDATE training
+SET date             = $3,
+    -- the group trainings have no attributes, the ones given to a member only make sense for their sport
+    attributes       = CASE WHEN sport = $4 THEN attributes END,
+    sport            = $4,
+    type             = $5,
+    intensity        = $6,
+    details          = $7,
+    planned_duration = $8,
+    version          = version + 1
+WHERE organization_id = $1
+  AND group_training_id = $2
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeletePendingGroupTrainings :many
+UPDATE training
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND group_training_id = $2
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListTrainingLoadsByUser :many
+SELECT t.id,
+       t.date,
+       t.sport,
+       t.intensity,
+       t.status,
+       t.planned_duration,
+       COALESCE(tf.borg_scale, 0)::int AS borg_scale,
+       COALESCE(tf.duration, 0)::int   AS duration,
+       COALESCE(tf.distance, 0)::int   AS distance,
+       COALESCE(tf.avg_power, 0)::int  AS avg_power,
+       COALESCE(tf.avg_hr, 0)::int     AS avg_hr
+FROM training t
+         LEFT JOIN training_feedback tf ON tf.training_id = t.id
+WHERE t.organization_id = $1
+  AND t.user_id = $2
+  AND t.date >= sqlc.arg(start_date)
+  AND t.date <= sqlc.arg(end_date)
+  AND t.deleted_at IS NULL
+ORDER BY t.date, t.id;
@@ -1,24 +1,28 @@
 -- name: CreateTrainingFeedback :one
-INSERT INTO training_feedback (training_id, borg_scale)
-VALUES ($1, $2)
+INSERT INTO training_feedback (organization_id, training_id, borg_scale, duration, distance, avg_power, avg_hr, pain,
+                               injury_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: DeleteTrainingFeedback :exec
 DELETE
 FROM training_feedback
-WHERE training_id = $1;
+WHERE organization_id = $1
+  AND training_id = $2;
 
 -- name: GetTrainingFeedback :one
 SELECT *
 FROM training_feedback
-WHERE training_id = $1
+WHERE organization_id = $1
+  AND training_id = $2
 LIMIT 1;
 
 -- name: ListTrainingFeedbacksByUser :many
 SELECT tf.*
 FROM training_feedback tf
          JOIN training t ON tf.training_id = t.id
-WHERE t.user_id = $1
+WHERE tf.organization_id = $1
+  AND t.user_id = $2
   AND t.deleted_at IS NULL
 ORDER BY tf.id;
 
@@ -26,13 +30,50 @@ ORDER BY tf.id;
 SELECT tf.*
 FROM training_feedback tf
          JOIN training t ON tf.training_id = t.id
-WHERE t.user_id = $1
-  AND date between $2 AND $3
+WHERE tf.organization_id = $1
+  AND t.user_id = $2
+  AND date between $3 AND $4
   AND t.deleted_at IS NULL
 ORDER BY tf.id;
 
 -- name: UpdateTrainingFeedback :one
 UPDATE training_feedback
-SET borg_scale = $2
-WHERE training_id = $1
+SET borg_scale = $3,
+    duration   = $4,
+    distance   = $5,
+    avg_power  = $6,
+    avg_hr     = $7,
+    pain       = $8,
+    injury_id  = $9,
+    version    = version + 1
+WHERE organization_id = $1
+  AND training_id = $2
+  AND version = $10
 RETURNING *;
+
+-- name: PurgeTrainingFeedbacks :many
+DELETE
+FROM training_feedback tf
+    USING training t
+         LEFT JOIN users u ON t.user_id = u.id
+WHERE tf.training_id = t.id
+  AND tf.organization_id = $1
+  AND (t.deleted_at < $2 OR u.deleted_at < $2)
+RETURNING tf.id;
+
+-- name: ListAllTrainingFeedbacksByUser :many
+SELECT tf.*
+FROM training_feedback tf
+         JOIN training t ON tf.training_id = t.id
+WHERE tf.organization_id = $1
+  AND t.user_id = $2
+ORDER BY tf.id;
+
+-- name: EraseUserTrainingFeedbacksPain :exec
+UPDATE training_feedback tf
+SET pain      = NULL,
+    injury_id = NULL
+FROM training t
+WHERE t.id = tf.training_id
+  AND tf.organization_id = $1
+  AND t.user_id = $2;
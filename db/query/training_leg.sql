@@ -0,0 +1,49 @@
+-- name: CreateTrainingLeg :one
+INSERT INTO training_leg (organization_id, training_id, position, sport, details, planned_duration, transition,
+                          attributes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: DeleteTrainingLegs :exec
+DELETE
+FROM training_leg
+WHERE organization_id = $1
+  AND training_id = $2;
+
+-- name: ListTrainingLegs :many
+SELECT *
+FROM training_leg
+WHERE organization_id = $1
+  AND training_id = $2
+ORDER BY position;
+
+-- name: ListTrainingLegsByTrainings :many
+SELECT *
+FROM training_leg
+WHERE organization_id = $1
+  AND training_id = ANY (sqlc.arg(training_ids)::bigint[])
+ORDER BY training_id, position;
+
+-- name: UpdateTrainingLegFeedback :one
+UPDATE training_leg
+SET duration = $4,
+    distance = $5
+WHERE organization_id = $1
+  AND training_id = $2
+  AND position = $3
+RETURNING *;
+
+-- name: ClearTrainingLegsFeedback :exec
+UPDATE training_leg
+SET duration = NULL,
+    distance = NULL
+WHERE organization_id = $1
+  AND training_id = $2;
+
+-- name: ListAllTrainingLegsByUser :many
+SELECT l.*
+FROM training_leg l
+         JOIN training t ON t.id = l.training_id
+WHERE l.organization_id = $1
+  AND t.user_id = $2
+ORDER BY l.training_id, l.position;
@@ -0,0 +1,46 @@
+-- name: CreateTrainingSeries :one
+INSERT INTO training_series (organization_id, user_id, rrule, dtstart, exdate, sport, type, intensity, details)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: DeleteTrainingSeries :exec
+UPDATE training_series
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetTrainingSeries :one
+SELECT *
+FROM training_series
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListAllTrainingSeriesByUser :many
+SELECT *
+FROM training_series
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY id;
+
+-- name: UpdateTrainingSeries :one
+UPDATE training_series
+SET rrule     = $3,
+    dtstart   = $4,
+    exdate    = $5,
+    sport     = $6,
+    type      = $7,
+    intensity = $8,
+    details   = $9
+WHERE organization_id = $1
+  AND id = $2
+RETURNING *;
+
+-- name: PurgeTrainingSeries :many
+DELETE
+FROM training_series s
+WHERE s.organization_id = $1
+  AND (s.deleted_at < $2 OR s.user_id IN (SELECT u.id FROM users u WHERE u.deleted_at < $2))
+  AND NOT EXISTS(SELECT 1 FROM training t WHERE t.series_id = s.id)
+RETURNING s.id;
@@ -1,49 +1,132 @@
 -- name: CreateUser :one
-INSERT INTO users (type, name, gender, email, phone, birth, active)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO users (organization_id, type, name, gender, email, phone, birth, active, timezone, locale, units)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
 -- name: DeleteUser :exec
 UPDATE users
 SET deleted_at = now()
-WHERE id = $1;
+WHERE organization_id = $1
+  AND id = $2;
 
 -- name: GetUser :one
 SELECT *
 FROM users
-WHERE id = $1
+WHERE organization_id = $1
+  AND id = $2
   AND deleted_at IS NULL
 LIMIT 1;
 
 -- name: ListActiveUsers :many
 SELECT *
 FROM users
-WHERE deleted_at IS NULL
+WHERE organization_id = $1
+  AND deleted_at IS NULL
   AND active = TRUE
 ORDER BY id
-LIMIT $1 OFFSET $2;
+LIMIT $2 OFFSET $3;
 
 -- name: ListUsers :many
 SELECT *
 FROM users
-WHERE deleted_at IS NULL
+WHERE organization_id = $1
+  AND deleted_at IS NULL
 ORDER BY id
-LIMIT $1 OFFSET $2;
+LIMIT $2 OFFSET $3;
 
 -- name: ListAllUsers :many
 SELECT *
 FROM users
+WHERE organization_id = $1
 ORDER BY id
-LIMIT $1 OFFSET $2;
+LIMIT $2 OFFSET $3;
 
 -- name: UpdateUser :one
 UPDATE users
-SET type   = $2,
-    name   = $3,
-    gender = $4,
-    email  = $5,
-    phone  = $6,
-    birth  = $7,
-    active = $8
-WHERE id = $1
+SET type     = $3,
+    name     = $4,
+    gender   = $5,
+    email    = $6,
+    phone    = $7,
+    birth    = $8,
+    active   = $9,
+    timezone = $10,
+    locale   = $11,
+    units    = $12,
+    version  = version + 1
+WHERE organization_id = $1
+  AND id = $2
+  AND version = $13
+RETURNING *;
+
+-- name: ListDeletedUsers :many
+SELECT *
+FROM users
+WHERE organization_id = $1
+  AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetDeletedUser :one
+SELECT *
+FROM users
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+LIMIT 1;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeUsers :many
+DELETE
+FROM users
+WHERE organization_id = $1
+  AND deleted_at < $2
+RETURNING id;
+
+-- name: GetUserIncludingDeleted :one
+SELECT *
+FROM users
+WHERE organization_id = $1
+  AND id = $2
+LIMIT 1;
+
+-- name: EraseUser :one
+UPDATE users
+SET name     = 'Erased user',
+    gender   = NULL,
+    email    = 'erased-' || id || '@erased.invalid',
+    phone    = NULL,
+    birth    = NULL,
+    active   = FALSE,
+    timezone = DEFAULT,
+    locale   = DEFAULT,
+    units    = DEFAULT,
+    version  = version + 1
+WHERE organization_id = $1
+  AND id = $2
 RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT *
+FROM users
+WHERE organization_id = $1
+  AND email = $2
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT 1;
+
+-- name: ListActiveAthletes :many
+SELECT *
+FROM users
+WHERE organization_id = $1
+  AND type = 'athlete'
+  AND active = TRUE
+  AND deleted_at IS NULL
+ORDER BY id;
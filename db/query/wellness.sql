@@ -0,0 +1,57 @@
+-- name: CreateWellness :one
+INSERT INTO wellness (organization_id, user_id, date, weight, resting_hr, hrv, sleep_hours, sleep_quality, soreness,
+                      stress, cycle_phase)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: DeleteWellness :exec
+DELETE
+FROM wellness
+WHERE organization_id = $1
+  AND user_id = $2
+  AND date = $3;
+
+-- name: GetWellness :one
+SELECT *
+FROM wellness
+WHERE organization_id = $1
+  AND user_id = $2
+  AND date = $3
+LIMIT 1;
+
+-- name: ListWellnessByUserInPeriod :many
+SELECT *
+FROM wellness
+WHERE organization_id = $1
+  AND user_id = $2
+  AND date >= sqlc.arg(start_date)
+  AND date <= sqlc.arg(end_date)
+ORDER BY date;
+
+-- name: UpdateWellness :one
+UPDATE wellness
+SET weight        = $4,
+    resting_hr    = $5,
+    hrv           = $6,
+    sleep_hours   = $7,
+    sleep_quality = $8,
+    soreness      = $9,
+    stress        = $10,
+    cycle_phase   = $11
+WHERE organization_id = $1
+  AND user_id = $2
+  AND date = $3
+RETURNING *;
+
+-- name: ListAllWellnessByUser :many
+SELECT *
+FROM wellness
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY date;
+
+-- name: DeleteAllWellnessByUser :exec
+DELETE
+FROM wellness
+WHERE organization_id = $1
+  AND user_id = $2;
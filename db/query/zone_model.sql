@@ -0,0 +1,49 @@
+-- name: CreateZoneModel :one
+INSERT INTO zone_model (organization_id, user_id, sport, method, threshold, resting, effective_from)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: DeleteZoneModel :exec
+UPDATE zone_model
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND id = $2;
+
+-- name: GetZoneModel :one
+SELECT *
+FROM zone_model
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListZoneModelsByUser :many
+SELECT *
+FROM zone_model
+WHERE organization_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL
+ORDER BY sport, method, effective_from DESC, id DESC;
+
+-- name: ListEffectiveZoneModels :many
+SELECT *
+FROM zone_model
+WHERE organization_id = $1
+  AND user_id = $2
+  AND sport = $3
+  AND effective_from <= sqlc.arg(on_date)
+  AND deleted_at IS NULL
+ORDER BY method, effective_from DESC, id DESC;
+
+-- name: ListAllZoneModelsByUser :many
+SELECT *
+FROM zone_model
+WHERE organization_id = $1
+  AND user_id = $2
+ORDER BY id;
+
+-- name: DeleteAllZoneModelsByUser :exec
+DELETE
+FROM zone_model
+WHERE organization_id = $1
+  AND user_id = $2;
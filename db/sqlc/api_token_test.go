@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+)
+
+func (s *DbTestSuite) createApiToken(userID int64, token string) ApiToken {
+	hash := sha256.Sum256([]byte(token))
+	arg := CreateApiTokenParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		TokenHash:      hash[:],
+	}
+
+	apiToken, err := s.q.CreateApiToken(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.UserID, apiToken.UserID)
+	s.Equal(arg.TokenHash, apiToken.TokenHash)
+	s.False(apiToken.RevokedAt.Valid)
+
+	return apiToken
+}
+
+func (s *DbTestSuite) TestGetApiTokenUser() {
+	u := s.createUser(UserTypeAthlete, true)
+	apiToken := s.createApiToken(u.ID, s.f.UUID().V4())
+
+	user, err := s.q.GetApiTokenUser(context.Background(), apiToken.TokenHash)
+	s.Require().NoError(err)
+	s.Equal(u.ID, user.ID)
+	s.Equal(s.org.ID, user.OrganizationID)
+
+	// an inactive user can't sign in
+	inactive := s.createUser(UserTypeAthlete, false)
+	apiToken = s.createApiToken(inactive.ID, s.f.UUID().V4())
+	_, err = s.q.GetApiTokenUser(context.Background(), apiToken.TokenHash)
+	s.Require().Equal(sql.ErrNoRows, err)
+
+	// nor a deleted one
+	deleted := s.createUser(UserTypeAthlete, true)
+	apiToken = s.createApiToken(deleted.ID, s.f.UUID().V4())
+	err = s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: deleted.ID})
+	s.Require().NoError(err)
+	_, err = s.q.GetApiTokenUser(context.Background(), apiToken.TokenHash)
+	s.Require().Equal(sql.ErrNoRows, err)
+}
+
+func (s *DbTestSuite) TestRevokeUserApiTokens() {
+	u := s.createUser(UserTypeAthlete, true)
+	first := s.createApiToken(u.ID, s.f.UUID().V4())
+	second := s.createApiToken(u.ID, s.f.UUID().V4())
+	other := s.createApiToken(s.createUser(UserTypeAthlete, true).ID, s.f.UUID().V4())
+
+	ids, err := s.q.RevokeUserApiTokens(context.Background(), RevokeUserApiTokensParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.ElementsMatch([]int64{first.ID, second.ID}, ids)
+
+	_, err = s.q.GetApiTokenUser(context.Background(), first.TokenHash)
+	s.Require().Equal(sql.ErrNoRows, err)
+	_, err = s.q.GetApiTokenUser(context.Background(), other.TokenHash)
+	s.Require().NoError(err)
+
+	// the revoked tokens aren't revoked again
+	ids, err = s.q.RevokeUserApiTokens(context.Background(), RevokeUserApiTokensParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(ids)
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createAuditLog(actorID int64, action string, entityType string, entityID int64) AuditLog {
+	arg := CreateAuditLogParams{
+		OrganizationID: s.org.ID,
+		ActorID:        null.NewInt64(actorID, actorID != 0),
+		Action:         action,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		Before:         json.RawMessage(`null`),
+		After:          json.RawMessage(`{"id": 1}`),
+		RequestID:      s.f.UUID().V4(),
+	}
+
+	log, err := s.q.CreateAuditLog(context.Background(), arg)
+	s.Require().NoError(err)
+	s.NotEmpty(log)
+
+	s.Equal(arg.ActorID, log.ActorID)
+	s.Equal(arg.Action, log.Action)
+	s.Equal(arg.EntityType, log.EntityType)
+	s.Equal(arg.EntityID, log.EntityID)
+	s.JSONEq(string(arg.After), string(log.After))
+	s.Equal(arg.RequestID, log.RequestID)
+	s.NotEmpty(log.CreatedAt)
+
+	return log
+}
+
+func (s *DbTestSuite) TestCreateAuditLog() {
+	u := s.createUser(UserTypeAdmin, true)
+	s.createAuditLog(u.ID, "create", "user", u.ID)
+}
+
+func (s *DbTestSuite) TestListAuditLogs() {
+	u := s.createUser(UserTypeAdmin, true)
+	t := s.createTraining(u.ID)
+
+	s.createAuditLog(u.ID, "create", "training", t.ID)
+	s.createAuditLog(u.ID, "update", "training", t.ID)
+	s.createAuditLog(0, "delete", "training", t.ID)
+
+	arg := ListAuditLogsParams{
+		OrganizationID: s.org.ID,
+		EntityType:     "training",
+		EntityID:       t.ID,
+		StartTime:      time.Now().UTC().AddDate(0, 0, -1),
+		EndTime:        time.Now().UTC().AddDate(0, 0, 1),
+		RowLimit:       math.MaxInt32,
+	}
+
+	logs, err := s.q.ListAuditLogs(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Len(logs, 3)
+
+	arg.ActorID = u.ID
+	logs, err = s.q.ListAuditLogs(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Len(logs, 2)
+
+	arg.Action = "update"
+	logs, err = s.q.ListAuditLogs(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Len(logs, 1)
+	s.Equal("update", logs[0].Action)
+}
+
+func (s *DbTestSuite) TestListAllAuditLogsByUser() {
+	u := s.createUser(UserTypeAthlete, true)
+	coach := s.createUser(UserTypeCoach, true)
+
+	made := s.createAuditLog(u.ID, "update", "wellness", 1)
+	about := s.createAuditLog(coach.ID, "update", "user", u.ID)
+	s.createAuditLog(coach.ID, "update", "user", coach.ID)
+
+	logs, err := s.q.ListAllAuditLogsByUser(context.Background(), ListAllAuditLogsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Require().Len(logs, 2)
+	s.Equal(made.ID, logs[0].ID)
+	s.Equal(about.ID, logs[1].ID)
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createAvailability(userID int64, weekday time.Weekday) Availability {
+	arg := CreateAvailabilityParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		Weekday:        int32(weekday),
+		StartMinute:    360,
+		EndMinute:      450,
+		MaxDuration:    null.NewInt32(3600, true),
+	}
+
+	window, err := s.q.CreateAvailability(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Weekday, window.Weekday)
+	s.Equal(arg.StartMinute, window.StartMinute)
+	s.Equal(arg.EndMinute, window.EndMinute)
+	s.Equal(arg.MaxDuration, window.MaxDuration)
+
+	return window
+}
+
+func (s *DbTestSuite) createBlackout(userID int64, start, end time.Time) Blackout {
+	arg := CreateBlackoutParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		StartDate:      start,
+		EndDate:        end,
+		Reason:         null.NewString("trip", true),
+	}
+
+	blackout, err := s.q.CreateBlackout(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(start.Format("2006-01-02"), blackout.StartDate.Format("2006-01-02"))
+	s.Equal(end.Format("2006-01-02"), blackout.EndDate.Format("2006-01-02"))
+	s.Equal(arg.Reason, blackout.Reason)
+
+	return blackout
+}
+
+func (s *DbTestSuite) TestListAvailabilityByUser() {
+	u1 := s.createUser(UserTypeAthlete, true)
+	u2 := s.createUser(UserTypeAthlete, true)
+
+	saturday := s.createAvailability(u1.ID, time.Saturday)
+	monday := s.createAvailability(u1.ID, time.Monday)
+	s.createAvailability(u2.ID, time.Monday)
+
+	windows, err := s.q.ListAvailabilityByUser(context.Background(), ListAvailabilityByUserParams{
+		OrganizationID: s.org.ID,
+		UserID:         u1.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(windows, 2)
+	s.Equal(monday.ID, windows[0].ID)
+	s.Equal(saturday.ID, windows[1].ID)
+}
+
+func (s *DbTestSuite) TestDeleteAvailability() {
+	u := s.createUser(UserTypeAthlete, true)
+	window := s.createAvailability(u.ID, time.Monday)
+
+	err := s.q.DeleteAvailability(context.Background(), DeleteAvailabilityParams{OrganizationID: s.org.ID, ID: window.ID})
+	s.Require().NoError(err)
+
+	_, err = s.q.GetAvailability(context.Background(), GetAvailabilityParams{OrganizationID: s.org.ID, ID: window.ID})
+	s.Require().Error(err)
+}
+
+func (s *DbTestSuite) TestListBlackoutsByUserInPeriod() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+
+	s.createBlackout(u.ID, date.AddDate(0, 0, -10), date.AddDate(0, 0, -1))
+	overlapping := s.createBlackout(u.ID, date.AddDate(0, 0, -3), date)
+	inside := s.createBlackout(u.ID, date.AddDate(0, 0, 5), date.AddDate(0, 0, 6))
+	s.createBlackout(u.ID, date.AddDate(0, 0, 8), date.AddDate(0, 0, 9))
+
+	blackouts, err := s.q.ListBlackoutsByUserInPeriod(context.Background(), ListBlackoutsByUserInPeriodParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		StartDate:      date,
+		EndDate:        date.AddDate(0, 0, 7),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(blackouts, 2)
+	s.Equal(overlapping.ID, blackouts[0].ID)
+	s.Equal(inside.ID, blackouts[1].ID)
+}
+
+func (s *DbTestSuite) TestDeleteBlackout() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+	blackout := s.createBlackout(u.ID, date, date)
+
+	err := s.q.DeleteBlackout(context.Background(), DeleteBlackoutParams{OrganizationID: s.org.ID, ID: blackout.ID})
+	s.Require().NoError(err)
+
+	_, err = s.q.GetBlackout(context.Background(), GetBlackoutParams{OrganizationID: s.org.ID, ID: blackout.ID})
+	s.Require().Error(err)
+}
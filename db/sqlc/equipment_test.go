@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createEquipment(userID int64, equipmentType EquipmentType) Equipment {
+	arg := CreateEquipmentParams{
+		OrganizationID:    s.org.ID,
+		UserID:            userID,
+		Type:              equipmentType,
+		Brand:             "Brand",
+		Model:             "Model",
+		StartDate:         time.Now().UTC(),
+		ThresholdDistance: null.NewInt32(700000, true),
+	}
+
+	equipment, err := s.q.CreateEquipment(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Type, equipment.Type)
+	s.Equal(arg.Brand, equipment.Brand)
+	s.Equal(arg.ThresholdDistance, equipment.ThresholdDistance)
+	s.False(equipment.ThresholdDuration.Valid)
+	s.False(equipment.RetiredOn.Valid)
+
+	return equipment
+}
+
+func (s *DbTestSuite) TestUpdateEquipment() {
+	u := s.createUser(UserTypeAthlete, true)
+	e := s.createEquipment(u.ID, EquipmentTypeShoes)
+	retiredOn := e.StartDate.AddDate(0, 6, 0)
+
+	updated, err := s.q.UpdateEquipment(context.Background(), UpdateEquipmentParams{
+		OrganizationID:    s.org.ID,
+		ID:                e.ID,
+		Type:              e.Type,
+		Brand:             e.Brand,
+		Model:             "Other model",
+		StartDate:         e.StartDate,
+		ThresholdDistance: e.ThresholdDistance,
+		RetiredOn:         null.NewTime(retiredOn, true),
+	})
+	s.Require().NoError(err)
+	s.Equal("Other model", updated.Model)
+	s.Equal(retiredOn.Format("2006-01-02"), updated.RetiredOn.Time.Format("2006-01-02"))
+}
+
+func (s *DbTestSuite) TestListEquipmentByUser() {
+	u := s.createUser(UserTypeAthlete, true)
+	retired := s.createEquipment(u.ID, EquipmentTypeShoes)
+	_, err := s.q.UpdateEquipment(context.Background(), UpdateEquipmentParams{
+		OrganizationID: s.org.ID,
+		ID:             retired.ID,
+		Type:           retired.Type,
+		Brand:          retired.Brand,
+		Model:          retired.Model,
+		StartDate:      retired.StartDate,
+		RetiredOn:      null.NewTime(retired.StartDate, true),
+	})
+	s.Require().NoError(err)
+	bike := s.createEquipment(u.ID, EquipmentTypeBike)
+
+	equipment, err := s.q.ListEquipmentByUser(context.Background(), ListEquipmentByUserParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(equipment, 2)
+	// the equipment in use comes first
+	s.Equal(bike.ID, equipment[0].ID)
+	s.Equal(retired.ID, equipment[1].ID)
+}
+
+func (s *DbTestSuite) TestListEquipmentUsage() {
+	u := s.createUser(UserTypeAthlete, true)
+	shoes := s.createEquipment(u.ID, EquipmentTypeShoes)
+	unused := s.createEquipment(u.ID, EquipmentTypeShoes)
+	date := time.Now().UTC()
+
+	withFeedback := s.createDatedTraining(u.ID, date, TrainingStatusDoneFeedback)
+	_, err := s.q.CreateTrainingFeedback(context.Background(), CreateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     withFeedback.ID,
+		BorgScale:      13,
+		Duration:       null.NewInt32(3600, true),
+		Distance:       null.NewInt32(12000, true),
+	})
+	s.Require().NoError(err)
+	withoutFeedback := s.createDatedTraining(u.ID, date, TrainingStatusDone)
+
+	for _, t := range []Training{withFeedback, withoutFeedback, withFeedback} {
+		err = s.q.AddTrainingEquipment(context.Background(), AddTrainingEquipmentParams{TrainingID: t.ID, EquipmentID: shoes.ID})
+		s.Require().NoError(err)
+	}
+
+	equipment, err := s.q.ListTrainingEquipment(context.Background(), ListTrainingEquipmentParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     withFeedback.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(equipment, 1)
+	s.Equal(shoes.ID, equipment[0].ID)
+
+	usage, err := s.q.ListEquipmentUsage(context.Background(), ListEquipmentUsageParams{
+		OrganizationID: s.org.ID,
+		EquipmentIds:   []int64{shoes.ID, unused.ID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(usage, 1)
+	s.Equal(shoes.ID, usage[0].EquipmentID)
+	s.Equal(int64(2), usage[0].Trainings)
+	s.Equal(int64(12000), usage[0].Distance)
+	s.Equal(int64(3600), usage[0].Duration)
+
+	err = s.q.RemoveTrainingEquipment(context.Background(), RemoveTrainingEquipmentParams{TrainingID: withFeedback.ID, EquipmentID: shoes.ID})
+	s.Require().NoError(err)
+
+	usage, err = s.q.ListEquipmentUsage(context.Background(), ListEquipmentUsageParams{
+		OrganizationID: s.org.ID,
+		EquipmentIds:   []int64{shoes.ID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(usage, 1)
+	s.Zero(usage[0].Distance)
+}
+
+func (s *DbTestSuite) TestListEquipmentUsageWithLegs() {
+	u := s.createUser(UserTypeAthlete, true)
+	shoes := s.createEquipment(u.ID, EquipmentTypeShoes)
+	other := s.createEquipment(u.ID, EquipmentTypeOther)
+	date, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+
+	brick := s.createBrick(u.ID, date)
+	_, err := s.q.CreateTrainingFeedback(context.Background(), CreateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     brick.Training.ID,
+		BorgScale:      15,
+		Duration:       null.NewInt32(4800, true),
+		Distance:       null.NewInt32(40000, true),
+	})
+	s.Require().NoError(err)
+	_, err = s.store.UpdateTrainingLegsFeedbackTx(context.Background(), UpdateTrainingLegsFeedbackTxParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     brick.Training.ID,
+		Legs: []UpdateTrainingLegFeedbackParams{
+			{Position: 1, Duration: null.NewInt32(3600, true), Distance: null.NewInt32(32000, true)},
+			{Position: 2, Duration: null.NewInt32(1200, true), Distance: null.NewInt32(8000, true)},
+		},
+	})
+	s.Require().NoError(err)
+
+	// the trainings before the equipment was put in use don't count
+	before := s.createDatedTraining(u.ID, date.AddDate(0, 0, -1), TrainingStatusDone)
+
+	for _, t := range []Training{brick.Training, before} {
+		for _, e := range []Equipment{shoes, other} {
+			err = s.q.AddTrainingEquipment(context.Background(), AddTrainingEquipmentParams{TrainingID: t.ID, EquipmentID: e.ID})
+			s.Require().NoError(err)
+		}
+	}
+
+	usage, err := s.q.ListEquipmentUsage(context.Background(), ListEquipmentUsageParams{
+		OrganizationID: s.org.ID,
+		EquipmentIds:   []int64{shoes.ID, other.ID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(usage, 2)
+
+	// the shoes count the running leg only
+	s.Equal(shoes.ID, usage[0].EquipmentID)
+	s.Equal(int64(1), usage[0].Trainings)
+	s.Equal(int64(8000), usage[0].Distance)
+	s.Equal(int64(1200), usage[0].Duration)
+
+	s.Equal(other.ID, usage[1].EquipmentID)
+	s.Equal(int64(1), usage[1].Trainings)
+	s.Equal(int64(40000), usage[1].Distance)
+	s.Equal(int64(4800), usage[1].Duration)
+}
+
+func (s *DbTestSuite) TestEquipmentAlerts() {
+	u := s.createUser(UserTypeAthlete, true)
+	shoes := s.createEquipment(u.ID, EquipmentTypeShoes)
+
+	arg := CreateEquipmentAlertParams{
+		OrganizationID: s.org.ID,
+		EquipmentID:    shoes.ID,
+		Metric:         "distance",
+		Threshold:      shoes.ThresholdDistance.Int32,
+		Value:          701000,
+	}
+	alert, err := s.q.CreateEquipmentAlert(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Value, alert.Value)
+
+	// an alert is raised once per threshold
+	_, err = s.q.CreateEquipmentAlert(context.Background(), arg)
+	s.Require().Error(err)
+
+	alerts, err := s.q.ListEquipmentAlertsByUser(context.Background(), ListEquipmentAlertsByUserParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(alerts, 1)
+	s.Equal(alert.ID, alerts[0].ID)
+}
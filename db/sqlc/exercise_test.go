@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createExercise(name string) Exercise {
+	arg := CreateExerciseParams{
+		OrganizationID: s.org.ID,
+		Name:           name,
+		MuscleGroups:   []string{"quadriceps", "glutes"},
+		Equipment:      null.NewString("barbell", true),
+	}
+
+	exercise, err := s.q.CreateExercise(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Name, exercise.Name)
+	s.Equal(arg.MuscleGroups, exercise.MuscleGroups)
+	s.Equal(arg.Equipment, exercise.Equipment)
+	s.False(exercise.VideoUrl.Valid)
+
+	return exercise
+}
+
+func (s *DbTestSuite) createExerciseLog(training Training, exerciseID int64, reps int32, weight float64) ExerciseLog {
+	log, err := s.q.CreateExerciseLog(context.Background(), CreateExerciseLogParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     training.ID,
+		ExerciseID:     exerciseID,
+		SetNumber:      1,
+		Reps:           reps,
+		Weight:         weight,
+	})
+	s.Require().NoError(err)
+	s.False(log.PrescriptionID.Valid)
+
+	return log
+}
+
+func (s *DbTestSuite) TestListExercises() {
+	squat := s.createExercise("Squat")
+	deadlift := s.createExercise("Deadlift")
+	deleted := s.createExercise("Bench press")
+
+	err := s.q.DeleteExercise(context.Background(), DeleteExerciseParams{OrganizationID: s.org.ID, ID: deleted.ID})
+	s.Require().NoError(err)
+
+	exercises, err := s.q.ListExercises(context.Background(), s.org.ID)
+	s.Require().NoError(err)
+	position := map[int64]int{}
+	for i, e := range exercises {
+		position[e.ID] = i
+	}
+	s.NotContains(position, deleted.ID)
+	s.Contains(position, squat.ID)
+	s.Contains(position, deadlift.ID)
+	// sorted by name
+	s.Less(position[deadlift.ID], position[squat.ID])
+
+	_, err = s.q.GetExercise(context.Background(), GetExerciseParams{OrganizationID: s.org.ID, ID: deleted.ID})
+	s.Error(err)
+}
+
+func (s *DbTestSuite) TestSetTrainingExercisesTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	t := s.createTraining(u.ID)
+	squat := s.createExercise("Squat")
+	deadlift := s.createExercise("Deadlift")
+
+	prescriptions, err := s.store.SetTrainingExercisesTx(context.Background(), SetTrainingExercisesTxParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     t.ID,
+		Prescriptions: []CreateExercisePrescriptionParams{
+			{OrganizationID: s.org.ID, TrainingID: t.ID, ExerciseID: squat.ID, Position: 1, Sets: 5, Reps: 5,
+				Percent1rm: null.NewFloat64(80, true), Rest: null.NewInt32(180, true)},
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(prescriptions, 1)
+
+	log, err := s.q.CreateExerciseLog(context.Background(), CreateExerciseLogParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     t.ID,
+		ExerciseID:     squat.ID,
+		PrescriptionID: null.NewInt64(prescriptions[0].ID, true),
+		SetNumber:      1,
+		Reps:           5,
+		Weight:         100,
+	})
+	s.Require().NoError(err)
+
+	// replacing the prescriptions keeps the logged sets
+	prescriptions, err = s.store.SetTrainingExercisesTx(context.Background(), SetTrainingExercisesTxParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     t.ID,
+		Prescriptions: []CreateExercisePrescriptionParams{
+			{OrganizationID: s.org.ID, TrainingID: t.ID, ExerciseID: deadlift.ID, Position: 1, Sets: 3, Reps: 3,
+				Rpe: null.NewFloat64(8, true)},
+			{OrganizationID: s.org.ID, TrainingID: t.ID, ExerciseID: squat.ID, Position: 2, Sets: 3, Reps: 8},
+		},
+	})
+	s.Require().NoError(err)
+
+	listed, err := s.q.ListTrainingPrescriptions(context.Background(), ListTrainingPrescriptionsParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     t.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(listed, 2)
+	s.Equal(deadlift.ID, listed[0].ExerciseID)
+	s.Equal(prescriptions[1].ID, listed[1].ID)
+
+	log, err = s.q.GetExerciseLog(context.Background(), GetExerciseLogParams{OrganizationID: s.org.ID, ID: log.ID})
+	s.Require().NoError(err)
+	s.False(log.PrescriptionID.Valid)
+}
+
+func (s *DbTestSuite) TestListExerciseHistory() {
+	u := s.createUser(UserTypeAthlete, true)
+	other := s.createUser(UserTypeAthlete, true)
+	squat := s.createExercise("Squat")
+
+	old := s.createTraining(u.ID)
+	old, err := s.q.UpdateTraining(context.Background(), UpdateTrainingParams{
+		OrganizationID: s.org.ID,
+		ID:             old.ID,
+		Date:           old.Date.AddDate(0, 0, -30),
+		Sport:          old.Sport,
+		Details:        old.Details,
+		Status:         old.Status,
+		Version:        old.Version,
+	})
+	s.Require().NoError(err)
+	s.createExerciseLog(old, squat.ID, 5, 90)
+	recent := s.createTraining(u.ID)
+	s.createExerciseLog(recent, squat.ID, 5, 100)
+	s.createExerciseLog(recent, squat.ID, 3, 105)
+	s.createExerciseLog(s.createTraining(other.ID), squat.ID, 5, 60)
+
+	history, err := s.q.ListExerciseHistory(context.Background(), ListExerciseHistoryParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		StartDate:      recent.Date.AddDate(0, 0, -7),
+		EndDate:        recent.Date,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(history, 2)
+	s.Equal(100.0, history[0].Weight)
+	s.Equal(int32(3), history[1].Reps)
+
+	logs, err := s.q.ListAllExerciseLogsByUser(context.Background(), ListAllExerciseLogsByUserParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+	})
+	s.Require().NoError(err)
+	s.Len(logs, 3)
+}
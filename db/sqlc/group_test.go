@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createGroup(members int) (Group, []User) {
+	coach := s.createUser(UserTypeCoach, true)
+
+	arg := CreateGroupParams{
+		OrganizationID: s.org.ID,
+		Name:           s.f.Lorem().Word(),
+		CoachID:        null.NewInt64(coach.ID, true),
+	}
+
+	group, err := s.q.CreateGroup(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Name, group.Name)
+	s.Equal(arg.CoachID, group.CoachID)
+	s.False(group.DeletedAt.Valid)
+
+	users := make([]User, 0, members)
+	for i := 0; i < members; i++ {
+		u := s.createUser(UserTypeAthlete, true)
+		member, err := s.q.AddGroupMember(context.Background(), AddGroupMemberParams{GroupID: group.ID, UserID: u.ID})
+		s.Require().NoError(err)
+		s.Equal(u.ID, member.UserID)
+		users = append(users, u)
+	}
+
+	return group, users
+}
+
+func (s *DbTestSuite) createGroupTraining(groupID int64) GroupTrainingTxResult {
+	date, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+
+	arg := CreateGroupTrainingTxParams{
+		GroupTraining: CreateGroupTrainingParams{
+			OrganizationID:  s.org.ID,
+			GroupID:         groupID,
+			Date:            date,
+			Sport:           "running",
+			Details:         "10 x 400m",
+			PlannedDuration: null.NewInt32(3600, true),
+		},
+		Status: TrainingStatusNew,
+	}
+
+	result, err := s.store.CreateGroupTrainingTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.GroupTraining.Details, result.GroupTraining.Details)
+
+	return result
+}
+
+func (s *DbTestSuite) TestGroupMembers() {
+	group, users := s.createGroup(3)
+
+	// adding a member twice is a no-op
+	_, err := s.q.AddGroupMember(context.Background(), AddGroupMemberParams{GroupID: group.ID, UserID: users[0].ID})
+	s.Require().NoError(err)
+
+	err = s.q.RemoveGroupMember(context.Background(), RemoveGroupMemberParams{GroupID: group.ID, UserID: users[1].ID})
+	s.Require().NoError(err)
+
+	members, err := s.q.ListGroupMembers(context.Background(), ListGroupMembersParams{OrganizationID: s.org.ID, GroupID: group.ID})
+	s.Require().NoError(err)
+	s.Len(members, 2)
+	s.Equal(users[0].ID, members[0].ID)
+	s.Equal(users[2].ID, members[1].ID)
+}
+
+func (s *DbTestSuite) TestDeleteGroup() {
+	group, _ := s.createGroup(0)
+
+	err := s.q.DeleteGroup(context.Background(), DeleteGroupParams{OrganizationID: s.org.ID, ID: group.ID})
+	s.Require().NoError(err)
+
+	_, err = s.q.GetGroup(context.Background(), GetGroupParams{OrganizationID: s.org.ID, ID: group.ID})
+	s.Error(err)
+}
+
+func (s *DbTestSuite) TestCreateGroupTrainingTx() {
+	group, users := s.createGroup(3)
+	result := s.createGroupTraining(group.ID)
+
+	s.Len(result.Trainings, len(users))
+	for i, t := range result.Trainings {
+		s.Equal(users[i].ID, t.UserID)
+		s.Equal(null.NewInt64(result.GroupTraining.ID, true), t.GroupTrainingID)
+		s.Equal(result.GroupTraining.Details, t.Details)
+		s.Equal(result.GroupTraining.PlannedDuration, t.PlannedDuration)
+		s.Equal(TrainingStatusNew, t.Status)
+	}
+}
+
+func (s *DbTestSuite) TestUpdateGroupTrainingTx() {
+	group, _ := s.createGroup(3)
+	result := s.createGroupTraining(group.ID)
+	done := result.Trainings[0]
+
+	_, err := s.q.UpdateTraining(context.Background(), UpdateTrainingParams{
+		OrganizationID: s.org.ID,
+		ID:             done.ID,
+		Date:           done.Date,
+		Sport:          done.Sport,
+		Details:        done.Details,
+		Status:         TrainingStatusDone,
+		Version:        done.Version,
+	})
+	s.Require().NoError(err)
+
+	// the attributes given to a member are dropped when the sport changes
+	member := result.Trainings[1]
+	_, err = s.q.UpdateTraining(context.Background(), UpdateTrainingParams{
+		OrganizationID: s.org.ID,
+		ID:             member.ID,
+		Date:           member.Date,
+		Sport:          member.Sport,
+		Details:        member.Details,
+		Status:         member.Status,
+		Attributes:     json.RawMessage(`{"distance": 4000}`),
+		Version:        member.Version,
+	})
+	s.Require().NoError(err)
+
+	gt := result.GroupTraining
+	arg := UpdateGroupTrainingTxParams{
+		GroupTraining: UpdateGroupTrainingParams{
+			OrganizationID:  s.org.ID,
+			ID:              gt.ID,
+			Date:            gt.Date.AddDate(0, 0, 1),
+			Sport:           "cycling",
+			Details:         "8 x 400m",
+			PlannedDuration: null.NewInt32(2700, true),
+		},
+		Propagate: true,
+	}
+
+	updated, err := s.store.UpdateGroupTrainingTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.GroupTraining.Details, updated.GroupTraining.Details)
+	s.Len(updated.Trainings, 2)
+	for _, t := range updated.Trainings {
+		s.NotEqual(done.ID, t.ID)
+		s.Equal(arg.GroupTraining.Details, t.Details)
+		s.Equal(arg.GroupTraining.Date, t.Date)
+		s.Equal(arg.GroupTraining.Sport, t.Sport)
+		s.Equal(arg.GroupTraining.PlannedDuration, t.PlannedDuration)
+		s.Nil(t.Attributes)
+	}
+
+	// the completed training is left untouched
+	training, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: done.ID})
+	s.Require().NoError(err)
+	s.Equal(gt.Details, training.Details)
+
+	// without propagation only the group training changes
+	arg.GroupTraining.Details = "6 x 400m"
+	arg.Propagate = false
+	updated, err = s.store.UpdateGroupTrainingTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Empty(updated.Trainings)
+}
+
+func (s *DbTestSuite) TestDeleteGroupTrainingTx() {
+	group, _ := s.createGroup(2)
+	result := s.createGroupTraining(group.ID)
+
+	deleted, err := s.store.DeleteGroupTrainingTx(context.Background(), DeleteGroupTrainingTxParams{
+		OrganizationID:  s.org.ID,
+		GroupTrainingID: result.GroupTraining.ID,
+		Propagate:       true,
+	})
+	s.Require().NoError(err)
+	s.Len(deleted.Trainings, 2)
+
+	_, err = s.q.GetGroupTraining(context.Background(), GetGroupTrainingParams{OrganizationID: s.org.ID, ID: result.GroupTraining.ID})
+	s.Error(err)
+
+	trainings, err := s.q.ListTrainingsByGroupTraining(context.Background(), ListTrainingsByGroupTrainingParams{OrganizationID: s.org.ID, GroupTrainingID: null.NewInt64(result.GroupTraining.ID, true)})
+	s.Require().NoError(err)
+	s.Empty(trainings)
+}
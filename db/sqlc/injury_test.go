@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createInjury(userID int64, onset time.Time, expectedReturn null.Time) Injury {
+	arg := CreateInjuryParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		Kind:           InjuryKindInjury,
+		BodyPart:       null.NewString("left calf", true),
+		Diagnosis:      "strain",
+		Onset:          onset,
+		Severity:       InjurySeverityModerate,
+		Status:         InjuryStatusActive,
+		ExpectedReturn: expectedReturn,
+	}
+
+	injury, err := s.q.CreateInjury(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Diagnosis, injury.Diagnosis)
+	s.Equal(arg.BodyPart, injury.BodyPart)
+	s.Equal(arg.Severity, injury.Severity)
+	s.Equal(arg.ExpectedReturn.Valid, injury.ExpectedReturn.Valid)
+
+	return injury
+}
+
+func (s *DbTestSuite) createDatedTraining(userID int64, date time.Time, status TrainingStatus) Training {
+	training, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		Date:           date,
+		Sport:          "running",
+		Details:        "planned run",
+		Status:         status,
+	})
+	s.Require().NoError(err)
+	return training
+}
+
+func (s *DbTestSuite) TestListInjuryTrainings() {
+	u := s.createUser(UserTypeAthlete, true)
+	onset, _ := time.Parse("2006-01-02", "2021-06-01")
+	injury := s.createInjury(u.ID, onset, null.NewTime(onset.AddDate(0, 0, 14), true))
+
+	s.createDatedTraining(u.ID, onset.AddDate(0, 0, -1), TrainingStatusNew)
+	s.createDatedTraining(u.ID, onset, TrainingStatusDone)
+	inside := s.createDatedTraining(u.ID, onset.AddDate(0, 0, 3), TrainingStatusNew)
+	s.createDatedTraining(u.ID, onset.AddDate(0, 0, 14), TrainingStatusNew)
+
+	trainings, err := s.q.ListInjuryTrainings(context.Background(), ListInjuryTrainingsParams{OrganizationID: s.org.ID, ID: injury.ID})
+	s.Require().NoError(err)
+	s.Require().Len(trainings, 1)
+	s.Equal(inside.ID, trainings[0].ID)
+
+	// without an expected return every later planned training is included
+	open := s.createInjury(u.ID, onset, null.Time{})
+	trainings, err = s.q.ListInjuryTrainings(context.Background(), ListInjuryTrainingsParams{OrganizationID: s.org.ID, ID: open.ID})
+	s.Require().NoError(err)
+	s.Len(trainings, 2)
+}
+
+func (s *DbTestSuite) TestListInjuryFeedbacks() {
+	u := s.createUser(UserTypeAthlete, true)
+	onset, _ := time.Parse("2006-01-02", "2021-06-01")
+	injury := s.createInjury(u.ID, onset, null.Time{})
+	t := s.createDatedTraining(u.ID, onset, TrainingStatusDoneFeedback)
+
+	feedback, err := s.q.CreateTrainingFeedback(context.Background(), CreateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     t.ID,
+		BorgScale:      12,
+		Pain:           null.NewInt32(6, true),
+		InjuryID:       null.NewInt64(injury.ID, true),
+	})
+	s.Require().NoError(err)
+
+	feedbacks, err := s.q.ListInjuryFeedbacks(context.Background(), ListInjuryFeedbacksParams{
+		OrganizationID: s.org.ID,
+		InjuryID:       null.NewInt64(injury.ID, true),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(feedbacks, 1)
+	s.Equal(feedback.ID, feedbacks[0].ID)
+	s.Equal(null.NewInt32(6, true), feedbacks[0].Pain)
+
+	// the pain report is kept when the injury is deleted
+	err = s.q.DeleteInjury(context.Background(), DeleteInjuryParams{OrganizationID: s.org.ID, ID: injury.ID})
+	s.Require().NoError(err)
+	feedback, err = s.q.GetTrainingFeedback(context.Background(), GetTrainingFeedbackParams{OrganizationID: s.org.ID, TrainingID: t.ID})
+	s.Require().NoError(err)
+	s.False(feedback.InjuryID.Valid)
+	s.True(feedback.Pain.Valid)
+}
+
+func (s *DbTestSuite) TestPauseTrainingsTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	onset, _ := time.Parse("2006-01-02", "2021-06-01")
+	injury := s.createInjury(u.ID, onset, null.NewTime(onset.AddDate(0, 0, 7), true))
+	first := s.createDatedTraining(u.ID, onset.AddDate(0, 0, 1), TrainingStatusNew)
+	second := s.createDatedTraining(u.ID, onset.AddDate(0, 0, 2), TrainingStatusNotified)
+
+	trainings, err := s.store.PauseTrainingsTx(context.Background(), PauseTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		InjuryID:       injury.ID,
+		Days:           6,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(trainings, 2)
+	s.Equal(first.ID, trainings[0].ID)
+	s.Equal(first.Date.AddDate(0, 0, 6).Format("2006-01-02"), trainings[0].Date.Format("2006-01-02"))
+	s.Equal(second.Status, trainings[1].Status)
+
+	// the rescheduled trainings are after the expected return
+	trainings, err = s.store.PauseTrainingsTx(context.Background(), PauseTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		InjuryID:       injury.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(trainings, 0)
+
+	// without days the trainings are cancelled
+	third := s.createDatedTraining(u.ID, onset.AddDate(0, 0, 3), TrainingStatusNew)
+	trainings, err = s.store.PauseTrainingsTx(context.Background(), PauseTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		InjuryID:       injury.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(trainings, 1)
+	s.Equal(third.ID, trainings[0].ID)
+
+	_, err = s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: third.ID})
+	s.Require().Error(err)
+}
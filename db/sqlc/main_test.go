@@ -18,8 +18,10 @@ func TestDbTestSuite(t *testing.T) {
 
 type DbTestSuite struct {
 	suite.Suite
-	q *Queries
-	f faker.Faker
+	q     *Queries
+	store Store
+	f     faker.Faker
+	org   Organization
 }
 
 func (s *DbTestSuite) SetupSuite() {
@@ -35,13 +37,55 @@ func (s *DbTestSuite) SetupSuite() {
 
 	// todo: run the tests in a fresh containerized DB
 	// clean up test DB
+	_, err = conn.Exec(`DELETE FROM audit_log`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM test_result`)
+	s.Require().NoError(err)
 	_, err = conn.Exec(`DELETE FROM training_feedback`)
 	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM injury`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM training_leg`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM exercise_log`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM exercise_prescription`)
+	s.Require().NoError(err)
 	_, err = conn.Exec(`DELETE FROM training`)
 	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM training_series`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM group_training`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM group_member`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM groups`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM zone_model`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM race`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM wellness`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM availability`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM blackout`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM equipment`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM api_token`)
+	s.Require().NoError(err)
 	_, err = conn.Exec(`DELETE FROM users`)
 	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM exercise`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM sport WHERE organization_id IS NOT NULL`)
+	s.Require().NoError(err)
+	_, err = conn.Exec(`DELETE FROM organization WHERE slug <> 'default'`)
+	s.Require().NoError(err)
 
 	s.q = New(conn)
+	s.store = NewStore(conn)
 	s.f = faker.New()
+	s.org = s.createOrganization()
 }
@@ -3,12 +3,57 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/emvi/null"
 )
 
+type CyclePhase string
+
+const (
+	CyclePhaseMenstrual  CyclePhase = "menstrual"
+	CyclePhaseFollicular CyclePhase = "follicular"
+	CyclePhaseOvulation  CyclePhase = "ovulation"
+	CyclePhaseLuteal     CyclePhase = "luteal"
+)
+
+func (e *CyclePhase) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CyclePhase(s)
+	case string:
+		*e = CyclePhase(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CyclePhase: %T", src)
+	}
+	return nil
+}
+
+type EquipmentType string
+
+const (
+	EquipmentTypeShoes   EquipmentType = "shoes"
+	EquipmentTypeBike    EquipmentType = "bike"
+	EquipmentTypeChain   EquipmentType = "chain"
+	EquipmentTypeWetsuit EquipmentType = "wetsuit"
+	EquipmentTypeOther   EquipmentType = "other"
+)
+
+func (e *EquipmentType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EquipmentType(s)
+	case string:
+		*e = EquipmentType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EquipmentType: %T", src)
+	}
+	return nil
+}
+
 type GenderType string
 
 const (
@@ -28,23 +73,102 @@ func (e *GenderType) Scan(src interface{}) error {
 	return nil
 }
 
-type TrainingSport string
+type InjuryKind string
+
+const (
+	InjuryKindInjury  InjuryKind = "injury"
+	InjuryKindIllness InjuryKind = "illness"
+)
+
+func (e *InjuryKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InjuryKind(s)
+	case string:
+		*e = InjuryKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InjuryKind: %T", src)
+	}
+	return nil
+}
+
+type InjurySeverity string
+
+const (
+	InjurySeverityMinor    InjurySeverity = "minor"
+	InjurySeverityModerate InjurySeverity = "moderate"
+	InjurySeveritySevere   InjurySeverity = "severe"
+)
+
+func (e *InjurySeverity) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InjurySeverity(s)
+	case string:
+		*e = InjurySeverity(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InjurySeverity: %T", src)
+	}
+	return nil
+}
+
+type InjuryStatus string
+
+const (
+	InjuryStatusActive     InjuryStatus = "active"
+	InjuryStatusRecovering InjuryStatus = "recovering"
+	InjuryStatusResolved   InjuryStatus = "resolved"
+)
+
+func (e *InjuryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InjuryStatus(s)
+	case string:
+		*e = InjuryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InjuryStatus: %T", src)
+	}
+	return nil
+}
+
+type RacePriority string
+
+const (
+	RacePriorityA RacePriority = "A"
+	RacePriorityB RacePriority = "B"
+	RacePriorityC RacePriority = "C"
+)
+
+func (e *RacePriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RacePriority(s)
+	case string:
+		*e = RacePriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RacePriority: %T", src)
+	}
+	return nil
+}
+
+type TestProtocol string
 
 const (
-	TrainingSportRunning  TrainingSport = "running"
-	TrainingSportCycling  TrainingSport = "cycling"
-	TrainingSportSwimming TrainingSport = "swimming"
-	TrainingSportWeight   TrainingSport = "weight"
+	TestProtocolCooper TestProtocol = "cooper"
+	TestProtocolTt30   TestProtocol = "tt30"
+	TestProtocolFtp20  TestProtocol = "ftp20"
+	TestProtocolCss    TestProtocol = "css"
 )
 
-func (e *TrainingSport) Scan(src interface{}) error {
+func (e *TestProtocol) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = TrainingSport(s)
+		*e = TestProtocol(s)
 	case string:
-		*e = TrainingSport(s)
+		*e = TestProtocol(s)
 	default:
-		return fmt.Errorf("unsupported scan type for TrainingSport: %T", src)
+		return fmt.Errorf("unsupported scan type for TestProtocol: %T", src)
 	}
 	return nil
 }
@@ -71,6 +195,25 @@ func (e *TrainingStatus) Scan(src interface{}) error {
 	return nil
 }
 
+type UnitSystem string
+
+const (
+	UnitSystemMetric   UnitSystem = "metric"
+	UnitSystemImperial UnitSystem = "imperial"
+)
+
+func (e *UnitSystem) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UnitSystem(s)
+	case string:
+		*e = UnitSystem(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UnitSystem: %T", src)
+	}
+	return nil
+}
+
 type UserType string
 
 const (
@@ -91,34 +234,343 @@ func (e *UserType) Scan(src interface{}) error {
 	return nil
 }
 
+type ZoneMethod string
+
+const (
+	ZoneMethodHrMax      ZoneMethod = "hr_max"
+	ZoneMethodHrLthr     ZoneMethod = "hr_lthr"
+	ZoneMethodHrKarvonen ZoneMethod = "hr_karvonen"
+	ZoneMethodPace       ZoneMethod = "pace"
+	ZoneMethodPower      ZoneMethod = "power"
+	ZoneMethodCss        ZoneMethod = "css"
+)
+
+func (e *ZoneMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ZoneMethod(s)
+	case string:
+		*e = ZoneMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ZoneMethod: %T", src)
+	}
+	return nil
+}
+
+type ApiToken struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organization_id"`
+	UserID         int64     `json:"user_id"`
+	TokenHash      []byte    `json:"token_hash"`
+	CreatedAt      time.Time `json:"created_at"`
+	RevokedAt      null.Time `json:"revoked_at"`
+}
+
+type AuditLog struct {
+	ID             int64           `json:"id"`
+	ActorID        null.Int64      `json:"actor_id"`
+	Action         string          `json:"action"`
+	EntityType     string          `json:"entity_type"`
+	EntityID       int64           `json:"entity_id"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	RequestID      string          `json:"request_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	OrganizationID int64           `json:"organization_id"`
+}
+
+type Availability struct {
+	ID             int64      `json:"id"`
+	OrganizationID int64      `json:"organization_id"`
+	UserID         int64      `json:"user_id"`
+	Weekday        int32      `json:"weekday"`
+	StartMinute    int32      `json:"start_minute"`
+	EndMinute      int32      `json:"end_minute"`
+	MaxDuration    null.Int32 `json:"max_duration"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type Blackout struct {
+	ID             int64       `json:"id"`
+	OrganizationID int64       `json:"organization_id"`
+	UserID         int64       `json:"user_id"`
+	StartDate      time.Time   `json:"start_date"`
+	EndDate        time.Time   `json:"end_date"`
+	Reason         null.String `json:"reason"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+type Equipment struct {
+	ID                int64         `json:"id"`
+	OrganizationID    int64         `json:"organization_id"`
+	UserID            int64         `json:"user_id"`
+	Type              EquipmentType `json:"type"`
+	Brand             string        `json:"brand"`
+	Model             string        `json:"model"`
+	StartDate         time.Time     `json:"start_date"`
+	ThresholdDistance null.Int32    `json:"threshold_distance"`
+	ThresholdDuration null.Int32    `json:"threshold_duration"`
+	RetiredOn         null.Time     `json:"retired_on"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
+
+type EquipmentAlert struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organization_id"`
+	EquipmentID    int64     `json:"equipment_id"`
+	Metric         string    `json:"metric"`
+	Threshold      int32     `json:"threshold"`
+	Value          int64     `json:"value"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type Exercise struct {
+	ID             int64        `json:"id"`
+	OrganizationID int64        `json:"organization_id"`
+	Name           string       `json:"name"`
+	MuscleGroups   []string     `json:"muscle_groups"`
+	Equipment      null.String  `json:"equipment"`
+	VideoUrl       null.String  `json:"video_url"`
+	CreatedAt      time.Time    `json:"created_at"`
+	DeletedAt      sql.NullTime `json:"deleted_at"`
+}
+
+type ExerciseLog struct {
+	ID             int64        `json:"id"`
+	OrganizationID int64        `json:"organization_id"`
+	TrainingID     int64        `json:"training_id"`
+	ExerciseID     int64        `json:"exercise_id"`
+	PrescriptionID null.Int64   `json:"prescription_id"`
+	SetNumber      int32        `json:"set_number"`
+	Reps           int32        `json:"reps"`
+	Weight         float64      `json:"weight"`
+	Rpe            null.Float64 `json:"rpe"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type ExercisePrescription struct {
+	ID             int64        `json:"id"`
+	OrganizationID int64        `json:"organization_id"`
+	TrainingID     int64        `json:"training_id"`
+	ExerciseID     int64        `json:"exercise_id"`
+	Position       int32        `json:"position"`
+	Sets           int32        `json:"sets"`
+	Reps           int32        `json:"reps"`
+	Percent1rm     null.Float64 `json:"percent_1rm"`
+	Rpe            null.Float64 `json:"rpe"`
+	Rest           null.Int32   `json:"rest"`
+	Notes          null.String  `json:"notes"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type Group struct {
+	ID             int64      `json:"id"`
+	Name           string     `json:"name"`
+	CoachID        null.Int64 `json:"coach_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      null.Time  `json:"deleted_at"`
+	OrganizationID int64      `json:"organization_id"`
+}
+
+type GroupMember struct {
+	GroupID   int64     `json:"group_id"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GroupTraining struct {
+	ID              int64       `json:"id"`
+	GroupID         int64       `json:"group_id"`
+	Date            time.Time   `json:"date"`
+	Sport           string      `json:"sport"`
+	Type            null.String `json:"type"`
+	Intensity       null.String `json:"intensity"`
+	Details         string      `json:"details"`
+	CreatedAt       time.Time   `json:"created_at"`
+	DeletedAt       null.Time   `json:"deleted_at"`
+	OrganizationID  int64       `json:"organization_id"`
+	PlannedDuration null.Int32  `json:"planned_duration"`
+}
+
+type Injury struct {
+	ID             int64          `json:"id"`
+	OrganizationID int64          `json:"organization_id"`
+	UserID         int64          `json:"user_id"`
+	Kind           InjuryKind     `json:"kind"`
+	BodyPart       null.String    `json:"body_part"`
+	Diagnosis      string         `json:"diagnosis"`
+	Onset          time.Time      `json:"onset"`
+	Severity       InjurySeverity `json:"severity"`
+	Status         InjuryStatus   `json:"status"`
+	ExpectedReturn null.Time      `json:"expected_return"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type Organization struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Slug      string          `json:"slug"`
+	Settings  json.RawMessage `json:"settings"`
+	CreatedAt time.Time       `json:"created_at"`
+	DeletedAt null.Time       `json:"deleted_at"`
+}
+
+type Race struct {
+	ID             int64        `json:"id"`
+	OrganizationID int64        `json:"organization_id"`
+	UserID         int64        `json:"user_id"`
+	Name           string       `json:"name"`
+	Date           time.Time    `json:"date"`
+	Sport          string       `json:"sport"`
+	Distance       int32        `json:"distance"`
+	Priority       RacePriority `json:"priority"`
+	GoalTime       null.Int32   `json:"goal_time"`
+	ResultTime     null.Int32   `json:"result_time"`
+	CreatedAt      time.Time    `json:"created_at"`
+	DeletedAt      null.Time    `json:"deleted_at"`
+}
+
+type Sport struct {
+	ID             int64           `json:"id"`
+	OrganizationID null.Int64      `json:"organization_id"`
+	Slug           string          `json:"slug"`
+	Name           string          `json:"name"`
+	Metrics        json.RawMessage `json:"metrics"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeletedAt      sql.NullTime    `json:"deleted_at"`
+}
+
+type TestResult struct {
+	ID             int64        `json:"id"`
+	OrganizationID int64        `json:"organization_id"`
+	UserID         int64        `json:"user_id"`
+	TrainingID     int64        `json:"training_id"`
+	Protocol       TestProtocol `json:"protocol"`
+	Distance       null.Int32   `json:"distance"`
+	AvgPower       null.Int32   `json:"avg_power"`
+	AvgHr          null.Int32   `json:"avg_hr"`
+	Time400m       null.Int32   `json:"time_400m"`
+	Time200m       null.Int32   `json:"time_200m"`
+	Vo2max         null.Float64 `json:"vo2max"`
+	ThresholdPace  null.Int32   `json:"threshold_pace"`
+	Ftp            null.Int32   `json:"ftp"`
+	Css            null.Int32   `json:"css"`
+	Lthr           null.Int32   `json:"lthr"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
 type Training struct {
-	ID        int64          `json:"id"`
-	UserID    int64          `json:"user_id"`
-	Date      time.Time      `json:"date"`
-	Sport     TrainingSport  `json:"sport"`
-	Type      null.String    `json:"type"`
-	Intensity null.String    `json:"intensity"`
-	Details   string         `json:"details"`
-	Status    TrainingStatus `json:"status"`
-	CreatedAt time.Time      `json:"created_at"`
-	DeletedAt null.Time      `json:"deleted_at"`
+	ID              int64           `json:"id"`
+	UserID          int64           `json:"user_id"`
+	Date            time.Time       `json:"date"`
+	Sport           string          `json:"sport"`
+	Type            null.String     `json:"type"`
+	Intensity       null.String     `json:"intensity"`
+	Details         string          `json:"details"`
+	Status          TrainingStatus  `json:"status"`
+	CreatedAt       time.Time       `json:"created_at"`
+	DeletedAt       null.Time       `json:"deleted_at"`
+	SeriesID        null.Int64      `json:"series_id"`
+	GroupTrainingID null.Int64      `json:"group_training_id"`
+	OrganizationID  int64           `json:"organization_id"`
+	PlannedDuration null.Int32      `json:"planned_duration"`
+	Attributes      json.RawMessage `json:"attributes"`
+	Version         int32           `json:"version"`
+}
+
+type TrainingEquipment struct {
+	TrainingID  int64     `json:"training_id"`
+	EquipmentID int64     `json:"equipment_id"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type TrainingFeedback struct {
-	ID         int64 `json:"id"`
-	TrainingID int64 `json:"training_id"`
-	BorgScale  int32 `json:"borg_scale"`
+	ID             int64      `json:"id"`
+	TrainingID     int64      `json:"training_id"`
+	BorgScale      int32      `json:"borg_scale"`
+	OrganizationID int64      `json:"organization_id"`
+	Duration       null.Int32 `json:"duration"`
+	Distance       null.Int32 `json:"distance"`
+	AvgPower       null.Int32 `json:"avg_power"`
+	AvgHr          null.Int32 `json:"avg_hr"`
+	Pain           null.Int32 `json:"pain"`
+	InjuryID       null.Int64 `json:"injury_id"`
+	Version        int32      `json:"version"`
+}
+
+type TrainingLeg struct {
+	ID              int64           `json:"id"`
+	OrganizationID  int64           `json:"organization_id"`
+	TrainingID      int64           `json:"training_id"`
+	Position        int32           `json:"position"`
+	Sport           string          `json:"sport"`
+	Details         null.String     `json:"details"`
+	PlannedDuration null.Int32      `json:"planned_duration"`
+	Transition      null.Int32      `json:"transition"`
+	Attributes      json.RawMessage `json:"attributes"`
+	Duration        null.Int32      `json:"duration"`
+	Distance        null.Int32      `json:"distance"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+type TrainingSeries struct {
+	ID             int64       `json:"id"`
+	UserID         int64       `json:"user_id"`
+	Rrule          string      `json:"rrule"`
+	Dtstart        time.Time   `json:"dtstart"`
+	Exdate         string      `json:"exdate"`
+	Sport          string      `json:"sport"`
+	Type           null.String `json:"type"`
+	Intensity      null.String `json:"intensity"`
+	Details        string      `json:"details"`
+	CreatedAt      time.Time   `json:"created_at"`
+	DeletedAt      null.Time   `json:"deleted_at"`
+	OrganizationID int64       `json:"organization_id"`
 }
 
 type User struct {
-	ID        int64       `json:"id"`
-	Type      UserType    `json:"type"`
-	Name      string      `json:"name"`
-	Gender    GenderType  `json:"gender"`
-	Email     string      `json:"email"`
-	Phone     null.String `json:"phone"`
-	Birth     null.Time   `json:"birth"`
-	Active    bool        `json:"active"`
-	CreatedAt time.Time   `json:"created_at"`
-	DeletedAt null.Time   `json:"deleted_at"`
+	ID             int64       `json:"id"`
+	Type           UserType    `json:"type"`
+	Name           string      `json:"name"`
+	Gender         null.String `json:"gender"`
+	Email          string      `json:"email"`
+	Phone          null.String `json:"phone"`
+	Birth          null.Time   `json:"birth"`
+	Active         bool        `json:"active"`
+	CreatedAt      time.Time   `json:"created_at"`
+	DeletedAt      null.Time   `json:"deleted_at"`
+	OrganizationID int64       `json:"organization_id"`
+	Timezone       string      `json:"timezone"`
+	Locale         string      `json:"locale"`
+	Units          UnitSystem  `json:"units"`
+	Version        int32       `json:"version"`
+}
+
+type Wellness struct {
+	ID             int64        `json:"id"`
+	OrganizationID int64        `json:"organization_id"`
+	UserID         int64        `json:"user_id"`
+	Date           time.Time    `json:"date"`
+	Weight         null.Float64 `json:"weight"`
+	RestingHr      null.Int32   `json:"resting_hr"`
+	Hrv            null.Int32   `json:"hrv"`
+	SleepHours     null.Float64 `json:"sleep_hours"`
+	SleepQuality   null.Int32   `json:"sleep_quality"`
+	Soreness       null.Int32   `json:"soreness"`
+	Stress         null.Int32   `json:"stress"`
+	CyclePhase     null.String  `json:"cycle_phase"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type ZoneModel struct {
+	ID             int64      `json:"id"`
+	OrganizationID int64      `json:"organization_id"`
+	UserID         int64      `json:"user_id"`
+	Sport          string     `json:"sport"`
+	Method         ZoneMethod `json:"method"`
+	Threshold      int32      `json:"threshold"`
+	Resting        null.Int32 `json:"resting"`
+	EffectiveFrom  time.Time  `json:"effective_from"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      null.Time  `json:"deleted_at"`
 }
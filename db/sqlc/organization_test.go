@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createOrganization() Organization {
+	arg := CreateOrganizationParams{
+		Name:     s.f.Company().Name(),
+		Slug:     s.f.UUID().V4(),
+		Settings: json.RawMessage(`{}`),
+	}
+
+	organization, err := s.q.CreateOrganization(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Name, organization.Name)
+	s.Equal(arg.Slug, organization.Slug)
+	s.NotEmpty(organization.CreatedAt)
+	s.False(organization.DeletedAt.Valid)
+
+	return organization
+}
+
+func (s *DbTestSuite) TestUpdateOrganization() {
+	o := s.createOrganization()
+
+	arg := UpdateOrganizationParams{
+		ID:       o.ID,
+		Name:     s.f.Company().Name(),
+		Settings: json.RawMessage(`{"trash_retention_days":7}`),
+	}
+
+	organization, err := s.q.UpdateOrganization(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Name, organization.Name)
+	s.Equal(o.Slug, organization.Slug)
+	s.JSONEq(string(arg.Settings), string(organization.Settings))
+
+	bySlug, err := s.q.GetOrganizationBySlug(context.Background(), o.Slug)
+	s.Require().NoError(err)
+	s.Equal(o.ID, bySlug.ID)
+}
+
+func (s *DbTestSuite) TestCreateOrganizationTx() {
+	result, err := s.store.CreateOrganizationTx(context.Background(), CreateOrganizationTxParams{
+		Organization: CreateOrganizationParams{
+			Name:     s.f.Company().Name(),
+			Slug:     s.f.UUID().V4(),
+			Settings: json.RawMessage(`{}`),
+		},
+		Admin: CreateUserParams{
+			Name:     s.f.Person().Name(),
+			Gender:   null.NewString(string(GenderTypeF), true),
+			Email:    s.f.Internet().Email(),
+			Timezone: "UTC",
+			Locale:   "en",
+			Units:    UnitSystemMetric,
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(result.Organization.ID, result.Admin.OrganizationID)
+	s.Equal(UserTypeAdmin, result.Admin.Type)
+	s.True(result.Admin.Active)
+}
+
+func (s *DbTestSuite) TestTenantIsolation() {
+	other := s.createOrganization()
+	u := s.createUser(UserTypeAthlete, true)
+
+	// a user is not reachable from another organization
+	_, err := s.q.GetUser(context.Background(), GetUserParams{OrganizationID: other.ID, ID: u.ID})
+	s.Error(err)
+
+	err = s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: other.ID, ID: u.ID})
+	s.Require().NoError(err)
+
+	user, err := s.q.GetUser(context.Background(), GetUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().NoError(err)
+	s.False(user.DeletedAt.Valid)
+
+	training := s.createTraining(u.ID)
+	_, err = s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: other.ID, ID: training.ID})
+	s.Error(err)
+}
@@ -7,25 +7,175 @@ import (
 )
 
 type Querier interface {
+	AddGroupMember(ctx context.Context, arg AddGroupMemberParams) (GroupMember, error)
+	AddTrainingEquipment(ctx context.Context, arg AddTrainingEquipmentParams) error
+	ClearTrainingLegsFeedback(ctx context.Context, arg ClearTrainingLegsFeedbackParams) error
+	CreateApiToken(ctx context.Context, arg CreateApiTokenParams) (ApiToken, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
+	CreateAvailability(ctx context.Context, arg CreateAvailabilityParams) (Availability, error)
+	CreateBlackout(ctx context.Context, arg CreateBlackoutParams) (Blackout, error)
+	CreateEquipment(ctx context.Context, arg CreateEquipmentParams) (Equipment, error)
+	CreateEquipmentAlert(ctx context.Context, arg CreateEquipmentAlertParams) (EquipmentAlert, error)
+	CreateExercise(ctx context.Context, arg CreateExerciseParams) (Exercise, error)
+	CreateExerciseLog(ctx context.Context, arg CreateExerciseLogParams) (ExerciseLog, error)
+	CreateExercisePrescription(ctx context.Context, arg CreateExercisePrescriptionParams) (ExercisePrescription, error)
+	CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error)
+	CreateGroupTraining(ctx context.Context, arg CreateGroupTrainingParams) (GroupTraining, error)
+	CreateInjury(ctx context.Context, arg CreateInjuryParams) (Injury, error)
+	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error)
+	CreateRace(ctx context.Context, arg CreateRaceParams) (Race, error)
+	CreateSport(ctx context.Context, arg CreateSportParams) (Sport, error)
+	CreateTestResult(ctx context.Context, arg CreateTestResultParams) (TestResult, error)
 	CreateTraining(ctx context.Context, arg CreateTrainingParams) (Training, error)
 	CreateTrainingFeedback(ctx context.Context, arg CreateTrainingFeedbackParams) (TrainingFeedback, error)
+	CreateTrainingLeg(ctx context.Context, arg CreateTrainingLegParams) (TrainingLeg, error)
+	CreateTrainingSeries(ctx context.Context, arg CreateTrainingSeriesParams) (TrainingSeries, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteTraining(ctx context.Context, id int64) error
-	DeleteTrainingFeedback(ctx context.Context, trainingID int64) error
-	DeleteUser(ctx context.Context, id int64) error
-	GetTraining(ctx context.Context, id int64) (Training, error)
-	GetTrainingFeedback(ctx context.Context, trainingID int64) (TrainingFeedback, error)
-	GetUser(ctx context.Context, id int64) (User, error)
+	CreateWellness(ctx context.Context, arg CreateWellnessParams) (Wellness, error)
+	CreateZoneModel(ctx context.Context, arg CreateZoneModelParams) (ZoneModel, error)
+	DeleteAllApiTokensByUser(ctx context.Context, arg DeleteAllApiTokensByUserParams) error
+	DeleteAllAvailabilityByUser(ctx context.Context, arg DeleteAllAvailabilityByUserParams) error
+	DeleteAllBlackoutsByUser(ctx context.Context, arg DeleteAllBlackoutsByUserParams) error
+	DeleteAllInjuriesByUser(ctx context.Context, arg DeleteAllInjuriesByUserParams) error
+	DeleteAllRacesByUser(ctx context.Context, arg DeleteAllRacesByUserParams) error
+	DeleteAllTestResultsByUser(ctx context.Context, arg DeleteAllTestResultsByUserParams) error
+	DeleteAllWellnessByUser(ctx context.Context, arg DeleteAllWellnessByUserParams) error
+	DeleteAllZoneModelsByUser(ctx context.Context, arg DeleteAllZoneModelsByUserParams) error
+	DeleteAvailability(ctx context.Context, arg DeleteAvailabilityParams) error
+	DeleteBlackout(ctx context.Context, arg DeleteBlackoutParams) error
+	DeleteEquipment(ctx context.Context, arg DeleteEquipmentParams) error
+	DeleteExercise(ctx context.Context, arg DeleteExerciseParams) error
+	DeleteExerciseLog(ctx context.Context, arg DeleteExerciseLogParams) error
+	DeleteGroup(ctx context.Context, arg DeleteGroupParams) error
+	DeleteGroupTraining(ctx context.Context, arg DeleteGroupTrainingParams) error
+	DeleteInjury(ctx context.Context, arg DeleteInjuryParams) error
+	DeletePendingGroupTrainings(ctx context.Context, arg DeletePendingGroupTrainingsParams) ([]Training, error)
+	DeleteRace(ctx context.Context, arg DeleteRaceParams) error
+	DeleteSeriesTrainings(ctx context.Context, arg DeleteSeriesTrainingsParams) ([]Training, error)
+	DeleteSport(ctx context.Context, arg DeleteSportParams) error
+	DeleteTestResult(ctx context.Context, arg DeleteTestResultParams) error
+	DeleteTraining(ctx context.Context, arg DeleteTrainingParams) error
+	DeleteTrainingFeedback(ctx context.Context, arg DeleteTrainingFeedbackParams) error
+	DeleteTrainingLegs(ctx context.Context, arg DeleteTrainingLegsParams) error
+	DeleteTrainingPrescriptions(ctx context.Context, arg DeleteTrainingPrescriptionsParams) error
+	DeleteTrainingSeries(ctx context.Context, arg DeleteTrainingSeriesParams) error
+	DeleteUser(ctx context.Context, arg DeleteUserParams) error
+	DeleteWellness(ctx context.Context, arg DeleteWellnessParams) error
+	DeleteZoneModel(ctx context.Context, arg DeleteZoneModelParams) error
+	DetachSeriesTrainings(ctx context.Context, arg DetachSeriesTrainingsParams) ([]Training, error)
+	EraseUser(ctx context.Context, arg EraseUserParams) (User, error)
+	EraseUserTrainingFeedbacksPain(ctx context.Context, arg EraseUserTrainingFeedbacksPainParams) error
+	GetApiTokenUser(ctx context.Context, tokenHash []byte) (User, error)
+	GetAvailability(ctx context.Context, arg GetAvailabilityParams) (Availability, error)
+	GetBlackout(ctx context.Context, arg GetBlackoutParams) (Blackout, error)
+	GetDeletedTraining(ctx context.Context, arg GetDeletedTrainingParams) (Training, error)
+	GetDeletedUser(ctx context.Context, arg GetDeletedUserParams) (User, error)
+	GetEquipment(ctx context.Context, arg GetEquipmentParams) (Equipment, error)
+	GetExercise(ctx context.Context, arg GetExerciseParams) (Exercise, error)
+	GetExerciseLog(ctx context.Context, arg GetExerciseLogParams) (ExerciseLog, error)
+	GetGroup(ctx context.Context, arg GetGroupParams) (Group, error)
+	GetGroupTraining(ctx context.Context, arg GetGroupTrainingParams) (GroupTraining, error)
+	GetInjury(ctx context.Context, arg GetInjuryParams) (Injury, error)
+	GetOrganization(ctx context.Context, id int64) (Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
+	GetRace(ctx context.Context, arg GetRaceParams) (Race, error)
+	GetSport(ctx context.Context, arg GetSportParams) (Sport, error)
+	GetSportBySlug(ctx context.Context, arg GetSportBySlugParams) (Sport, error)
+	GetTestResult(ctx context.Context, arg GetTestResultParams) (TestResult, error)
+	GetTraining(ctx context.Context, arg GetTrainingParams) (Training, error)
+	GetTrainingFeedback(ctx context.Context, arg GetTrainingFeedbackParams) (TrainingFeedback, error)
+	GetTrainingSeries(ctx context.Context, arg GetTrainingSeriesParams) (TrainingSeries, error)
+	GetUser(ctx context.Context, arg GetUserParams) (User, error)
+	GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error)
+	GetUserIncludingDeleted(ctx context.Context, arg GetUserIncludingDeletedParams) (User, error)
+	GetWellness(ctx context.Context, arg GetWellnessParams) (Wellness, error)
+	GetZoneModel(ctx context.Context, arg GetZoneModelParams) (ZoneModel, error)
+	ListActiveAthletes(ctx context.Context, organizationID int64) ([]User, error)
+	ListActiveInjuriesByUser(ctx context.Context, arg ListActiveInjuriesByUserParams) ([]Injury, error)
 	ListActiveUsers(ctx context.Context, arg ListActiveUsersParams) ([]User, error)
+	ListAllAuditLogsByUser(ctx context.Context, arg ListAllAuditLogsByUserParams) ([]AuditLog, error)
+	ListAllExerciseLogsByUser(ctx context.Context, arg ListAllExerciseLogsByUserParams) ([]ExerciseLog, error)
+	ListAllExercisePrescriptionsByUser(ctx context.Context, arg ListAllExercisePrescriptionsByUserParams) ([]ExercisePrescription, error)
+	ListAllGroupMembershipsByUser(ctx context.Context, arg ListAllGroupMembershipsByUserParams) ([]GroupMember, error)
+	ListAllInjuriesByUser(ctx context.Context, arg ListAllInjuriesByUserParams) ([]Injury, error)
+	ListAllRacesByUser(ctx context.Context, arg ListAllRacesByUserParams) ([]Race, error)
+	ListAllTestResultsByUser(ctx context.Context, arg ListAllTestResultsByUserParams) ([]TestResult, error)
+	ListAllTrainingFeedbacksByUser(ctx context.Context, arg ListAllTrainingFeedbacksByUserParams) ([]TrainingFeedback, error)
+	ListAllTrainingLegsByUser(ctx context.Context, arg ListAllTrainingLegsByUserParams) ([]TrainingLeg, error)
+	ListAllTrainingSeriesByUser(ctx context.Context, arg ListAllTrainingSeriesByUserParams) ([]TrainingSeries, error)
+	ListAllTrainingsByUser(ctx context.Context, arg ListAllTrainingsByUserParams) ([]Training, error)
 	ListAllUsers(ctx context.Context, arg ListAllUsersParams) ([]User, error)
-	ListTrainingFeedbacksByUser(ctx context.Context, userID int64) ([]TrainingFeedback, error)
+	ListAllWellnessByUser(ctx context.Context, arg ListAllWellnessByUserParams) ([]Wellness, error)
+	ListAllZoneModelsByUser(ctx context.Context, arg ListAllZoneModelsByUserParams) ([]ZoneModel, error)
+	ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]AuditLog, error)
+	ListAvailabilityByUser(ctx context.Context, arg ListAvailabilityByUserParams) ([]Availability, error)
+	ListBlackoutsByUser(ctx context.Context, arg ListBlackoutsByUserParams) ([]Blackout, error)
+	ListBlackoutsByUserInPeriod(ctx context.Context, arg ListBlackoutsByUserInPeriodParams) ([]Blackout, error)
+	ListDeletedTrainings(ctx context.Context, arg ListDeletedTrainingsParams) ([]Training, error)
+	ListDeletedUsers(ctx context.Context, arg ListDeletedUsersParams) ([]User, error)
+	ListEffectiveZoneModels(ctx context.Context, arg ListEffectiveZoneModelsParams) ([]ZoneModel, error)
+	ListEquipmentAlertsByUser(ctx context.Context, arg ListEquipmentAlertsByUserParams) ([]EquipmentAlert, error)
+	ListEquipmentByUser(ctx context.Context, arg ListEquipmentByUserParams) ([]Equipment, error)
+	ListEquipmentUsage(ctx context.Context, arg ListEquipmentUsageParams) ([]ListEquipmentUsageRow, error)
+	ListExerciseHistory(ctx context.Context, arg ListExerciseHistoryParams) ([]ListExerciseHistoryRow, error)
+	ListExercises(ctx context.Context, organizationID int64) ([]Exercise, error)
+	ListGroupMembers(ctx context.Context, arg ListGroupMembersParams) ([]User, error)
+	ListGroupTrainings(ctx context.Context, arg ListGroupTrainingsParams) ([]GroupTraining, error)
+	ListGroups(ctx context.Context, arg ListGroupsParams) ([]Group, error)
+	ListInjuriesByUser(ctx context.Context, arg ListInjuriesByUserParams) ([]Injury, error)
+	ListInjuryFeedbacks(ctx context.Context, arg ListInjuryFeedbacksParams) ([]TrainingFeedback, error)
+	ListInjuryTrainings(ctx context.Context, arg ListInjuryTrainingsParams) ([]Training, error)
+	ListOrganizations(ctx context.Context) ([]Organization, error)
+	ListRaceResultsByUser(ctx context.Context, arg ListRaceResultsByUserParams) ([]Race, error)
+	ListRacesByUser(ctx context.Context, arg ListRacesByUserParams) ([]Race, error)
+	ListRacesByUserFrom(ctx context.Context, arg ListRacesByUserFromParams) ([]Race, error)
+	ListSports(ctx context.Context, organizationID int64) ([]Sport, error)
+	ListTestResultsByUser(ctx context.Context, arg ListTestResultsByUserParams) ([]TestResult, error)
+	ListTrainingEquipment(ctx context.Context, arg ListTrainingEquipmentParams) ([]Equipment, error)
+	ListTrainingExerciseLogs(ctx context.Context, arg ListTrainingExerciseLogsParams) ([]ExerciseLog, error)
+	ListTrainingFeedbacksByUser(ctx context.Context, arg ListTrainingFeedbacksByUserParams) ([]TrainingFeedback, error)
 	ListTrainingFeedbacksByUserInPeriod(ctx context.Context, arg ListTrainingFeedbacksByUserInPeriodParams) ([]TrainingFeedback, error)
-	ListTrainingsByUser(ctx context.Context, userID int64) ([]Training, error)
+	ListTrainingLegs(ctx context.Context, arg ListTrainingLegsParams) ([]TrainingLeg, error)
+	ListTrainingLegsByTrainings(ctx context.Context, arg ListTrainingLegsByTrainingsParams) ([]TrainingLeg, error)
+	ListTrainingLoadsByUser(ctx context.Context, arg ListTrainingLoadsByUserParams) ([]ListTrainingLoadsByUserRow, error)
+	ListTrainingPrescriptions(ctx context.Context, arg ListTrainingPrescriptionsParams) ([]ExercisePrescription, error)
+	ListTrainingSummaries(ctx context.Context, arg ListTrainingSummariesParams) ([]ListTrainingSummariesRow, error)
+	ListTrainingsByGroupTraining(ctx context.Context, arg ListTrainingsByGroupTrainingParams) ([]Training, error)
+	ListTrainingsBySeries(ctx context.Context, arg ListTrainingsBySeriesParams) ([]Training, error)
+	ListTrainingsByUser(ctx context.Context, arg ListTrainingsByUserParams) ([]Training, error)
 	ListTrainingsByUserInPeriod(ctx context.Context, arg ListTrainingsByUserInPeriodParams) ([]Training, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListWellnessByUserInPeriod(ctx context.Context, arg ListWellnessByUserInPeriodParams) ([]Wellness, error)
+	ListZoneModelsByUser(ctx context.Context, arg ListZoneModelsByUserParams) ([]ZoneModel, error)
+	MoveTraining(ctx context.Context, arg MoveTrainingParams) (Training, error)
+	PurgeTrainingFeedbacks(ctx context.Context, arg PurgeTrainingFeedbacksParams) ([]int64, error)
+	PurgeTrainingSeries(ctx context.Context, arg PurgeTrainingSeriesParams) ([]int64, error)
+	PurgeTrainings(ctx context.Context, arg PurgeTrainingsParams) ([]int64, error)
+	PurgeUsers(ctx context.Context, arg PurgeUsersParams) ([]int64, error)
+	RedactUserAuditLogs(ctx context.Context, arg RedactUserAuditLogsParams) error
+	RemoveGroupMember(ctx context.Context, arg RemoveGroupMemberParams) error
+	RemoveTrainingEquipment(ctx context.Context, arg RemoveTrainingEquipmentParams) error
+	RestoreTraining(ctx context.Context, arg RestoreTrainingParams) (Training, error)
+	RestoreTrainingsByUser(ctx context.Context, arg RestoreTrainingsByUserParams) ([]Training, error)
+	RestoreUser(ctx context.Context, arg RestoreUserParams) (User, error)
+	RevokeUserApiTokens(ctx context.Context, arg RevokeUserApiTokensParams) ([]int64, error)
+	TouchTraining(ctx context.Context, arg TouchTrainingParams) error
+	UpdateEquipment(ctx context.Context, arg UpdateEquipmentParams) (Equipment, error)
+	UpdateExercise(ctx context.Context, arg UpdateExerciseParams) (Exercise, error)
+	UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error)
+	UpdateGroupTraining(ctx context.Context, arg UpdateGroupTrainingParams) (GroupTraining, error)
+	UpdateInjury(ctx context.Context, arg UpdateInjuryParams) (Injury, error)
+	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error)
+	UpdatePendingGroupTrainings(ctx context.Context, arg UpdatePendingGroupTrainingsParams) ([]Training, error)
+	UpdateRace(ctx context.Context, arg UpdateRaceParams) (Race, error)
+	UpdateSeriesTrainings(ctx context.Context, arg UpdateSeriesTrainingsParams) ([]Training, error)
+	UpdateSport(ctx context.Context, arg UpdateSportParams) (Sport, error)
 	UpdateTraining(ctx context.Context, arg UpdateTrainingParams) (Training, error)
 	UpdateTrainingFeedback(ctx context.Context, arg UpdateTrainingFeedbackParams) (TrainingFeedback, error)
+	UpdateTrainingLegFeedback(ctx context.Context, arg UpdateTrainingLegFeedbackParams) (TrainingLeg, error)
+	UpdateTrainingSeries(ctx context.Context, arg UpdateTrainingSeriesParams) (TrainingSeries, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateWellness(ctx context.Context, arg UpdateWellnessParams) (Wellness, error)
 }
 
 var _ Querier = (*Queries)(nil)
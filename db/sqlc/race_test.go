@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createRace(userID int64, date time.Time, resultTime null.Int32) Race {
+	arg := CreateRaceParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		Name:           s.f.Lorem().Word(),
+		Date:           date,
+		Sport:          "running",
+		Distance:       10000,
+		Priority:       RacePriorityA,
+		GoalTime:       null.NewInt32(2400, true),
+		ResultTime:     resultTime,
+	}
+
+	race, err := s.q.CreateRace(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Name, race.Name)
+	s.Equal(arg.Priority, race.Priority)
+	s.Equal(arg.GoalTime, race.GoalTime)
+	s.Equal(arg.ResultTime, race.ResultTime)
+	s.False(race.DeletedAt.Valid)
+
+	return race
+}
+
+func (s *DbTestSuite) TestListRacesByUserFrom() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+
+	s.createRace(u.ID, date.AddDate(0, 0, -1), null.Int32{})
+	next := s.createRace(u.ID, date, null.Int32{})
+	later := s.createRace(u.ID, date.AddDate(0, 1, 0), null.Int32{})
+
+	races, err := s.q.ListRacesByUserFrom(context.Background(), ListRacesByUserFromParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		FromDate:       date,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(races, 2)
+	s.Equal(next.ID, races[0].ID)
+	s.Equal(later.ID, races[1].ID)
+}
+
+func (s *DbTestSuite) TestListRaceResultsByUser() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+
+	old := s.createRace(u.ID, date.AddDate(0, -1, 0), null.NewInt32(2500, true))
+	recent := s.createRace(u.ID, date, null.NewInt32(2450, true))
+	s.createRace(u.ID, date.AddDate(0, 1, 0), null.Int32{})
+
+	deleted := s.createRace(u.ID, date.AddDate(0, 0, 1), null.NewInt32(2400, true))
+	err := s.q.DeleteRace(context.Background(), DeleteRaceParams{OrganizationID: s.org.ID, ID: deleted.ID})
+	s.Require().NoError(err)
+
+	races, err := s.q.ListRaceResultsByUser(context.Background(), ListRaceResultsByUserParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		Sport:          "running",
+		Limit:          5,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(races, 2)
+	s.Equal(recent.ID, races[0].ID)
+	s.Equal(old.ID, races[1].ID)
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createSport(organizationID int64, slug string) Sport {
+	arg := CreateSportParams{
+		OrganizationID: null.NewInt64(organizationID, true),
+		Slug:           slug,
+		Name:           "Rowing",
+		Metrics:        json.RawMessage(`[{"key": "strokes", "name": "Strokes", "type": "integer"}]`),
+	}
+
+	sport, err := s.q.CreateSport(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.OrganizationID, sport.OrganizationID)
+	s.Equal(arg.Slug, sport.Slug)
+	s.JSONEq(string(arg.Metrics), string(sport.Metrics))
+	s.False(sport.DeletedAt.Valid)
+
+	return sport
+}
+
+func (s *DbTestSuite) TestListSports() {
+	other := s.createOrganization()
+	rowing := s.createSport(s.org.ID, "rowing")
+	s.createSport(other.ID, "yoga")
+
+	catalogue, err := s.q.ListSports(context.Background(), s.org.ID)
+	s.Require().NoError(err)
+
+	slugs := map[string]bool{}
+	for _, sport := range catalogue {
+		slugs[sport.Slug] = true
+	}
+	s.True(slugs["running"])
+	s.True(slugs["cycling"])
+	s.True(slugs["swimming"])
+	s.True(slugs["weight"])
+	s.True(slugs[rowing.Slug])
+	// the sports of other organizations aren't listed
+	s.False(slugs["yoga"])
+	// the built-in sports come first
+	s.False(catalogue[0].OrganizationID.Valid)
+}
+
+func (s *DbTestSuite) TestGetSportBySlug() {
+	running, err := s.q.GetSportBySlug(context.Background(), GetSportBySlugParams{OrganizationID: s.org.ID, Slug: "running"})
+	s.Require().NoError(err)
+	s.False(running.OrganizationID.Valid)
+
+	other := s.createOrganization()
+	s.createSport(other.ID, "mobility")
+	_, err = s.q.GetSportBySlug(context.Background(), GetSportBySlugParams{OrganizationID: s.org.ID, Slug: "mobility"})
+	s.Require().Error(err)
+
+	// the slug is unique per organization
+	_, err = s.q.CreateSport(context.Background(), CreateSportParams{
+		OrganizationID: null.NewInt64(other.ID, true),
+		Slug:           "mobility",
+		Name:           "Mobility",
+		Metrics:        json.RawMessage(`[]`),
+	})
+	s.Require().Error(err)
+}
+
+func (s *DbTestSuite) TestUpdateSport() {
+	sport := s.createSport(s.org.ID, "kayak")
+
+	updated, err := s.q.UpdateSport(context.Background(), UpdateSportParams{
+		OrganizationID: sport.OrganizationID,
+		ID:             sport.ID,
+		Name:           "Kayak",
+		Metrics:        json.RawMessage(`[]`),
+	})
+	s.Require().NoError(err)
+	s.Equal("Kayak", updated.Name)
+	s.Equal(sport.Slug, updated.Slug)
+	s.JSONEq(`[]`, string(updated.Metrics))
+
+	// the built-in sports have no organization
+	running, err := s.q.GetSportBySlug(context.Background(), GetSportBySlugParams{OrganizationID: s.org.ID, Slug: "running"})
+	s.Require().NoError(err)
+	_, err = s.q.UpdateSport(context.Background(), UpdateSportParams{
+		OrganizationID: null.NewInt64(s.org.ID, true),
+		ID:             running.ID,
+		Name:           "Jogging",
+		Metrics:        running.Metrics,
+	})
+	s.Require().Error(err)
+}
+
+func (s *DbTestSuite) TestDeleteSport() {
+	sport := s.createSport(s.org.ID, "climbing")
+
+	err := s.q.DeleteSport(context.Background(), DeleteSportParams{OrganizationID: sport.OrganizationID, ID: sport.ID})
+	s.Require().NoError(err)
+
+	_, err = s.q.GetSport(context.Background(), GetSportParams{OrganizationID: s.org.ID, ID: sport.ID})
+	s.Require().Error(err)
+
+	// the slug can be used again
+	s.createSport(s.org.ID, "climbing")
+}
+
+func (s *DbTestSuite) TestTrainingAttributes() {
+	u := s.createUser(UserTypeAthlete, true)
+	t := s.createTraining(u.ID)
+	s.Nil(t.Attributes)
+
+	updated, err := s.q.UpdateTraining(context.Background(), UpdateTrainingParams{
+		OrganizationID: s.org.ID,
+		ID:             t.ID,
+		Date:           t.Date,
+		Sport:          "swimming",
+		Details:        t.Details,
+		Status:         t.Status,
+		Attributes:     json.RawMessage(`{"pool_length": 25}`),
+		Version:        t.Version,
+	})
+	s.Require().NoError(err)
+	s.JSONEq(`{"pool_length": 25}`, string(updated.Attributes))
+}
@@ -0,0 +1,1258 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+// Store provides all functions to execute db queries and transactions
+type Store interface {
+	Querier
+	RestoreUserTx(ctx context.Context, arg RestoreUserTxParams) (RestoreUserTxResult, error)
+	PurgeDeletedTx(ctx context.Context, arg PurgeDeletedTxParams) (PurgeDeletedTxResult, error)
+	EraseUserTx(ctx context.Context, arg EraseUserTxParams) (User, error)
+	CreateTrainingsTx(ctx context.Context, args []CreateTrainingParams) ([]Training, error)
+	CreateTrainingTx(ctx context.Context, arg CreateTrainingTxParams) (TrainingTxResult, error)
+	UpdateTrainingTx(ctx context.Context, arg UpdateTrainingTxParams) (TrainingTxResult, error)
+	UpdateTrainingLegsFeedbackTx(ctx context.Context, arg UpdateTrainingLegsFeedbackTxParams) ([]TrainingLeg, error)
+	CreateTrainingSeriesTx(ctx context.Context, arg CreateTrainingSeriesTxParams) (TrainingSeriesTxResult, error)
+	UpdateTrainingSeriesTx(ctx context.Context, arg UpdateTrainingSeriesTxParams) (TrainingSeriesTxResult, error)
+	DeleteTrainingSeriesTx(ctx context.Context, arg DeleteTrainingSeriesTxParams) (DeleteTrainingSeriesTxResult, error)
+	CopyTrainingsTx(ctx context.Context, arg CopyTrainingsTxParams) (BulkTrainingsTxResult, error)
+	ShiftTrainingsTx(ctx context.Context, arg ShiftTrainingsTxParams) (BulkTrainingsTxResult, error)
+	CreateGroupTrainingTx(ctx context.Context, arg CreateGroupTrainingTxParams) (GroupTrainingTxResult, error)
+	UpdateGroupTrainingTx(ctx context.Context, arg UpdateGroupTrainingTxParams) (GroupTrainingTxResult, error)
+	DeleteGroupTrainingTx(ctx context.Context, arg DeleteGroupTrainingTxParams) (GroupTrainingTxResult, error)
+	CreateOrganizationTx(ctx context.Context, arg CreateOrganizationTxParams) (CreateOrganizationTxResult, error)
+	CreateTestResultTx(ctx context.Context, arg CreateTestResultTxParams) (CreateTestResultTxResult, error)
+	PauseTrainingsTx(ctx context.Context, arg PauseTrainingsTxParams) ([]Training, error)
+	SetTrainingExercisesTx(ctx context.Context, arg SetTrainingExercisesTxParams) ([]ExercisePrescription, error)
+	AuditTx(ctx context.Context, audit CreateAuditLogParams, fn func(Store) ([]AuditEntry, error)) error
+}
+
+// ErrTrainingConflict is returned by the bulk trainings transactions when they are
+// asked to fail on conflicts and a training lands on a day that already has trainings
+var ErrTrainingConflict = errors.New("trainings conflict with existing ones")
+
+// ErrVersionConflict is returned by the update transactions when the training was updated since the
+// version they were given
+var ErrVersionConflict = errors.New("the training was updated in the meantime")
+
+// SQLStore provides all functions to execute SQL queries and transactions
+type SQLStore struct {
+	*Queries
+	db *sql.DB
+	// inTx is set on the store given to the functions run by AuditTx, its transactions join the one of AuditTx
+	inTx bool
+}
+
+// NewStore creates a new Store
+func NewStore(db *sql.DB) Store {
+	return &SQLStore{
+		db:      db,
+		Queries: New(db),
+	}
+}
+
+// execTx executes a function within a database transaction
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	if store.inTx {
+		return fn(store.Queries)
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AuditEntry is a change to record in the audit log, the snapshots of the entity being marshalled to JSON.
+// The organization, actor and request of the entry are the ones of the audit params of the transaction.
+type AuditEntry struct {
+	Action     string      `json:"action"`
+	EntityType string      `json:"entity_type"`
+	EntityID   int64       `json:"entity_id"`
+	Before     interface{} `json:"before"`
+	After      interface{} `json:"after"`
+}
+
+// AuditTx executes fn within a database transaction and records the audit entries it returns in the same one,
+// so a change is never applied without its entry or the other way round. fn makes its changes through the
+// store it is given, whose transactions join the one of AuditTx.
+func (store *SQLStore) AuditTx(ctx context.Context, audit CreateAuditLogParams, fn func(Store) ([]AuditEntry, error)) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		entries, err := fn(&SQLStore{Queries: q, db: store.db, inTx: true})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			arg := audit
+			arg.Action = entry.Action
+			arg.EntityType = entry.EntityType
+			arg.EntityID = entry.EntityID
+			if arg.Before, err = snapshot(entry.Before); err != nil {
+				return err
+			}
+			if arg.After, err = snapshot(entry.After); err != nil {
+				return err
+			}
+			if _, err = q.CreateAuditLog(ctx, arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// snapshot is the JSON state of an entity recorded in the audit log, null when there is none
+func snapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return json.RawMessage("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+// RestoreUserTxParams contains the input parameters of the restore user transaction
+type RestoreUserTxParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	UserID         int64 `json:"user_id"`
+	Trainings      bool  `json:"trainings"`
+}
+
+// RestoreUserTxResult is the result of the restore user transaction
+type RestoreUserTxResult struct {
+	User      User       `json:"user"`
+	Trainings []Training `json:"trainings"`
+}
+
+// RestoreUserTx restores a soft-deleted user and, optionally, their trainings deleted since the user was.
+// The trainings deleted before are kept in the trash.
+func (store *SQLStore) RestoreUserTx(ctx context.Context, arg RestoreUserTxParams) (RestoreUserTxResult, error) {
+	result := RestoreUserTxResult{Trainings: []Training{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		deleted, err := q.GetDeletedUser(ctx, GetDeletedUserParams{
+			OrganizationID: arg.OrganizationID,
+			ID:             arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.RestoreUser(ctx, RestoreUserParams{
+			OrganizationID: arg.OrganizationID,
+			ID:             arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.Trainings {
+			result.Trainings, err = q.RestoreTrainingsByUser(ctx, RestoreTrainingsByUserParams{
+				OrganizationID: arg.OrganizationID,
+				UserID:         arg.UserID,
+				DeletedAt:      deleted.DeletedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// PurgeDeletedTxParams contains the input parameters of the purge deleted transaction.
+// Audit describes who requested the purge; its entity type and id are filled for every removed row.
+type PurgeDeletedTxParams struct {
+	OrganizationID int64                `json:"organization_id"`
+	Before         time.Time            `json:"before"`
+	Audit          CreateAuditLogParams `json:"audit"`
+}
+
+// PurgeDeletedTxResult is the result of the purge deleted transaction
+type PurgeDeletedTxResult struct {
+	UserIDs             []int64 `json:"user_ids"`
+	TrainingIDs         []int64 `json:"training_ids"`
+	TrainingFeedbackIDs []int64 `json:"training_feedback_ids"`
+	TrainingSeriesIDs   []int64 `json:"training_series_ids"`
+}
+
+// PurgeDeletedTx permanently removes the users and trainings of an organization soft-deleted before
+// the given time, together with the trainings of those users and all the dependent feedbacks, and
+// records the removal of each of them
+func (store *SQLStore) PurgeDeletedTx(ctx context.Context, arg PurgeDeletedTxParams) (PurgeDeletedTxResult, error) {
+	var result PurgeDeletedTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		organizationID := arg.OrganizationID
+		deletedAt := null.NewTime(arg.Before, true)
+
+		result.TrainingFeedbackIDs, err = q.PurgeTrainingFeedbacks(ctx, PurgeTrainingFeedbacksParams{OrganizationID: organizationID, DeletedAt: deletedAt})
+		if err != nil {
+			return err
+		}
+
+		result.TrainingIDs, err = q.PurgeTrainings(ctx, PurgeTrainingsParams{OrganizationID: organizationID, DeletedAt: deletedAt})
+		if err != nil {
+			return err
+		}
+
+		result.TrainingSeriesIDs, err = q.PurgeTrainingSeries(ctx, PurgeTrainingSeriesParams{OrganizationID: organizationID, DeletedAt: deletedAt})
+		if err != nil {
+			return err
+		}
+
+		result.UserIDs, err = q.PurgeUsers(ctx, PurgeUsersParams{OrganizationID: organizationID, DeletedAt: deletedAt})
+		if err != nil {
+			return err
+		}
+
+		// the entity types are the ones the API records
+		audit := arg.Audit
+		audit.OrganizationID = organizationID
+		purged := []struct {
+			entityType string
+			ids        []int64
+		}{
+			{"training_feedback", result.TrainingFeedbackIDs},
+			{"training", result.TrainingIDs},
+			{"training_series", result.TrainingSeriesIDs},
+			{"user", result.UserIDs},
+		}
+		for _, p := range purged {
+			audit.EntityType = p.entityType
+			for _, id := range p.ids {
+				audit.EntityID = id
+				audit.Before = json.RawMessage("null")
+				audit.After = json.RawMessage("null")
+				if _, err := q.CreateAuditLog(ctx, audit); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// EraseUserTxParams contains the input parameters of the erase user transaction.
+// Audit describes who requested the erasure; its entity id and snapshots are filled by the transaction.
+type EraseUserTxParams struct {
+	OrganizationID int64                `json:"organization_id"`
+	UserID         int64                `json:"user_id"`
+	Audit          CreateAuditLogParams `json:"audit"`
+}
+
+// EraseUserTx anonymizes the personal data of a user, deletes its wellness log, injuries, zone models, test results,
+// races, availability and API tokens, clears the pain reported with the feedbacks, removes it and everything of theirs
+// from the audit log snapshots and records the erasure. The user's trainings, feedbacks and exercise logs are kept for
+// aggregated stats, what is left of them is training data that no longer tells whom it belongs to.
+func (store *SQLStore) EraseUserTx(ctx context.Context, arg EraseUserTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = q.EraseUser(ctx, EraseUserParams{
+			OrganizationID: arg.OrganizationID,
+			ID:             arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllWellnessByUser(ctx, DeleteAllWellnessByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllInjuriesByUser(ctx, DeleteAllInjuriesByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllZoneModelsByUser(ctx, DeleteAllZoneModelsByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllTestResultsByUser(ctx, DeleteAllTestResultsByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllRacesByUser(ctx, DeleteAllRacesByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.EraseUserTrainingFeedbacksPain(ctx, EraseUserTrainingFeedbacksPainParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllAvailabilityByUser(ctx, DeleteAllAvailabilityByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllBlackoutsByUser(ctx, DeleteAllBlackoutsByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteAllApiTokensByUser(ctx, DeleteAllApiTokensByUserParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the snapshots of the rows of the user go too, the deleted health data being in them
+		err = q.RedactUserAuditLogs(ctx, RedactUserAuditLogsParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		audit := arg.Audit
+		audit.OrganizationID = arg.OrganizationID
+		audit.EntityID = arg.UserID
+		audit.Before = json.RawMessage("null")
+		audit.After, err = json.Marshal(user)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAuditLog(ctx, audit)
+		return err
+	})
+
+	return user, err
+}
+
+// CreateTrainingsTx creates all the trainings or none of them
+func (store *SQLStore) CreateTrainingsTx(ctx context.Context, args []CreateTrainingParams) ([]Training, error) {
+	trainings := make([]Training, 0, len(args))
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, arg := range args {
+			training, err := q.CreateTraining(ctx, arg)
+			if err != nil {
+				return err
+			}
+			trainings = append(trainings, training)
+		}
+
+		return nil
+	})
+
+	return trainings, err
+}
+
+// CreateTrainingTxParams contains the input parameters of the create training transaction
+type CreateTrainingTxParams struct {
+	Training CreateTrainingParams `json:"training"`
+	// Legs of a multisport training, their organization and training are set by the transaction
+	Legs []CreateTrainingLegParams `json:"legs"`
+}
+
+// TrainingTxResult is the result of the training transactions
+type TrainingTxResult struct {
+	Training Training      `json:"training"`
+	Legs     []TrainingLeg `json:"legs"`
+}
+
+// CreateTrainingTx creates a training along with its legs
+func (store *SQLStore) CreateTrainingTx(ctx context.Context, arg CreateTrainingTxParams) (TrainingTxResult, error) {
+	result := TrainingTxResult{Legs: []TrainingLeg{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Training, err = q.CreateTraining(ctx, arg.Training)
+		if err != nil {
+			return err
+		}
+
+		result.Legs, err = createTrainingLegs(ctx, q, result.Training, arg.Legs)
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateTrainingTxParams contains the input parameters of the update training transaction
+type UpdateTrainingTxParams struct {
+	Training UpdateTrainingParams `json:"training"`
+	// Legs replace the ones of the training, their organization and training are set by the transaction
+	Legs []CreateTrainingLegParams `json:"legs"`
+}
+
+// UpdateTrainingTx updates a training and replaces its legs, the training has no legs anymore when none are given
+func (store *SQLStore) UpdateTrainingTx(ctx context.Context, arg UpdateTrainingTxParams) (TrainingTxResult, error) {
+	result := TrainingTxResult{Legs: []TrainingLeg{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Training, err = q.UpdateTraining(ctx, arg.Training)
+		if err == sql.ErrNoRows {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTrainingLegs(ctx, DeleteTrainingLegsParams{
+			OrganizationID: result.Training.OrganizationID,
+			TrainingID:     result.Training.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Legs, err = createTrainingLegs(ctx, q, result.Training, arg.Legs)
+		return err
+	})
+
+	return result, err
+}
+
+// createTrainingLegs adds the legs to a training
+func createTrainingLegs(ctx context.Context, q *Queries, training Training, args []CreateTrainingLegParams) ([]TrainingLeg, error) {
+	legs := make([]TrainingLeg, 0, len(args))
+	for _, arg := range args {
+		arg.OrganizationID = training.OrganizationID
+		arg.TrainingID = training.ID
+		leg, err := q.CreateTrainingLeg(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		legs = append(legs, leg)
+	}
+
+	return legs, nil
+}
+
+// UpdateTrainingLegsFeedbackTxParams contains the input parameters of the update training legs feedback transaction
+type UpdateTrainingLegsFeedbackTxParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	TrainingID     int64 `json:"training_id"`
+	// Legs are the actual data of the legs, their organization and training are set by the transaction
+	Legs []UpdateTrainingLegFeedbackParams `json:"legs"`
+}
+
+// UpdateTrainingLegsFeedbackTx replaces the actual data of the legs of a training, the legs not given have none.
+// The legs are part of the training, so its version changes along with them.
+func (store *SQLStore) UpdateTrainingLegsFeedbackTx(ctx context.Context, arg UpdateTrainingLegsFeedbackTxParams) ([]TrainingLeg, error) {
+	legs := make([]TrainingLeg, 0, len(arg.Legs))
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.TouchTraining(ctx, TouchTrainingParams{
+			OrganizationID: arg.OrganizationID,
+			ID:             arg.TrainingID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.ClearTrainingLegsFeedback(ctx, ClearTrainingLegsFeedbackParams{
+			OrganizationID: arg.OrganizationID,
+			TrainingID:     arg.TrainingID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, l := range arg.Legs {
+			l.OrganizationID = arg.OrganizationID
+			l.TrainingID = arg.TrainingID
+			leg, err := q.UpdateTrainingLegFeedback(ctx, l)
+			if err != nil {
+				return err
+			}
+			legs = append(legs, leg)
+		}
+
+		return nil
+	})
+
+	return legs, err
+}
+
+// CreateTrainingSeriesTxParams contains the input parameters of the create training series transaction
+type CreateTrainingSeriesTxParams struct {
+	Series CreateTrainingSeriesParams `json:"series"`
+	Dates  []time.Time                `json:"dates"`
+	Status TrainingStatus             `json:"status"`
+}
+
+// TrainingSeriesTxResult is the result of the training series transactions
+type TrainingSeriesTxResult struct {
+	Series    TrainingSeries `json:"series"`
+	Trainings []Training     `json:"trainings"`
+}
+
+// CreateTrainingSeriesTx creates a training series and one training for each of its dates
+func (store *SQLStore) CreateTrainingSeriesTx(ctx context.Context, arg CreateTrainingSeriesTxParams) (TrainingSeriesTxResult, error) {
+	result := TrainingSeriesTxResult{Trainings: make([]Training, 0, len(arg.Dates))}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Series, err = q.CreateTrainingSeries(ctx, arg.Series)
+		if err != nil {
+			return err
+		}
+
+		for _, date := range arg.Dates {
+			training, err := q.CreateTraining(ctx, CreateTrainingParams{
+				OrganizationID: result.Series.OrganizationID,
+				UserID:         result.Series.UserID,
+				Date:           date,
+				Sport:          result.Series.Sport,
+				Type:           result.Series.Type,
+				Intensity:      result.Series.Intensity,
+				Details:        result.Series.Details,
+				Status:         arg.Status,
+				SeriesID:       null.NewInt64(result.Series.ID, true),
+			})
+			if err != nil {
+				return err
+			}
+			result.Trainings = append(result.Trainings, training)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// UpdateTrainingSeriesTxParams contains the input parameters of the update training series transaction
+type UpdateTrainingSeriesTxParams struct {
+	// Series is the new state of the existing series
+	Series UpdateTrainingSeriesParams `json:"series"`
+	// Split, when set, creates a new series that takes over the updated trainings
+	Split *CreateTrainingSeriesParams `json:"split"`
+	// Trainings updates the trainings of the series, its NewSeriesID is set by the transaction
+	Trainings UpdateSeriesTrainingsParams `json:"trainings"`
+	// Training is the new state of the training the change was made from, at the version it was edited from
+	Training UpdateTrainingParams `json:"training"`
+}
+
+// UpdateTrainingSeriesTx updates several trainings of a series at once, splitting the series if needed
+func (store *SQLStore) UpdateTrainingSeriesTx(ctx context.Context, arg UpdateTrainingSeriesTxParams) (TrainingSeriesTxResult, error) {
+	var result TrainingSeriesTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Series, err = q.UpdateTrainingSeries(ctx, arg.Series)
+		if err != nil {
+			return err
+		}
+
+		trainings := arg.Trainings
+		trainings.NewSeriesID = null.NewInt64(result.Series.ID, true)
+		if arg.Split != nil {
+			result.Series, err = q.CreateTrainingSeries(ctx, *arg.Split)
+			if err != nil {
+				return err
+			}
+			trainings.NewSeriesID.SetValid(result.Series.ID)
+		}
+
+		result.Trainings, err = q.UpdateSeriesTrainings(ctx, trainings)
+		if err != nil {
+			return err
+		}
+
+		// the training was just updated along with the series, it must have been at the version it was
+		// edited from
+		for _, t := range result.Trainings {
+			if t.ID == arg.Training.ID {
+				if t.Version != arg.Training.Version+1 {
+					return ErrVersionConflict
+				}
+				arg.Training.Version = t.Version
+			}
+		}
+
+		training, err := q.UpdateTraining(ctx, arg.Training)
+		if err == sql.ErrNoRows {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		for i := range result.Trainings {
+			if result.Trainings[i].ID == training.ID {
+				result.Trainings[i] = training
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// DeleteTrainingSeriesTxParams contains the input parameters of the delete training series transaction
+type DeleteTrainingSeriesTxParams struct {
+	// Series is the new state of the series, ignored when the whole series is deleted
+	Series UpdateTrainingSeriesParams `json:"series"`
+	// TrainingID deletes a single training of the series, otherwise every training from FromDate is deleted
+	TrainingID int64     `json:"training_id"`
+	FromDate   time.Time `json:"from_date"`
+	// DeleteSeries deletes the series itself
+	DeleteSeries bool `json:"delete_series"`
+}
+
+// DeleteTrainingSeriesTxResult is the result of the delete training series transaction
+type DeleteTrainingSeriesTxResult struct {
+	Deleted []Training `json:"deleted"`
+	// Detached are the completed trainings of a deleted series, they are kept as trainings on their own
+	Detached []Training `json:"detached"`
+}
+
+// DeleteTrainingSeriesTx deletes one or more trainings of a series, updating the series accordingly.
+// Completed trainings are history and are only deleted one by one, when the whole series is
+// deleted they are detached from it.
+func (store *SQLStore) DeleteTrainingSeriesTx(ctx context.Context, arg DeleteTrainingSeriesTxParams) (DeleteTrainingSeriesTxResult, error) {
+	result := DeleteTrainingSeriesTxResult{Deleted: []Training{}, Detached: []Training{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		seriesID := null.NewInt64(arg.Series.ID, true)
+
+		if arg.TrainingID != 0 {
+			training, err := q.GetTraining(ctx, GetTrainingParams{
+				OrganizationID: arg.Series.OrganizationID,
+				ID:             arg.TrainingID,
+			})
+			if err != nil {
+				return err
+			}
+			err = q.DeleteTraining(ctx, DeleteTrainingParams{
+				OrganizationID: training.OrganizationID,
+				ID:             training.ID,
+			})
+			if err != nil {
+				return err
+			}
+			result.Deleted = append(result.Deleted, training)
+		} else {
+			result.Deleted, err = q.DeleteSeriesTrainings(ctx, DeleteSeriesTrainingsParams{
+				OrganizationID: arg.Series.OrganizationID,
+				SeriesID:       seriesID,
+				Date:           arg.FromDate,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if arg.DeleteSeries {
+			result.Detached, err = q.DetachSeriesTrainings(ctx, DetachSeriesTrainingsParams{
+				OrganizationID: arg.Series.OrganizationID,
+				SeriesID:       seriesID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return q.DeleteTrainingSeries(ctx, DeleteTrainingSeriesParams{
+				OrganizationID: arg.Series.OrganizationID,
+				ID:             arg.Series.ID,
+			})
+		}
+
+		_, err = q.UpdateTrainingSeries(ctx, arg.Series)
+		return err
+	})
+
+	return result, err
+}
+
+// TrainingConflict reports a training copied or moved to a day that already has trainings.
+// TrainingID is the training that was copied or moved.
+type TrainingConflict struct {
+	UserID      int64     `json:"user_id"`
+	Date        time.Time `json:"date"`
+	TrainingID  int64     `json:"training_id"`
+	ExistingIDs []int64   `json:"existing_ids"`
+}
+
+// BulkTrainingsTxResult is the result of the bulk trainings transactions
+type BulkTrainingsTxResult struct {
+	Trainings []Training         `json:"trainings"`
+	Conflicts []TrainingConflict `json:"conflicts"`
+}
+
+// CopyTrainingsTxParams contains the input parameters of the copy trainings transaction
+type CopyTrainingsTxParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	UserID         int64     `json:"user_id"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	// Days is the number of days between the source and the target periods
+	Days int `json:"days"`
+	// TargetUserIDs receive the copies, the source user if empty
+	TargetUserIDs  []int64 `json:"target_user_ids"`
+	FailOnConflict bool    `json:"fail_on_conflict"`
+}
+
+// CopyTrainingsTx copies the trainings of a user in a period to another period,
+// for the same user or for other users. The copies have the status new.
+func (store *SQLStore) CopyTrainingsTx(ctx context.Context, arg CopyTrainingsTxParams) (BulkTrainingsTxResult, error) {
+	result := BulkTrainingsTxResult{Trainings: []Training{}, Conflicts: []TrainingConflict{}}
+
+	targets := arg.TargetUserIDs
+	if len(targets) == 0 {
+		targets = []int64{arg.UserID}
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		sources, err := q.ListTrainingsByUserInPeriod(ctx, ListTrainingsByUserInPeriodParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+			Date:           arg.StartDate,
+			Date_2:         arg.EndDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		legs, err := sourceLegs(ctx, q, arg.OrganizationID, sources)
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range targets {
+			existing, err := existingTrainings(ctx, q, arg.OrganizationID, userID, arg.StartDate.AddDate(0, 0, arg.Days), arg.EndDate.AddDate(0, 0, arg.Days), nil)
+			if err != nil {
+				return err
+			}
+
+			for _, source := range sources {
+				training, err := q.CreateTraining(ctx, CreateTrainingParams{
+					OrganizationID:  arg.OrganizationID,
+					UserID:          userID,
+					Date:            source.Date.AddDate(0, 0, arg.Days),
+					Sport:           source.Sport,
+					Type:            source.Type,
+					Intensity:       source.Intensity,
+					Details:         source.Details,
+					Status:          TrainingStatusNew,
+					PlannedDuration: source.PlannedDuration,
+					Attributes:      source.Attributes,
+				})
+				if err != nil {
+					return err
+				}
+				if _, err = createTrainingLegs(ctx, q, training, legs[source.ID]); err != nil {
+					return err
+				}
+				result.Trainings = append(result.Trainings, training)
+
+				if ids := existing[training.Date.Format("2006-01-02")]; len(ids) > 0 {
+					result.Conflicts = append(result.Conflicts, TrainingConflict{
+						UserID:      userID,
+						Date:        training.Date,
+						TrainingID:  source.ID,
+						ExistingIDs: ids,
+					})
+				}
+			}
+		}
+
+		if arg.FailOnConflict && len(result.Conflicts) > 0 {
+			return ErrTrainingConflict
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// ShiftTrainingsTxParams contains the input parameters of the shift trainings transaction
+type ShiftTrainingsTxParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	UserID         int64     `json:"user_id"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	Days           int       `json:"days"`
+	FailOnConflict bool      `json:"fail_on_conflict"`
+}
+
+// ShiftTrainingsTx moves the trainings of a user in a period by a number of days.
+// The completed trainings are history and stay where they are.
+func (store *SQLStore) ShiftTrainingsTx(ctx context.Context, arg ShiftTrainingsTxParams) (BulkTrainingsTxResult, error) {
+	result := BulkTrainingsTxResult{Trainings: []Training{}, Conflicts: []TrainingConflict{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		trainings, err := q.ListTrainingsByUserInPeriod(ctx, ListTrainingsByUserInPeriodParams{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+			Date:           arg.StartDate,
+			Date_2:         arg.EndDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		var sources []Training
+		moved := map[int64]bool{}
+		for _, t := range trainings {
+			if t.Status == TrainingStatusDone || t.Status == TrainingStatusDoneFeedback {
+				continue
+			}
+			sources = append(sources, t)
+			moved[t.ID] = true
+		}
+		existing, err := existingTrainings(ctx, q, arg.OrganizationID, arg.UserID, arg.StartDate.AddDate(0, 0, arg.Days), arg.EndDate.AddDate(0, 0, arg.Days), moved)
+		if err != nil {
+			return err
+		}
+
+		for _, source := range sources {
+			training, err := q.MoveTraining(ctx, MoveTrainingParams{
+				OrganizationID: arg.OrganizationID,
+				ID:             source.ID,
+				Date:           source.Date.AddDate(0, 0, arg.Days),
+				Version:        source.Version,
+			})
+			if err == sql.ErrNoRows {
+				return ErrVersionConflict
+			}
+			if err != nil {
+				return err
+			}
+			result.Trainings = append(result.Trainings, training)
+
+			if ids := existing[training.Date.Format("2006-01-02")]; len(ids) > 0 {
+				result.Conflicts = append(result.Conflicts, TrainingConflict{
+					UserID:      arg.UserID,
+					Date:        training.Date,
+					TrainingID:  training.ID,
+					ExistingIDs: ids,
+				})
+			}
+		}
+
+		if arg.FailOnConflict && len(result.Conflicts) > 0 {
+			return ErrTrainingConflict
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// sourceLegs returns the legs of the multisport trainings among the sources by training, to be created for
+// their copies
+func sourceLegs(ctx context.Context, q *Queries, organizationID int64, sources []Training) (map[int64][]CreateTrainingLegParams, error) {
+	ids := make([]int64, len(sources))
+	for i, source := range sources {
+		ids[i] = source.ID
+	}
+	rows, err := q.ListTrainingLegsByTrainings(ctx, ListTrainingLegsByTrainingsParams{
+		OrganizationID: organizationID,
+		TrainingIds:    ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make(map[int64][]CreateTrainingLegParams)
+	for _, l := range rows {
+		legs[l.TrainingID] = append(legs[l.TrainingID], CreateTrainingLegParams{
+			Position:        l.Position,
+			Sport:           l.Sport,
+			Details:         l.Details,
+			PlannedDuration: l.PlannedDuration,
+			Transition:      l.Transition,
+			Attributes:      l.Attributes,
+		})
+	}
+	return legs, nil
+}
+
+// existingTrainings returns the ids of the trainings of a user in a period by day, ignoring the skipped ones
+func existingTrainings(ctx context.Context, q *Queries, organizationID, userID int64, start, end time.Time, skip map[int64]bool) (map[string][]int64, error) {
+	trainings, err := q.ListTrainingsByUserInPeriod(ctx, ListTrainingsByUserInPeriodParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Date:           start,
+		Date_2:         end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string][]int64{}
+	for _, t := range trainings {
+		if skip[t.ID] {
+			continue
+		}
+		day := t.Date.Format("2006-01-02")
+		existing[day] = append(existing[day], t.ID)
+	}
+	return existing, nil
+}
+
+// CreateGroupTrainingTxParams contains the input parameters of the create group training transaction
+type CreateGroupTrainingTxParams struct {
+	GroupTraining CreateGroupTrainingParams `json:"group_training"`
+	Status        TrainingStatus            `json:"status"`
+}
+
+// GroupTrainingTxResult is the result of the group training transactions.
+// Trainings are the member trainings created, updated or deleted along with the group training.
+type GroupTrainingTxResult struct {
+	GroupTraining GroupTraining `json:"group_training"`
+	Trainings     []Training    `json:"trainings"`
+}
+
+// CreateGroupTrainingTx creates a group training and one linked training for each member of the group
+func (store *SQLStore) CreateGroupTrainingTx(ctx context.Context, arg CreateGroupTrainingTxParams) (GroupTrainingTxResult, error) {
+	result := GroupTrainingTxResult{Trainings: []Training{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.GroupTraining, err = q.CreateGroupTraining(ctx, arg.GroupTraining)
+		if err != nil {
+			return err
+		}
+
+		members, err := q.ListGroupMembers(ctx, ListGroupMembersParams{
+			OrganizationID: result.GroupTraining.OrganizationID,
+			GroupID:        result.GroupTraining.GroupID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			training, err := q.CreateTraining(ctx, CreateTrainingParams{
+				OrganizationID:  result.GroupTraining.OrganizationID,
+				UserID:          member.ID,
+				Date:            result.GroupTraining.Date,
+				Sport:           result.GroupTraining.Sport,
+				Type:            result.GroupTraining.Type,
+				Intensity:       result.GroupTraining.Intensity,
+				Details:         result.GroupTraining.Details,
+				Status:          arg.Status,
+				GroupTrainingID: null.NewInt64(result.GroupTraining.ID, true),
+				PlannedDuration: result.GroupTraining.PlannedDuration,
+			})
+			if err != nil {
+				return err
+			}
+			result.Trainings = append(result.Trainings, training)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// UpdateGroupTrainingTxParams contains the input parameters of the update group training transaction
+type UpdateGroupTrainingTxParams struct {
+	GroupTraining UpdateGroupTrainingParams `json:"group_training"`
+	// Propagate applies the change to the member trainings that are not done yet
+	Propagate bool `json:"propagate"`
+}
+
+// UpdateGroupTrainingTx updates a group training and, optionally, the pending trainings of its members
+func (store *SQLStore) UpdateGroupTrainingTx(ctx context.Context, arg UpdateGroupTrainingTxParams) (GroupTrainingTxResult, error) {
+	result := GroupTrainingTxResult{Trainings: []Training{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.GroupTraining, err = q.UpdateGroupTraining(ctx, arg.GroupTraining)
+		if err != nil || !arg.Propagate {
+			return err
+		}
+
+		result.Trainings, err = q.UpdatePendingGroupTrainings(ctx, UpdatePendingGroupTrainingsParams{
+			OrganizationID:  result.GroupTraining.OrganizationID,
+			GroupTrainingID: null.NewInt64(result.GroupTraining.ID, true),
+			Date:            result.GroupTraining.Date,
+			Sport:           result.GroupTraining.Sport,
+			Type:            result.GroupTraining.Type,
+			Intensity:       result.GroupTraining.Intensity,
+			Details:         result.GroupTraining.Details,
+			PlannedDuration: result.GroupTraining.PlannedDuration,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// DeleteGroupTrainingTxParams contains the input parameters of the delete group training transaction
+type DeleteGroupTrainingTxParams struct {
+	OrganizationID  int64 `json:"organization_id"`
+	GroupTrainingID int64 `json:"group_training_id"`
+	// Propagate deletes the member trainings that are not done yet
+	Propagate bool `json:"propagate"`
+}
+
+// DeleteGroupTrainingTx deletes a group training and, optionally, the pending trainings of its members
+func (store *SQLStore) DeleteGroupTrainingTx(ctx context.Context, arg DeleteGroupTrainingTxParams) (GroupTrainingTxResult, error) {
+	result := GroupTrainingTxResult{Trainings: []Training{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.GroupTraining, err = q.GetGroupTraining(ctx, GetGroupTrainingParams{
+			OrganizationID: arg.OrganizationID,
+			ID:             arg.GroupTrainingID,
+		})
+		if err != nil {
+			return err
+		}
+		err = q.DeleteGroupTraining(ctx, DeleteGroupTrainingParams{
+			OrganizationID: arg.OrganizationID,
+			ID:             arg.GroupTrainingID,
+		})
+		if err != nil {
+			return err
+		}
+		if !arg.Propagate {
+			return nil
+		}
+
+		result.Trainings, err = q.DeletePendingGroupTrainings(ctx, DeletePendingGroupTrainingsParams{
+			OrganizationID:  arg.OrganizationID,
+			GroupTrainingID: null.NewInt64(arg.GroupTrainingID, true),
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CreateOrganizationTxParams contains the input parameters of the create organization transaction.
+// The organization id of the admin is set by the transaction.
+type CreateOrganizationTxParams struct {
+	Organization CreateOrganizationParams `json:"organization"`
+	Admin        CreateUserParams         `json:"admin"`
+}
+
+// CreateOrganizationTxResult is the result of the create organization transaction
+type CreateOrganizationTxResult struct {
+	Organization Organization `json:"organization"`
+	Admin        User         `json:"admin"`
+}
+
+// CreateOrganizationTx creates an organization together with its first admin
+func (store *SQLStore) CreateOrganizationTx(ctx context.Context, arg CreateOrganizationTxParams) (CreateOrganizationTxResult, error) {
+	var result CreateOrganizationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Organization, err = q.CreateOrganization(ctx, arg.Organization)
+		if err != nil {
+			return err
+		}
+
+		admin := arg.Admin
+		admin.OrganizationID = result.Organization.ID
+		admin.Type = UserTypeAdmin
+		admin.Active = true
+		result.Admin, err = q.CreateUser(ctx, admin)
+		return err
+	})
+
+	return result, err
+}
+
+// CreateTestResultTxParams contains the input parameters of the create test result transaction.
+// ZoneModels are the zone models derived from the result, if the athlete zones must be updated.
+type CreateTestResultTxParams struct {
+	TestResult CreateTestResultParams  `json:"test_result"`
+	ZoneModels []CreateZoneModelParams `json:"zone_models"`
+}
+
+// CreateTestResultTxResult is the result of the create test result transaction
+type CreateTestResultTxResult struct {
+	TestResult TestResult  `json:"test_result"`
+	ZoneModels []ZoneModel `json:"zone_models"`
+}
+
+// CreateTestResultTx records the result of a fitness test along with the zone models derived from it
+func (store *SQLStore) CreateTestResultTx(ctx context.Context, arg CreateTestResultTxParams) (CreateTestResultTxResult, error) {
+	result := CreateTestResultTxResult{ZoneModels: []ZoneModel{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.TestResult, err = q.CreateTestResult(ctx, arg.TestResult)
+		if err != nil {
+			return err
+		}
+
+		for _, zm := range arg.ZoneModels {
+			model, err := q.CreateZoneModel(ctx, zm)
+			if err != nil {
+				return err
+			}
+			result.ZoneModels = append(result.ZoneModels, model)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// PauseTrainingsTxParams contains the input parameters of the pause trainings transaction
+type PauseTrainingsTxParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	InjuryID       int64 `json:"injury_id"`
+	// Days reschedules the planned trainings that many days later, they are cancelled when 0
+	Days int `json:"days"`
+}
+
+// PauseTrainingsTx reschedules or cancels the planned trainings falling inside an injury period.
+// It returns the rescheduled trainings, or the cancelled ones as they were before being deleted.
+func (store *SQLStore) PauseTrainingsTx(ctx context.Context, arg PauseTrainingsTxParams) ([]Training, error) {
+	result := []Training{}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		trainings, err := q.ListInjuryTrainings(ctx, ListInjuryTrainingsParams{
+			OrganizationID: arg.OrganizationID,
+			ID:             arg.InjuryID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, training := range trainings {
+			if arg.Days == 0 {
+				err = q.DeleteTraining(ctx, DeleteTrainingParams{
+					OrganizationID: training.OrganizationID,
+					ID:             training.ID,
+				})
+			} else {
+				training, err = q.UpdateTraining(ctx, UpdateTrainingParams{
+					OrganizationID:  training.OrganizationID,
+					ID:              training.ID,
+					Date:            training.Date.AddDate(0, 0, arg.Days),
+					Sport:           training.Sport,
+					Type:            training.Type,
+					Intensity:       training.Intensity,
+					Details:         training.Details,
+					Status:          training.Status,
+					PlannedDuration: training.PlannedDuration,
+					Attributes:      training.Attributes,
+					Version:         training.Version,
+				})
+				if err == sql.ErrNoRows {
+					return ErrVersionConflict
+				}
+			}
+			if err != nil {
+				return err
+			}
+			result = append(result, training)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// SetTrainingExercisesTxParams contains the input parameters of the set training exercises transaction
+type SetTrainingExercisesTxParams struct {
+	OrganizationID int64                              `json:"organization_id"`
+	TrainingID     int64                              `json:"training_id"`
+	Prescriptions  []CreateExercisePrescriptionParams `json:"prescriptions"`
+}
+
+// SetTrainingExercisesTx replaces the exercises prescribed for a training, the sets already logged
+// are kept but lose the link to their prescription
+func (store *SQLStore) SetTrainingExercisesTx(ctx context.Context, arg SetTrainingExercisesTxParams) ([]ExercisePrescription, error) {
+	result := []ExercisePrescription{}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.DeleteTrainingPrescriptions(ctx, DeleteTrainingPrescriptionsParams{
+			OrganizationID: arg.OrganizationID,
+			TrainingID:     arg.TrainingID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, p := range arg.Prescriptions {
+			prescription, err := q.CreateExercisePrescription(ctx, p)
+			if err != nil {
+				return err
+			}
+			result = append(result, prescription)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
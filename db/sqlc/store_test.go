@@ -0,0 +1,480 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) TestRestoreUserTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	// a training deleted before the user stays in the trash
+	kept := s.createTraining(u.ID)
+	err := s.q.DeleteTraining(context.Background(), DeleteTrainingParams{OrganizationID: s.org.ID, ID: kept.ID})
+	s.Require().NoError(err)
+	err = s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().NoError(err)
+	for i := 0; i < 3; i++ {
+		t := s.createTraining(u.ID)
+		err := s.q.DeleteTraining(context.Background(), DeleteTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
+		s.Require().NoError(err)
+	}
+
+	result, err := s.store.RestoreUserTx(context.Background(), RestoreUserTxParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		Trainings:      true,
+	})
+	s.Require().NoError(err)
+	s.Equal(u.ID, result.User.ID)
+	s.Len(result.Trainings, 3)
+
+	trainings, err := s.q.ListTrainingsByUser(context.Background(), ListTrainingsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Len(trainings, 3)
+	for _, t := range trainings {
+		s.NotEqual(kept.ID, t.ID)
+	}
+}
+
+func (s *DbTestSuite) TestPurgeDeletedTx() {
+	u1 := s.createUser(UserTypeAthlete, true)
+	t1 := s.createTraining(u1.ID)
+	s.createTrainingFeedback(t1.ID, 10)
+	err := s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: u1.ID})
+	s.Require().NoError(err)
+
+	u2 := s.createUser(UserTypeAthlete, true)
+	t2 := s.createTraining(u2.ID)
+	s.createTrainingFeedback(t2.ID, 12)
+	t3 := s.createTraining(u2.ID)
+	err = s.q.DeleteTraining(context.Background(), DeleteTrainingParams{OrganizationID: s.org.ID, ID: t2.ID})
+	s.Require().NoError(err)
+
+	// nothing is old enough yet
+	arg := PurgeDeletedTxParams{
+		OrganizationID: s.org.ID,
+		Before:         time.Now().Add(-time.Hour),
+		Audit:          CreateAuditLogParams{Action: "purge", RequestID: "test"},
+	}
+	result, err := s.store.PurgeDeletedTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.NotContains(result.UserIDs, u1.ID)
+	s.NotContains(result.TrainingIDs, t2.ID)
+
+	arg.Before = time.Now().Add(time.Hour)
+	result, err = s.store.PurgeDeletedTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Contains(result.UserIDs, u1.ID)
+	s.Contains(result.TrainingIDs, t1.ID)
+	s.Contains(result.TrainingIDs, t2.ID)
+	s.NotContains(result.TrainingIDs, t3.ID)
+	s.GreaterOrEqual(len(result.TrainingFeedbackIDs), 2)
+
+	_, err = s.q.GetDeletedUser(context.Background(), GetDeletedUserParams{OrganizationID: s.org.ID, ID: u1.ID})
+	s.Require().Error(err)
+	_, err = s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: t3.ID})
+	s.Require().NoError(err)
+
+	// the removal is recorded along with it
+	logs, err := s.q.ListAuditLogs(context.Background(), ListAuditLogsParams{
+		OrganizationID: s.org.ID,
+		Action:         "purge",
+		EntityType:     "user",
+		EntityID:       u1.ID,
+		StartTime:      time.Now().UTC().AddDate(0, 0, -1),
+		EndTime:        time.Now().UTC().AddDate(0, 0, 1),
+		RowLimit:       10,
+	})
+	s.Require().NoError(err)
+	s.Len(logs, 1)
+	s.Equal("test", logs[0].RequestID)
+}
+
+func (s *DbTestSuite) TestEraseUserTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	t := s.createTraining(u.ID)
+	feedback := s.createTrainingFeedback(t.ID, 14)
+	checkIn := s.createWellness(u.ID, t.Date)
+	injury := s.createInjury(u.ID, t.Date, null.Time{})
+	window := s.createAvailability(u.ID, time.Monday)
+	blackout := s.createBlackout(u.ID, t.Date, t.Date)
+	s.createZoneModel(u.ID, ZoneMethodPace, t.Date)
+	s.createRace(u.ID, t.Date, null.Int32{})
+	_, err := s.q.CreateTestResult(context.Background(), CreateTestResultParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		TrainingID:     t.ID,
+		Protocol:       TestProtocolTt30,
+		AvgHr:          null.NewInt32(172, true),
+	})
+	s.Require().NoError(err)
+	feedback, err = s.q.UpdateTrainingFeedback(context.Background(), UpdateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     t.ID,
+		BorgScale:      feedback.BorgScale,
+		Pain:           null.NewInt32(6, true),
+		InjuryID:       null.NewInt64(injury.ID, true),
+		Version:        feedback.Version,
+	})
+	s.Require().NoError(err)
+	s.createAuditLog(0, "create", "user", u.ID)
+
+	// the audit snapshots of the rows of the user, as the API records them
+	snapshots := []struct {
+		entityType string
+		entityID   int64
+		after      interface{}
+	}{
+		{"training", t.ID, TrainingTxResult{Training: t}},
+		{"training_feedback", feedback.ID, feedback},
+		{"wellness", checkIn.ID, checkIn},
+		{"injury", injury.ID, injury},
+		{"availability", window.ID, window},
+		{"blackout", blackout.ID, blackout},
+	}
+	for _, snapshot := range snapshots {
+		after, err := json.Marshal(snapshot.after)
+		s.Require().NoError(err)
+		_, err = s.q.CreateAuditLog(context.Background(), CreateAuditLogParams{
+			OrganizationID: s.org.ID,
+			Action:         "create",
+			EntityType:     snapshot.entityType,
+			EntityID:       snapshot.entityID,
+			Before:         json.RawMessage("null"),
+			After:          after,
+			RequestID:      "test",
+		})
+		s.Require().NoError(err)
+	}
+	// the ones of other users are kept
+	other := s.createWellness(s.createUser(UserTypeAthlete, true).ID, t.Date)
+	otherAfter, err := json.Marshal(other)
+	s.Require().NoError(err)
+	_, err = s.q.CreateAuditLog(context.Background(), CreateAuditLogParams{
+		OrganizationID: s.org.ID,
+		Action:         "create",
+		EntityType:     "wellness",
+		EntityID:       other.ID,
+		Before:         json.RawMessage("null"),
+		After:          otherAfter,
+		RequestID:      "test",
+	})
+	s.Require().NoError(err)
+
+	user, err := s.store.EraseUserTx(context.Background(), EraseUserTxParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		Audit: CreateAuditLogParams{
+			OrganizationID: s.org.ID,
+			Action:         "erase",
+			EntityType:     "user",
+			RequestID:      "test",
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(u.ID, user.ID)
+	s.NotEqual(u.Name, user.Name)
+	s.NotEqual(u.Email, user.Email)
+	s.False(user.Phone.Valid)
+	s.False(user.Birth.Valid)
+	s.False(user.Gender.Valid)
+	s.Equal("UTC", user.Timezone)
+	s.Equal("en", user.Locale)
+	s.False(user.Active)
+
+	// trainings and feedbacks are kept, without the pain reported
+	trainings, err := s.q.ListTrainingsByUser(context.Background(), ListTrainingsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Len(trainings, 1)
+	feedbacks, err := s.q.ListTrainingFeedbacksByUser(context.Background(), ListTrainingFeedbacksByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Require().Len(feedbacks, 1)
+	s.False(feedbacks[0].Pain.Valid)
+	s.False(feedbacks[0].InjuryID.Valid)
+
+	// the zone models, test results and races are deleted
+	models, err := s.q.ListAllZoneModelsByUser(context.Background(), ListAllZoneModelsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(models)
+	results, err := s.q.ListAllTestResultsByUser(context.Background(), ListAllTestResultsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(results)
+	races, err := s.q.ListAllRacesByUser(context.Background(), ListAllRacesByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(races)
+
+	// the wellness log and injuries are deleted
+	wellness, err := s.q.ListAllWellnessByUser(context.Background(), ListAllWellnessByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(wellness)
+	injuries, err := s.q.ListAllInjuriesByUser(context.Background(), ListAllInjuriesByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(injuries)
+	windows, err := s.q.ListAvailabilityByUser(context.Background(), ListAvailabilityByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(windows)
+	blackouts, err := s.q.ListBlackoutsByUser(context.Background(), ListBlackoutsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Empty(blackouts)
+
+	logs, err := s.q.ListAuditLogs(context.Background(), ListAuditLogsParams{
+		OrganizationID: s.org.ID,
+		EntityType:     "user",
+		EntityID:       u.ID,
+		StartTime:      time.Now().UTC().AddDate(0, 0, -1),
+		EndTime:        time.Now().UTC().AddDate(0, 0, 1),
+		RowLimit:       10,
+	})
+	s.Require().NoError(err)
+	s.Len(logs, 2)
+	s.Equal("erase", logs[0].Action)
+	s.Nil(logs[1].After)
+
+	// no health data is left in the snapshots
+	for _, snapshot := range snapshots {
+		logs, err := s.q.ListAuditLogs(context.Background(), ListAuditLogsParams{
+			OrganizationID: s.org.ID,
+			EntityType:     snapshot.entityType,
+			EntityID:       snapshot.entityID,
+			StartTime:      time.Now().UTC().AddDate(0, 0, -1),
+			EndTime:        time.Now().UTC().AddDate(0, 0, 1),
+			RowLimit:       10,
+		})
+		s.Require().NoError(err)
+		s.Require().Len(logs, 1, snapshot.entityType)
+		s.Nil(logs[0].Before, snapshot.entityType)
+		s.Nil(logs[0].After, snapshot.entityType)
+	}
+	logs, err = s.q.ListAuditLogs(context.Background(), ListAuditLogsParams{
+		OrganizationID: s.org.ID,
+		EntityType:     "wellness",
+		EntityID:       other.ID,
+		StartTime:      time.Now().UTC().AddDate(0, 0, -1),
+		EndTime:        time.Now().UTC().AddDate(0, 0, 1),
+		RowLimit:       10,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(logs, 1)
+	s.JSONEq(string(otherAfter), string(logs[0].After))
+}
+
+func (s *DbTestSuite) TestAuditTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	audit := CreateAuditLogParams{OrganizationID: s.org.ID, RequestID: "audit-tx"}
+
+	var training Training
+	err := s.store.AuditTx(context.Background(), audit, func(store Store) ([]AuditEntry, error) {
+		result, err := store.CreateTrainingTx(context.Background(), CreateTrainingTxParams{Training: CreateTrainingParams{
+			OrganizationID: s.org.ID,
+			UserID:         u.ID,
+			Date:           time.Now().UTC(),
+			Sport:          "running",
+			Details:        "audited training",
+			Status:         TrainingStatusNew,
+		}})
+		training = result.Training
+		return []AuditEntry{{Action: "create", EntityType: "training", EntityID: training.ID, After: training}}, err
+	})
+	s.Require().NoError(err)
+
+	listArg := ListAuditLogsParams{
+		OrganizationID: s.org.ID,
+		EntityType:     "training",
+		EntityID:       training.ID,
+		StartTime:      time.Now().UTC().AddDate(0, 0, -1),
+		EndTime:        time.Now().UTC().AddDate(0, 0, 1),
+		RowLimit:       10,
+	}
+	logs, err := s.q.ListAuditLogs(context.Background(), listArg)
+	s.Require().NoError(err)
+	s.Require().Len(logs, 1)
+	s.Equal("audit-tx", logs[0].RequestID)
+	s.JSONEq("null", string(logs[0].Before))
+
+	// an entry that can't be recorded rolls back the change
+	err = s.store.AuditTx(context.Background(), audit, func(store Store) ([]AuditEntry, error) {
+		err := store.DeleteTraining(context.Background(), DeleteTrainingParams{OrganizationID: s.org.ID, ID: training.ID})
+		return []AuditEntry{{Action: "delete", EntityType: "training", EntityID: training.ID, Before: make(chan int)}}, err
+	})
+	s.Require().Error(err)
+
+	_, err = s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: training.ID})
+	s.Require().NoError(err)
+	logs, err = s.q.ListAuditLogs(context.Background(), listArg)
+	s.Require().NoError(err)
+	s.Len(logs, 1)
+}
+
+func (s *DbTestSuite) TestCreateTrainingsTx() {
+	u := s.createUser(UserTypeAthlete, true)
+
+	args := []CreateTrainingParams{}
+	for i := 0; i < 3; i++ {
+		args = append(args, CreateTrainingParams{
+			OrganizationID: s.org.ID,
+			UserID:         u.ID,
+			Date:           time.Now().UTC().AddDate(0, 0, i),
+			Sport:          "running",
+			Details:        "imported training",
+			Status:         TrainingStatusNew,
+		})
+	}
+
+	trainings, err := s.store.CreateTrainingsTx(context.Background(), args)
+	s.Require().NoError(err)
+	s.Len(trainings, 3)
+
+	// an invalid row rolls back the whole batch
+	args = append(args, CreateTrainingParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		Date:           time.Now().UTC(),
+		Sport:          "invalid",
+		Details:        "imported training",
+		Status:         TrainingStatusNew,
+	})
+	_, err = s.store.CreateTrainingsTx(context.Background(), args)
+	s.Require().Error(err)
+
+	trainings, err = s.q.ListTrainingsByUser(context.Background(), ListTrainingsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Len(trainings, 3)
+}
+
+func (s *DbTestSuite) TestCopyTrainingsTx() {
+	u1 := s.createUser(UserTypeAthlete, true)
+	u2 := s.createUser(UserTypeAthlete, true)
+
+	start, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+	for i := 0; i < 3; i++ {
+		t, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+			OrganizationID: s.org.ID,
+			UserID:         u1.ID,
+			Date:           start.AddDate(0, 0, i),
+			Sport:          "running",
+			Details:        "week plan",
+			Status:         TrainingStatusDone,
+		})
+		s.Require().NoError(err)
+		s.NotEmpty(t)
+	}
+	// u2 already has a training on the first target day
+	_, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+		OrganizationID: s.org.ID,
+		UserID:         u2.ID,
+		Date:           start.AddDate(0, 0, 7),
+		Sport:          "cycling",
+		Details:        "existing",
+		Status:         TrainingStatusNew,
+	})
+	s.Require().NoError(err)
+
+	arg := CopyTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		UserID:         u1.ID,
+		StartDate:      start,
+		EndDate:        start.AddDate(0, 0, 6),
+		Days:           7,
+		TargetUserIDs:  []int64{u1.ID, u2.ID},
+		FailOnConflict: true,
+	}
+
+	result, err := s.store.CopyTrainingsTx(context.Background(), arg)
+	s.Require().ErrorIs(err, ErrTrainingConflict)
+	s.Len(result.Conflicts, 1)
+	s.Equal(u2.ID, result.Conflicts[0].UserID)
+
+	trainings, err := s.q.ListTrainingsByUser(context.Background(), ListTrainingsByUserParams{OrganizationID: s.org.ID, UserID: u1.ID})
+	s.Require().NoError(err)
+	s.Len(trainings, 3)
+
+	arg.FailOnConflict = false
+	result, err = s.store.CopyTrainingsTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Len(result.Trainings, 6)
+	s.Len(result.Conflicts, 1)
+	for _, t := range result.Trainings {
+		s.Equal(TrainingStatusNew, t.Status)
+	}
+}
+
+func (s *DbTestSuite) TestShiftTrainingsTx() {
+	u := s.createUser(UserTypeAthlete, true)
+
+	start, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+	for i := 0; i < 4; i++ {
+		_, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+			OrganizationID: s.org.ID,
+			UserID:         u.ID,
+			Date:           start.AddDate(0, 0, i),
+			Sport:          "running",
+			Details:        "plan",
+			Status:         TrainingStatusNew,
+		})
+		s.Require().NoError(err)
+	}
+
+	// moving the last two days by one day lands the third training on the fourth day, which is also moved
+	result, err := s.store.ShiftTrainingsTx(context.Background(), ShiftTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		StartDate:      start.AddDate(0, 0, 2),
+		EndDate:        start.AddDate(0, 0, 3),
+		Days:           1,
+	})
+	s.Require().NoError(err)
+	s.Len(result.Trainings, 2)
+	s.Empty(result.Conflicts)
+
+	// moving the first day by one day conflicts with the second training
+	result, err = s.store.ShiftTrainingsTx(context.Background(), ShiftTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		StartDate:      start,
+		EndDate:        start,
+		Days:           1,
+	})
+	s.Require().NoError(err)
+	s.Len(result.Trainings, 1)
+	s.Len(result.Conflicts, 1)
+}
+
+func (s *DbTestSuite) TestShiftTrainingsTxKeepsCompleted() {
+	u := s.createUser(UserTypeAthlete, true)
+
+	start, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+	var trainings []Training
+	for i, status := range []TrainingStatus{TrainingStatusDone, TrainingStatusNew} {
+		t, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+			OrganizationID: s.org.ID,
+			UserID:         u.ID,
+			Date:           start.AddDate(0, 0, i),
+			Sport:          "running",
+			Details:        "plan",
+			Status:         status,
+		})
+		s.Require().NoError(err)
+		trainings = append(trainings, t)
+	}
+
+	// the pending training is moved, the completed one stays
+	result, err := s.store.ShiftTrainingsTx(context.Background(), ShiftTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		StartDate:      start,
+		EndDate:        start.AddDate(0, 0, 1),
+		Days:           2,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(result.Trainings, 1)
+	s.Equal(trainings[1].ID, result.Trainings[0].ID)
+	s.Equal(trainings[1].Version+1, result.Trainings[0].Version)
+	s.Equal(start.AddDate(0, 0, 3).Format("2006-01-02"), result.Trainings[0].Date.Format("2006-01-02"))
+
+	completed, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: trainings[0].ID})
+	s.Require().NoError(err)
+	s.Equal(trainings[0], completed)
+}
@@ -0,0 +1,234 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) TestListTrainingSummaries() {
+	u1 := s.createUser(UserTypeAthlete, true)
+	u2 := s.createUser(UserTypeAthlete, true)
+	// a Monday
+	monday, _ := time.Parse("2006-01-02", "2021-06-07")
+
+	createTraining := func(userID int64, date time.Time, sport string, status TrainingStatus) Training {
+		training, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+			OrganizationID:  s.org.ID,
+			UserID:          userID,
+			Date:            date,
+			Sport:           sport,
+			Details:         "summary training",
+			Status:          status,
+			PlannedDuration: null.NewInt32(3600, true),
+		})
+		s.Require().NoError(err)
+		return training
+	}
+
+	done := createTraining(u1.ID, monday, "running", TrainingStatusDoneFeedback)
+	createTraining(u1.ID, monday.AddDate(0, 0, 2), "running", TrainingStatusNew)
+	createTraining(u1.ID, monday.AddDate(0, 0, 6), "cycling", TrainingStatusNew)
+	createTraining(u1.ID, monday.AddDate(0, 0, 7), "running", TrainingStatusNew)
+	createTraining(u2.ID, monday, "swimming", TrainingStatusOverdue)
+
+	_, err := s.q.CreateTrainingFeedback(context.Background(), CreateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     done.ID,
+		BorgScale:      14,
+		Duration:       null.NewInt32(3000, true),
+		Distance:       null.NewInt32(9000, true),
+	})
+	s.Require().NoError(err)
+
+	rows, err := s.q.ListTrainingSummaries(context.Background(), ListTrainingSummariesParams{
+		OrganizationID: s.org.ID,
+		Period:         "week",
+		Now:            monday.AddDate(0, 0, 3).Add(12 * time.Hour),
+		UserIds:        []int64{u1.ID, u2.ID},
+		StartDate:      monday,
+		EndDate:        monday.AddDate(0, 0, 13),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(rows, 4)
+
+	// the sports are sorted by slug
+	cycling := rows[0]
+	s.Equal("cycling", cycling.Sport)
+	s.Equal(int64(1), cycling.Planned)
+	s.Zero(cycling.Due)
+	s.Zero(cycling.Overdue)
+
+	running := rows[1]
+	s.Equal(u1.ID, running.UserID)
+	s.Equal(monday.Format("2006-01-02"), running.PeriodStart.Format("2006-01-02"))
+	s.Equal("running", running.Sport)
+	s.Equal(int64(2), running.Planned)
+	s.Equal(int64(2), running.Due)
+	s.Equal(int64(1), running.Completed)
+	s.Equal(int64(1), running.Overdue)
+	s.Equal(int64(7200), running.PlannedDuration)
+	s.Equal(int64(3000), running.ActualDuration)
+	s.Equal(int64(9000), running.ActualDistance)
+	s.Equal(int64(14), running.BorgSum)
+	s.Equal(int64(1), running.Feedbacks)
+
+	// the next ISO week
+	s.Equal(monday.AddDate(0, 0, 7).Format("2006-01-02"), rows[2].PeriodStart.Format("2006-01-02"))
+
+	s.Equal(u2.ID, rows[3].UserID)
+	s.Equal(int64(1), rows[3].Overdue)
+	s.Zero(rows[3].Feedbacks)
+}
+
+func (s *DbTestSuite) TestListTrainingSummariesWithLegs() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-09")
+
+	result, err := s.store.CreateTrainingTx(context.Background(), CreateTrainingTxParams{
+		Training: CreateTrainingParams{
+			OrganizationID:  s.org.ID,
+			UserID:          u.ID,
+			Date:            date,
+			Sport:           "multisport",
+			Details:         "brick",
+			Status:          TrainingStatusDoneFeedback,
+			PlannedDuration: null.NewInt32(5700, true),
+		},
+		Legs: []CreateTrainingLegParams{
+			{Position: 1, Sport: "cycling", PlannedDuration: null.NewInt32(3600, true)},
+			{Position: 2, Sport: "running", PlannedDuration: null.NewInt32(1800, true), Transition: null.NewInt32(300, true)},
+		},
+	})
+	s.Require().NoError(err)
+
+	_, err = s.q.CreateTrainingFeedback(context.Background(), CreateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     result.Training.ID,
+		BorgScale:      15,
+		Duration:       null.NewInt32(5500, true),
+	})
+	s.Require().NoError(err)
+	_, err = s.store.UpdateTrainingLegsFeedbackTx(context.Background(), UpdateTrainingLegsFeedbackTxParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     result.Training.ID,
+		Legs: []UpdateTrainingLegFeedbackParams{
+			{Position: 1, Duration: null.NewInt32(3500, true), Distance: null.NewInt32(30000, true)},
+			{Position: 2, Duration: null.NewInt32(1700, true), Distance: null.NewInt32(5000, true)},
+		},
+	})
+	s.Require().NoError(err)
+
+	rows, err := s.q.ListTrainingSummaries(context.Background(), ListTrainingSummariesParams{
+		OrganizationID: s.org.ID,
+		Period:         "week",
+		Now:            date,
+		UserIds:        []int64{u.ID},
+		StartDate:      date,
+		EndDate:        date,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(rows, 3)
+
+	// each leg counts toward the volume of its sport
+	s.Equal("cycling", rows[0].Sport)
+	s.Equal(int64(3600), rows[0].PlannedDuration)
+	s.Equal(int64(3500), rows[0].ActualDuration)
+	s.Equal(int64(30000), rows[0].ActualDistance)
+	s.Equal("running", rows[2].Sport)
+	s.Equal(int64(1800), rows[2].PlannedDuration)
+	s.Equal(int64(1700), rows[2].ActualDuration)
+
+	// while the training is a single multisport session
+	multisport := rows[1]
+	s.Equal("multisport", multisport.Sport)
+	s.Equal(int64(1), multisport.Planned)
+	s.Equal(int64(1), multisport.Completed)
+	s.Equal(int64(15), multisport.BorgSum)
+	s.Equal(int64(1), multisport.Feedbacks)
+	s.Zero(multisport.PlannedDuration)
+	s.Zero(multisport.ActualDuration)
+
+	var planned, due, completed, feedbacks int64
+	for _, row := range rows {
+		planned += row.Planned
+		due += row.Due
+		completed += row.Completed
+		feedbacks += row.Feedbacks
+	}
+	s.Equal(int64(1), planned)
+	s.Equal(int64(1), due)
+	s.Equal(int64(1), completed)
+	s.Equal(int64(1), feedbacks)
+}
+
+func (s *DbTestSuite) TestListTrainingSummariesTimezone() {
+	utc := s.createUser(UserTypeAthlete, true)
+	abroad := s.createUser(UserTypeAthlete, true)
+	abroad, err := s.q.UpdateUser(context.Background(), UpdateUserParams{
+		OrganizationID: s.org.ID,
+		ID:             abroad.ID,
+		Type:           abroad.Type,
+		Name:           abroad.Name,
+		Gender:         abroad.Gender,
+		Email:          abroad.Email,
+		Active:         abroad.Active,
+		Timezone:       "Pacific/Kiritimati",
+		Locale:         abroad.Locale,
+		Units:          abroad.Units,
+		Version:        abroad.Version,
+	})
+	s.Require().NoError(err)
+
+	date, _ := time.Parse("2006-01-02", "2021-06-08")
+	for _, u := range []User{utc, abroad} {
+		_, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+			OrganizationID: s.org.ID,
+			UserID:         u.ID,
+			Date:           date,
+			Sport:          "running",
+			Details:        "timezone training",
+			Status:         TrainingStatusNew,
+		})
+		s.Require().NoError(err)
+	}
+
+	// at noon UTC of the day before it is already the day of the training at UTC+14
+	rows, err := s.q.ListTrainingSummaries(context.Background(), ListTrainingSummariesParams{
+		OrganizationID: s.org.ID,
+		Period:         "week",
+		Now:            date.Add(-12 * time.Hour),
+		UserIds:        []int64{utc.ID, abroad.ID},
+		StartDate:      date,
+		EndDate:        date,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+
+	due := map[int64]int64{}
+	for _, row := range rows {
+		due[row.UserID] = row.Due
+		s.Zero(row.Overdue)
+	}
+	s.Zero(due[utc.ID])
+	s.Equal(int64(1), due[abroad.ID])
+}
+
+func (s *DbTestSuite) TestListActiveAthletes() {
+	athlete := s.createUser(UserTypeAthlete, true)
+	inactive := s.createUser(UserTypeAthlete, false)
+	coach := s.createUser(UserTypeCoach, true)
+
+	users, err := s.q.ListActiveAthletes(context.Background(), s.org.ID)
+	s.Require().NoError(err)
+
+	ids := make(map[int64]bool)
+	for _, u := range users {
+		s.Equal(UserTypeAthlete, u.Type)
+		ids[u.ID] = true
+	}
+	s.True(ids[athlete.ID])
+	s.False(ids[inactive.ID])
+	s.False(ids[coach.ID])
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) TestCreateTestResultTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	training := s.createTraining(u.ID)
+
+	arg := CreateTestResultTxParams{
+		TestResult: CreateTestResultParams{
+			OrganizationID: s.org.ID,
+			UserID:         u.ID,
+			TrainingID:     training.ID,
+			Protocol:       TestProtocolTt30,
+			Distance:       null.NewInt32(7500, true),
+			AvgHr:          null.NewInt32(172, true),
+			ThresholdPace:  null.NewInt32(240, true),
+			Lthr:           null.NewInt32(172, true),
+		},
+		ZoneModels: []CreateZoneModelParams{
+			{
+				OrganizationID: s.org.ID,
+				UserID:         u.ID,
+				Sport:          "running",
+				Method:         ZoneMethodPace,
+				Threshold:      240,
+				EffectiveFrom:  training.Date,
+			},
+		},
+	}
+
+	result, err := s.store.CreateTestResultTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.TestResult.ThresholdPace, result.TestResult.ThresholdPace)
+	s.False(result.TestResult.Ftp.Valid)
+	s.Require().Len(result.ZoneModels, 1)
+	s.Equal(ZoneMethodPace, result.ZoneModels[0].Method)
+
+	// a training holds a single test result
+	_, err = s.store.CreateTestResultTx(context.Background(), CreateTestResultTxParams{TestResult: arg.TestResult})
+	s.Error(err)
+
+	results, err := s.q.ListTestResultsByUser(context.Background(), ListTestResultsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Len(results, 1)
+
+	err = s.q.DeleteTestResult(context.Background(), DeleteTestResultParams{OrganizationID: s.org.ID, TrainingID: training.ID})
+	s.Require().NoError(err)
+
+	_, err = s.q.GetTestResult(context.Background(), GetTestResultParams{OrganizationID: s.org.ID, TrainingID: training.ID})
+	s.Error(err)
+}
@@ -5,29 +5,37 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/emvi/null"
 )
 
 const createTraining = `-- name: CreateTraining :one
-INSERT INTO training (user_id, date, sport, type, intensity, details, status)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at
+INSERT INTO training (organization_id, user_id, date, sport, type, intensity, details, status, series_id,
+                      group_training_id, planned_duration, attributes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
 `
 
 type CreateTrainingParams struct {
-	UserID    int64          `json:"user_id"`
-	Date      time.Time      `json:"date"`
-	Sport     TrainingSport  `json:"sport"`
-	Type      null.String    `json:"type"`
-	Intensity null.String    `json:"intensity"`
-	Details   string         `json:"details"`
-	Status    TrainingStatus `json:"status"`
+	OrganizationID  int64           `json:"organization_id"`
+	UserID          int64           `json:"user_id"`
+	Date            time.Time       `json:"date"`
+	Sport           string          `json:"sport"`
+	Type            null.String     `json:"type"`
+	Intensity       null.String     `json:"intensity"`
+	Details         string          `json:"details"`
+	Status          TrainingStatus  `json:"status"`
+	SeriesID        null.Int64      `json:"series_id"`
+	GroupTrainingID null.Int64      `json:"group_training_id"`
+	PlannedDuration null.Int32      `json:"planned_duration"`
+	Attributes      json.RawMessage `json:"attributes"`
 }
 
 func (q *Queries) CreateTraining(ctx context.Context, arg CreateTrainingParams) (Training, error) {
 	row := q.db.QueryRowContext(ctx, createTraining,
+		arg.OrganizationID,
 		arg.UserID,
 		arg.Date,
 		arg.Sport,
@@ -35,6 +43,10 @@ func (q *Queries) CreateTraining(ctx context.Context, arg CreateTrainingParams)
 		arg.Intensity,
 		arg.Details,
 		arg.Status,
+		arg.SeriesID,
+		arg.GroupTrainingID,
+		arg.PlannedDuration,
+		arg.Attributes,
 	)
 	var i Training
 	err := row.Scan(
@@ -48,31 +60,254 @@ func (q *Queries) CreateTraining(ctx context.Context, arg CreateTrainingParams)
 		&i.Status,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.SeriesID,
+		&i.GroupTrainingID,
+		&i.OrganizationID,
+		&i.PlannedDuration,
+		&i.Attributes,
+		&i.Version,
 	)
 	return i, err
 }
 
+const deletePendingGroupTrainings = `-- name: DeletePendingGroupTrainings :many
+UPDATE training
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND group_training_id = $2
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type DeletePendingGroupTrainingsParams struct {
+	OrganizationID  int64      `json:"organization_id"`
+	GroupTrainingID null.Int64 `json:"group_training_id"`
+}
+
+func (q *Queries) DeletePendingGroupTrainings(ctx context.Context, arg DeletePendingGroupTrainingsParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, deletePendingGroupTrainings, arg.OrganizationID, arg.GroupTrainingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteSeriesTrainings = `-- name: DeleteSeriesTrainings :many
+UPDATE training
+SET deleted_at = now()
+WHERE organization_id = $1
+  AND series_id = $2
+  AND date >= $3
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type DeleteSeriesTrainingsParams struct {
+	OrganizationID int64      `json:"organization_id"`
+	SeriesID       null.Int64 `json:"series_id"`
+	Date           time.Time  `json:"date"`
+}
+
+func (q *Queries) DeleteSeriesTrainings(ctx context.Context, arg DeleteSeriesTrainingsParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, deleteSeriesTrainings, arg.OrganizationID, arg.SeriesID, arg.Date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteTraining = `-- name: DeleteTraining :exec
 UPDATE training
 SET deleted_at = now()
-WHERE id = $1
+WHERE organization_id = $1
+  AND id = $2
 `
 
-func (q *Queries) DeleteTraining(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteTraining, id)
+type DeleteTrainingParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) DeleteTraining(ctx context.Context, arg DeleteTrainingParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTraining, arg.OrganizationID, arg.ID)
 	return err
 }
 
+const detachSeriesTrainings = `-- name: DetachSeriesTrainings :many
+UPDATE training
+SET series_id = NULL,
+    version   = version + 1
+WHERE organization_id = $1
+  AND series_id = $2
+  AND deleted_at IS NULL
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type DetachSeriesTrainingsParams struct {
+	OrganizationID int64      `json:"organization_id"`
+	SeriesID       null.Int64 `json:"series_id"`
+}
+
+func (q *Queries) DetachSeriesTrainings(ctx context.Context, arg DetachSeriesTrainingsParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, detachSeriesTrainings, arg.OrganizationID, arg.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedTraining = `-- name: GetDeletedTraining :one
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+FROM training
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+LIMIT 1
+`
+
+type GetDeletedTrainingParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) GetDeletedTraining(ctx context.Context, arg GetDeletedTrainingParams) (Training, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedTraining, arg.OrganizationID, arg.ID)
+	var i Training
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Date,
+		&i.Sport,
+		&i.Type,
+		&i.Intensity,
+		&i.Details,
+		&i.Status,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.SeriesID,
+		&i.GroupTrainingID,
+		&i.OrganizationID,
+		&i.PlannedDuration,
+		&i.Attributes,
+		&i.Version,
+	)
+	return i, err
+}
+
 const getTraining = `-- name: GetTraining :one
-SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
 FROM training
-WHERE id = $1
+WHERE organization_id = $1
+  AND id = $2
   AND deleted_at IS NULL
 LIMIT 1
 `
 
-func (q *Queries) GetTraining(ctx context.Context, id int64) (Training, error) {
-	row := q.db.QueryRowContext(ctx, getTraining, id)
+type GetTrainingParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) GetTraining(ctx context.Context, arg GetTrainingParams) (Training, error) {
+	row := q.db.QueryRowContext(ctx, getTraining, arg.OrganizationID, arg.ID)
 	var i Training
 	err := row.Scan(
 		&i.ID,
@@ -85,20 +320,31 @@ func (q *Queries) GetTraining(ctx context.Context, id int64) (Training, error) {
 		&i.Status,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.SeriesID,
+		&i.GroupTrainingID,
+		&i.OrganizationID,
+		&i.PlannedDuration,
+		&i.Attributes,
+		&i.Version,
 	)
 	return i, err
 }
 
-const listTrainingsByUser = `-- name: ListTrainingsByUser :many
-SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at
+const listAllTrainingsByUser = `-- name: ListAllTrainingsByUser :many
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
 FROM training
-WHERE user_id = $1
-  AND deleted_at IS NULL
+WHERE organization_id = $1
+  AND user_id = $2
 ORDER BY id
 `
 
-func (q *Queries) ListTrainingsByUser(ctx context.Context, userID int64) ([]Training, error) {
-	rows, err := q.db.QueryContext(ctx, listTrainingsByUser, userID)
+type ListAllTrainingsByUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	UserID         int64 `json:"user_id"`
+}
+
+func (q *Queries) ListAllTrainingsByUser(ctx context.Context, arg ListAllTrainingsByUserParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, listAllTrainingsByUser, arg.OrganizationID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +363,12 @@ func (q *Queries) ListTrainingsByUser(ctx context.Context, userID int64) ([]Trai
 			&i.Status,
 			&i.CreatedAt,
 			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -131,23 +383,682 @@ func (q *Queries) ListTrainingsByUser(ctx context.Context, userID int64) ([]Trai
 	return items, nil
 }
 
-const listTrainingsByUserInPeriod = `-- name: ListTrainingsByUserInPeriod :many
-SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at
+const listDeletedTrainings = `-- name: ListDeletedTrainings :many
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+FROM training
+WHERE organization_id = $1
+  AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListDeletedTrainingsParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
+}
+
+func (q *Queries) ListDeletedTrainings(ctx context.Context, arg ListDeletedTrainingsParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedTrainings, arg.OrganizationID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrainingLoadsByUser = `-- name: ListTrainingLoadsByUser :many
+SELECT t.id,
+       t.date,
+       t.sport,
+       t.intensity,
+       t.status,
+       t.planned_duration,
+       COALESCE(tf.borg_scale, 0)::int AS borg_scale,
+       COALESCE(tf.duration, 0)::int   AS duration,
+       COALESCE(tf.distance, 0)::int   AS distance,
+       COALESCE(tf.avg_power, 0)::int  AS avg_power,
+       COALESCE(tf.avg_hr, 0)::int     AS avg_hr
+FROM training t
+         LEFT JOIN training_feedback tf ON tf.training_id = t.id
+WHERE t.organization_id = $1
+  AND t.user_id = $2
+  AND t.date >= $3
+  AND t.date <= $4
+  AND t.deleted_at IS NULL
+ORDER BY t.date, t.id
+`
+
+type ListTrainingLoadsByUserParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	UserID         int64     `json:"user_id"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+}
+
+type ListTrainingLoadsByUserRow struct {
+	ID              int64          `json:"id"`
+	Date            time.Time      `json:"date"`
+	Sport           string         `json:"sport"`
+	Intensity       null.String    `json:"intensity"`
+	Status          TrainingStatus `json:"status"`
+	PlannedDuration null.Int32     `json:"planned_duration"`
+	BorgScale       int32          `json:"borg_scale"`
+	Duration        int32          `json:"duration"`
+	Distance        int32          `json:"distance"`
+	AvgPower        int32          `json:"avg_power"`
+	AvgHr           int32          `json:"avg_hr"`
+}
+
+func (q *Queries) ListTrainingLoadsByUser(ctx context.Context, arg ListTrainingLoadsByUserParams) ([]ListTrainingLoadsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainingLoadsByUser,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTrainingLoadsByUserRow{}
+	for rows.Next() {
+		var i ListTrainingLoadsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Date,
+			&i.Sport,
+			&i.Intensity,
+			&i.Status,
+			&i.PlannedDuration,
+			&i.BorgScale,
+			&i.Duration,
+			&i.Distance,
+			&i.AvgPower,
+			&i.AvgHr,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrainingsByGroupTraining = `-- name: ListTrainingsByGroupTraining :many
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
 FROM training
-WHERE user_id = $1
-  AND date between $2 AND $3
+WHERE organization_id = $1
+  AND group_training_id = $2
   AND deleted_at IS NULL
-ORDER BY id
+ORDER BY user_id
 `
 
-type ListTrainingsByUserInPeriodParams struct {
-	UserID int64     `json:"user_id"`
-	Date   time.Time `json:"date"`
-	Date_2 time.Time `json:"date_2"`
+type ListTrainingsByGroupTrainingParams struct {
+	OrganizationID  int64      `json:"organization_id"`
+	GroupTrainingID null.Int64 `json:"group_training_id"`
 }
 
-func (q *Queries) ListTrainingsByUserInPeriod(ctx context.Context, arg ListTrainingsByUserInPeriodParams) ([]Training, error) {
-	rows, err := q.db.QueryContext(ctx, listTrainingsByUserInPeriod, arg.UserID, arg.Date, arg.Date_2)
+func (q *Queries) ListTrainingsByGroupTraining(ctx context.Context, arg ListTrainingsByGroupTrainingParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainingsByGroupTraining, arg.OrganizationID, arg.GroupTrainingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrainingsBySeries = `-- name: ListTrainingsBySeries :many
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+FROM training
+WHERE organization_id = $1
+  AND series_id = $2
+  AND deleted_at IS NULL
+ORDER BY date, id
+`
+
+type ListTrainingsBySeriesParams struct {
+	OrganizationID int64      `json:"organization_id"`
+	SeriesID       null.Int64 `json:"series_id"`
+}
+
+func (q *Queries) ListTrainingsBySeries(ctx context.Context, arg ListTrainingsBySeriesParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainingsBySeries, arg.OrganizationID, arg.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrainingsByUser = `-- name: ListTrainingsByUser :many
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+FROM training
+WHERE organization_id = $1
+  AND user_id = $2
+  AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListTrainingsByUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	UserID         int64 `json:"user_id"`
+}
+
+func (q *Queries) ListTrainingsByUser(ctx context.Context, arg ListTrainingsByUserParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainingsByUser, arg.OrganizationID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrainingsByUserInPeriod = `-- name: ListTrainingsByUserInPeriod :many
+SELECT id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+FROM training
+WHERE organization_id = $1
+  AND user_id = $2
+  AND date between $3 AND $4
+  AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListTrainingsByUserInPeriodParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	UserID         int64     `json:"user_id"`
+	Date           time.Time `json:"date"`
+	Date_2         time.Time `json:"date_2"`
+}
+
+func (q *Queries) ListTrainingsByUserInPeriod(ctx context.Context, arg ListTrainingsByUserInPeriodParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainingsByUserInPeriod,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.Date,
+		arg.Date_2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveTraining = `-- name: MoveTraining :one
+UPDATE training
+SET date    = $3,
+    version = version + 1
+WHERE organization_id = $1
+  AND id = $2
+  AND version = $4
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type MoveTrainingParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	ID             int64     `json:"id"`
+	Date           time.Time `json:"date"`
+	Version        int32     `json:"version"`
+}
+
+func (q *Queries) MoveTraining(ctx context.Context, arg MoveTrainingParams) (Training, error) {
+	row := q.db.QueryRowContext(ctx, moveTraining,
+		arg.OrganizationID,
+		arg.ID,
+		arg.Date,
+		arg.Version,
+	)
+	var i Training
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Date,
+		&i.Sport,
+		&i.Type,
+		&i.Intensity,
+		&i.Details,
+		&i.Status,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.SeriesID,
+		&i.GroupTrainingID,
+		&i.OrganizationID,
+		&i.PlannedDuration,
+		&i.Attributes,
+		&i.Version,
+	)
+	return i, err
+}
+
+const purgeTrainings = `-- name: PurgeTrainings :many
+DELETE
+FROM training t
+WHERE t.organization_id = $1
+  AND (t.deleted_at < $2
+    OR t.user_id IN (SELECT u.id FROM users u WHERE u.deleted_at < $2))
+RETURNING t.id
+`
+
+type PurgeTrainingsParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	DeletedAt      null.Time `json:"deleted_at"`
+}
+
+func (q *Queries) PurgeTrainings(ctx context.Context, arg PurgeTrainingsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, purgeTrainings, arg.OrganizationID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreTraining = `-- name: RestoreTraining :one
+UPDATE training
+SET deleted_at = NULL
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type RestoreTrainingParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) RestoreTraining(ctx context.Context, arg RestoreTrainingParams) (Training, error) {
+	row := q.db.QueryRowContext(ctx, restoreTraining, arg.OrganizationID, arg.ID)
+	var i Training
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Date,
+		&i.Sport,
+		&i.Type,
+		&i.Intensity,
+		&i.Details,
+		&i.Status,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.SeriesID,
+		&i.GroupTrainingID,
+		&i.OrganizationID,
+		&i.PlannedDuration,
+		&i.Attributes,
+		&i.Version,
+	)
+	return i, err
+}
+
+const restoreTrainingsByUser = `-- name: RestoreTrainingsByUser :many
+UPDATE training
+SET deleted_at = NULL
+WHERE organization_id = $1
+  AND user_id = $2
+  AND deleted_at >= $3
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type RestoreTrainingsByUserParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	UserID         int64     `json:"user_id"`
+	DeletedAt      null.Time `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreTrainingsByUser(ctx context.Context, arg RestoreTrainingsByUserParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, restoreTrainingsByUser, arg.OrganizationID, arg.UserID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchTraining = `-- name: TouchTraining :exec
+UPDATE training
+SET version = version + 1
+WHERE organization_id = $1
+  AND id = $2
+`
+
+type TouchTrainingParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) TouchTraining(ctx context.Context, arg TouchTrainingParams) error {
+	_, err := q.db.ExecContext(ctx, touchTraining, arg.OrganizationID, arg.ID)
+	return err
+}
+
+const updatePendingGroupTrainings = `-- name: UpdatePendingGroupTrainings :many
+UPDATE training
+SET date             = $3,
+    attributes       = CASE WHEN sport = $4 THEN attributes END,
+    sport            = $4,
+    type             = $5,
+    intensity        = $6,
+    details          = $7,
+    planned_duration = $8,
+    version          = version + 1
+WHERE organization_id = $1
+  AND group_training_id = $2
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type UpdatePendingGroupTrainingsParams struct {
+	OrganizationID  int64       `json:"organization_id"`
+	GroupTrainingID null.Int64  `json:"group_training_id"`
+	Date            time.Time   `json:"date"`
+	Sport           string      `json:"sport"`
+	Type            null.String `json:"type"`
+	Intensity       null.String `json:"intensity"`
+	Details         string      `json:"details"`
+	PlannedDuration null.Int32  `json:"planned_duration"`
+}
+
+func (q *Queries) UpdatePendingGroupTrainings(ctx context.Context, arg UpdatePendingGroupTrainingsParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, updatePendingGroupTrainings,
+		arg.OrganizationID,
+		arg.GroupTrainingID,
+		arg.Date,
+		arg.Sport,
+		arg.Type,
+		arg.Intensity,
+		arg.Details,
+		arg.PlannedDuration,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Training{}
+	for rows.Next() {
+		var i Training
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Date,
+			&i.Sport,
+			&i.Type,
+			&i.Intensity,
+			&i.Details,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateSeriesTrainings = `-- name: UpdateSeriesTrainings :many
+UPDATE training
+SET date       = date + $1::int,
+    attributes = CASE WHEN sport = $2 THEN attributes ELSE $3::jsonb END,
+    sport      = $2,
+    type       = $4,
+    intensity  = $5,
+    details    = $6,
+    series_id  = $7,
+    version    = version + 1
+WHERE organization_id = $8
+  AND series_id = $9
+  AND date >= $10::date
+  AND status NOT IN ('done', 'done_feedback')
+  AND deleted_at IS NULL
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
+`
+
+type UpdateSeriesTrainingsParams struct {
+	Days           int32           `json:"days"`
+	Sport          string          `json:"sport"`
+	Attributes     json.RawMessage `json:"attributes"`
+	Type           null.String     `json:"type"`
+	Intensity      null.String     `json:"intensity"`
+	Details        string          `json:"details"`
+	NewSeriesID    null.Int64      `json:"new_series_id"`
+	OrganizationID int64           `json:"organization_id"`
+	SeriesID       null.Int64      `json:"series_id"`
+	FromDate       time.Time       `json:"from_date"`
+}
+
+func (q *Queries) UpdateSeriesTrainings(ctx context.Context, arg UpdateSeriesTrainingsParams) ([]Training, error) {
+	rows, err := q.db.QueryContext(ctx, updateSeriesTrainings,
+		arg.Days,
+		arg.Sport,
+		arg.Attributes,
+		arg.Type,
+		arg.Intensity,
+		arg.Details,
+		arg.NewSeriesID,
+		arg.OrganizationID,
+		arg.SeriesID,
+		arg.FromDate,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +1077,12 @@ func (q *Queries) ListTrainingsByUserInPeriod(ctx context.Context, arg ListTrain
 			&i.Status,
 			&i.CreatedAt,
 			&i.DeletedAt,
+			&i.SeriesID,
+			&i.GroupTrainingID,
+			&i.OrganizationID,
+			&i.PlannedDuration,
+			&i.Attributes,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -182,28 +1099,38 @@ func (q *Queries) ListTrainingsByUserInPeriod(ctx context.Context, arg ListTrain
 
 const updateTraining = `-- name: UpdateTraining :one
 UPDATE training
-SET date      = $2,
-    sport     = $3,
-    type      = $4,
-    intensity = $5,
-    details   = $6,
-    status    = $7
-WHERE id = $1
-RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at
+SET date             = $3,
+    sport            = $4,
+    type             = $5,
+    intensity        = $6,
+    details          = $7,
+    status           = $8,
+    planned_duration = $9,
+    attributes       = $10,
+    version          = version + 1
+WHERE organization_id = $1
+  AND id = $2
+  AND version = $11
+RETURNING id, user_id, date, sport, type, intensity, details, status, created_at, deleted_at, series_id, group_training_id, organization_id, planned_duration, attributes, version
 `
 
 type UpdateTrainingParams struct {
-	ID        int64          `json:"id"`
-	Date      time.Time      `json:"date"`
-	Sport     TrainingSport  `json:"sport"`
-	Type      null.String    `json:"type"`
-	Intensity null.String    `json:"intensity"`
-	Details   string         `json:"details"`
-	Status    TrainingStatus `json:"status"`
+	OrganizationID  int64           `json:"organization_id"`
+	ID              int64           `json:"id"`
+	Date            time.Time       `json:"date"`
+	Sport           string          `json:"sport"`
+	Type            null.String     `json:"type"`
+	Intensity       null.String     `json:"intensity"`
+	Details         string          `json:"details"`
+	Status          TrainingStatus  `json:"status"`
+	PlannedDuration null.Int32      `json:"planned_duration"`
+	Attributes      json.RawMessage `json:"attributes"`
+	Version         int32           `json:"version"`
 }
 
 func (q *Queries) UpdateTraining(ctx context.Context, arg UpdateTrainingParams) (Training, error) {
 	row := q.db.QueryRowContext(ctx, updateTraining,
+		arg.OrganizationID,
 		arg.ID,
 		arg.Date,
 		arg.Sport,
@@ -211,6 +1138,9 @@ func (q *Queries) UpdateTraining(ctx context.Context, arg UpdateTrainingParams)
 		arg.Intensity,
 		arg.Details,
 		arg.Status,
+		arg.PlannedDuration,
+		arg.Attributes,
+		arg.Version,
 	)
 	var i Training
 	err := row.Scan(
@@ -224,6 +1154,12 @@ func (q *Queries) UpdateTraining(ctx context.Context, arg UpdateTrainingParams)
 		&i.Status,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.SeriesID,
+		&i.GroupTrainingID,
+		&i.OrganizationID,
+		&i.PlannedDuration,
+		&i.Attributes,
+		&i.Version,
 	)
 	return i, err
 }
@@ -6,62 +6,193 @@ package db
 import (
 	"context"
 	"time"
+
+	"github.com/emvi/null"
 )
 
 const createTrainingFeedback = `-- name: CreateTrainingFeedback :one
-INSERT INTO training_feedback (training_id, borg_scale)
-VALUES ($1, $2)
-RETURNING id, training_id, borg_scale
+INSERT INTO training_feedback (organization_id, training_id, borg_scale, duration, distance, avg_power, avg_hr, pain,
+                               injury_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, training_id, borg_scale, organization_id, duration, distance, avg_power, avg_hr, pain, injury_id, version
 `
 
 type CreateTrainingFeedbackParams struct {
-	TrainingID int64 `json:"training_id"`
-	BorgScale  int32 `json:"borg_scale"`
+	OrganizationID int64      `json:"organization_id"`
+	TrainingID     int64      `json:"training_id"`
+	BorgScale      int32      `json:"borg_scale"`
+	Duration       null.Int32 `json:"duration"`
+	Distance       null.Int32 `json:"distance"`
+	AvgPower       null.Int32 `json:"avg_power"`
+	AvgHr          null.Int32 `json:"avg_hr"`
+	Pain           null.Int32 `json:"pain"`
+	InjuryID       null.Int64 `json:"injury_id"`
 }
 
 func (q *Queries) CreateTrainingFeedback(ctx context.Context, arg CreateTrainingFeedbackParams) (TrainingFeedback, error) {
-	row := q.db.QueryRowContext(ctx, createTrainingFeedback, arg.TrainingID, arg.BorgScale)
+	row := q.db.QueryRowContext(ctx, createTrainingFeedback,
+		arg.OrganizationID,
+		arg.TrainingID,
+		arg.BorgScale,
+		arg.Duration,
+		arg.Distance,
+		arg.AvgPower,
+		arg.AvgHr,
+		arg.Pain,
+		arg.InjuryID,
+	)
 	var i TrainingFeedback
-	err := row.Scan(&i.ID, &i.TrainingID, &i.BorgScale)
+	err := row.Scan(
+		&i.ID,
+		&i.TrainingID,
+		&i.BorgScale,
+		&i.OrganizationID,
+		&i.Duration,
+		&i.Distance,
+		&i.AvgPower,
+		&i.AvgHr,
+		&i.Pain,
+		&i.InjuryID,
+		&i.Version,
+	)
 	return i, err
 }
 
 const deleteTrainingFeedback = `-- name: DeleteTrainingFeedback :exec
 DELETE
 FROM training_feedback
-WHERE training_id = $1
+WHERE organization_id = $1
+  AND training_id = $2
+`
+
+type DeleteTrainingFeedbackParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	TrainingID     int64 `json:"training_id"`
+}
+
+func (q *Queries) DeleteTrainingFeedback(ctx context.Context, arg DeleteTrainingFeedbackParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTrainingFeedback, arg.OrganizationID, arg.TrainingID)
+	return err
+}
+
+const eraseUserTrainingFeedbacksPain = `-- name: EraseUserTrainingFeedbacksPain :exec
+UPDATE training_feedback tf
+SET pain      = NULL,
+    injury_id = NULL
+FROM training t
+WHERE t.id = tf.training_id
+  AND tf.organization_id = $1
+  AND t.user_id = $2
 `
 
-func (q *Queries) DeleteTrainingFeedback(ctx context.Context, trainingID int64) error {
-	_, err := q.db.ExecContext(ctx, deleteTrainingFeedback, trainingID)
+type EraseUserTrainingFeedbacksPainParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	UserID         int64 `json:"user_id"`
+}
+
+func (q *Queries) EraseUserTrainingFeedbacksPain(ctx context.Context, arg EraseUserTrainingFeedbacksPainParams) error {
+	_, err := q.db.ExecContext(ctx, eraseUserTrainingFeedbacksPain, arg.OrganizationID, arg.UserID)
 	return err
 }
 
 const getTrainingFeedback = `-- name: GetTrainingFeedback :one
-SELECT id, training_id, borg_scale
+SELECT id, training_id, borg_scale, organization_id, duration, distance, avg_power, avg_hr, pain, injury_id, version
 FROM training_feedback
-WHERE training_id = $1
+WHERE organization_id = $1
+  AND training_id = $2
 LIMIT 1
 `
 
-func (q *Queries) GetTrainingFeedback(ctx context.Context, trainingID int64) (TrainingFeedback, error) {
-	row := q.db.QueryRowContext(ctx, getTrainingFeedback, trainingID)
+type GetTrainingFeedbackParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	TrainingID     int64 `json:"training_id"`
+}
+
+func (q *Queries) GetTrainingFeedback(ctx context.Context, arg GetTrainingFeedbackParams) (TrainingFeedback, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingFeedback, arg.OrganizationID, arg.TrainingID)
 	var i TrainingFeedback
-	err := row.Scan(&i.ID, &i.TrainingID, &i.BorgScale)
+	err := row.Scan(
+		&i.ID,
+		&i.TrainingID,
+		&i.BorgScale,
+		&i.OrganizationID,
+		&i.Duration,
+		&i.Distance,
+		&i.AvgPower,
+		&i.AvgHr,
+		&i.Pain,
+		&i.InjuryID,
+		&i.Version,
+	)
 	return i, err
 }
 
+const listAllTrainingFeedbacksByUser = `-- name: ListAllTrainingFeedbacksByUser :many
+SELECT tf.id, tf.training_id, tf.borg_scale, tf.organization_id, tf.duration, tf.distance, tf.avg_power, tf.avg_hr, tf.pain, tf.injury_id, tf.version
+FROM training_feedback tf
+         JOIN training t ON tf.training_id = t.id
+WHERE tf.organization_id = $1
+  AND t.user_id = $2
+ORDER BY tf.id
+`
+
+type ListAllTrainingFeedbacksByUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	UserID         int64 `json:"user_id"`
+}
+
+func (q *Queries) ListAllTrainingFeedbacksByUser(ctx context.Context, arg ListAllTrainingFeedbacksByUserParams) ([]TrainingFeedback, error) {
+	rows, err := q.db.QueryContext(ctx, listAllTrainingFeedbacksByUser, arg.OrganizationID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TrainingFeedback{}
+	for rows.Next() {
+		var i TrainingFeedback
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrainingID,
+			&i.BorgScale,
+			&i.OrganizationID,
+			&i.Duration,
+			&i.Distance,
+			&i.AvgPower,
+			&i.AvgHr,
+			&i.Pain,
+			&i.InjuryID,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTrainingFeedbacksByUser = `-- name: ListTrainingFeedbacksByUser :many
-SELECT tf.id, tf.training_id, tf.borg_scale
+SELECT tf.id, tf.training_id, tf.borg_scale, tf.organization_id, tf.duration, tf.distance, tf.avg_power, tf.avg_hr, tf.pain, tf.injury_id, tf.version
 FROM training_feedback tf
          JOIN training t ON tf.training_id = t.id
-WHERE t.user_id = $1
+WHERE tf.organization_id = $1
+  AND t.user_id = $2
   AND t.deleted_at IS NULL
 ORDER BY tf.id
 `
 
-func (q *Queries) ListTrainingFeedbacksByUser(ctx context.Context, userID int64) ([]TrainingFeedback, error) {
-	rows, err := q.db.QueryContext(ctx, listTrainingFeedbacksByUser, userID)
+type ListTrainingFeedbacksByUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	UserID         int64 `json:"user_id"`
+}
+
+func (q *Queries) ListTrainingFeedbacksByUser(ctx context.Context, arg ListTrainingFeedbacksByUserParams) ([]TrainingFeedback, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainingFeedbacksByUser, arg.OrganizationID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +200,19 @@ func (q *Queries) ListTrainingFeedbacksByUser(ctx context.Context, userID int64)
 	items := []TrainingFeedback{}
 	for rows.Next() {
 		var i TrainingFeedback
-		if err := rows.Scan(&i.ID, &i.TrainingID, &i.BorgScale); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrainingID,
+			&i.BorgScale,
+			&i.OrganizationID,
+			&i.Duration,
+			&i.Distance,
+			&i.AvgPower,
+			&i.AvgHr,
+			&i.Pain,
+			&i.InjuryID,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -84,23 +227,30 @@ func (q *Queries) ListTrainingFeedbacksByUser(ctx context.Context, userID int64)
 }
 
 const listTrainingFeedbacksByUserInPeriod = `-- name: ListTrainingFeedbacksByUserInPeriod :many
-SELECT tf.id, tf.training_id, tf.borg_scale
+SELECT tf.id, tf.training_id, tf.borg_scale, tf.organization_id, tf.duration, tf.distance, tf.avg_power, tf.avg_hr, tf.pain, tf.injury_id, tf.version
 FROM training_feedback tf
          JOIN training t ON tf.training_id = t.id
-WHERE t.user_id = $1
-  AND date between $2 AND $3
+WHERE tf.organization_id = $1
+  AND t.user_id = $2
+  AND date between $3 AND $4
   AND t.deleted_at IS NULL
 ORDER BY tf.id
 `
 
 type ListTrainingFeedbacksByUserInPeriodParams struct {
-	UserID int64     `json:"user_id"`
-	Date   time.Time `json:"date"`
-	Date_2 time.Time `json:"date_2"`
+	OrganizationID int64     `json:"organization_id"`
+	UserID         int64     `json:"user_id"`
+	Date           time.Time `json:"date"`
+	Date_2         time.Time `json:"date_2"`
 }
 
 func (q *Queries) ListTrainingFeedbacksByUserInPeriod(ctx context.Context, arg ListTrainingFeedbacksByUserInPeriodParams) ([]TrainingFeedback, error) {
-	rows, err := q.db.QueryContext(ctx, listTrainingFeedbacksByUserInPeriod, arg.UserID, arg.Date, arg.Date_2)
+	rows, err := q.db.QueryContext(ctx, listTrainingFeedbacksByUserInPeriod,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.Date,
+		arg.Date_2,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +258,19 @@ func (q *Queries) ListTrainingFeedbacksByUserInPeriod(ctx context.Context, arg L
 	items := []TrainingFeedback{}
 	for rows.Next() {
 		var i TrainingFeedback
-		if err := rows.Scan(&i.ID, &i.TrainingID, &i.BorgScale); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrainingID,
+			&i.BorgScale,
+			&i.OrganizationID,
+			&i.Duration,
+			&i.Distance,
+			&i.AvgPower,
+			&i.AvgHr,
+			&i.Pain,
+			&i.InjuryID,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -122,21 +284,100 @@ func (q *Queries) ListTrainingFeedbacksByUserInPeriod(ctx context.Context, arg L
 	return items, nil
 }
 
+const purgeTrainingFeedbacks = `-- name: PurgeTrainingFeedbacks :many
+DELETE
+FROM training_feedback tf
+    USING training t
+         LEFT JOIN users u ON t.user_id = u.id
+WHERE tf.training_id = t.id
+  AND tf.organization_id = $1
+  AND (t.deleted_at < $2 OR u.deleted_at < $2)
+RETURNING tf.id
+`
+
+type PurgeTrainingFeedbacksParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	DeletedAt      null.Time `json:"deleted_at"`
+}
+
+func (q *Queries) PurgeTrainingFeedbacks(ctx context.Context, arg PurgeTrainingFeedbacksParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, purgeTrainingFeedbacks, arg.OrganizationID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateTrainingFeedback = `-- name: UpdateTrainingFeedback :one
 UPDATE training_feedback
-SET borg_scale = $2
-WHERE training_id = $1
-RETURNING id, training_id, borg_scale
+SET borg_scale = $3,
+    duration   = $4,
+    distance   = $5,
+    avg_power  = $6,
+    avg_hr     = $7,
+    pain       = $8,
+    injury_id  = $9,
+    version    = version + 1
+WHERE organization_id = $1
+  AND training_id = $2
+  AND version = $10
+RETURNING id, training_id, borg_scale, organization_id, duration, distance, avg_power, avg_hr, pain, injury_id, version
 `
 
 type UpdateTrainingFeedbackParams struct {
-	TrainingID int64 `json:"training_id"`
-	BorgScale  int32 `json:"borg_scale"`
+	OrganizationID int64      `json:"organization_id"`
+	TrainingID     int64      `json:"training_id"`
+	BorgScale      int32      `json:"borg_scale"`
+	Duration       null.Int32 `json:"duration"`
+	Distance       null.Int32 `json:"distance"`
+	AvgPower       null.Int32 `json:"avg_power"`
+	AvgHr          null.Int32 `json:"avg_hr"`
+	Pain           null.Int32 `json:"pain"`
+	InjuryID       null.Int64 `json:"injury_id"`
+	Version        int32      `json:"version"`
 }
 
 func (q *Queries) UpdateTrainingFeedback(ctx context.Context, arg UpdateTrainingFeedbackParams) (TrainingFeedback, error) {
-	row := q.db.QueryRowContext(ctx, updateTrainingFeedback, arg.TrainingID, arg.BorgScale)
+	row := q.db.QueryRowContext(ctx, updateTrainingFeedback,
+		arg.OrganizationID,
+		arg.TrainingID,
+		arg.BorgScale,
+		arg.Duration,
+		arg.Distance,
+		arg.AvgPower,
+		arg.AvgHr,
+		arg.Pain,
+		arg.InjuryID,
+		arg.Version,
+	)
 	var i TrainingFeedback
-	err := row.Scan(&i.ID, &i.TrainingID, &i.BorgScale)
+	err := row.Scan(
+		&i.ID,
+		&i.TrainingID,
+		&i.BorgScale,
+		&i.OrganizationID,
+		&i.Duration,
+		&i.Distance,
+		&i.AvgPower,
+		&i.AvgHr,
+		&i.Pain,
+		&i.InjuryID,
+		&i.Version,
+	)
 	return i, err
 }
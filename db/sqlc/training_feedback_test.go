@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"github.com/emvi/null"
 	"time"
 )
@@ -9,6 +10,7 @@ import (
 func (s *DbTestSuite) createTrainingFeedback(trainingID int64, borgScale int32) TrainingFeedback {
 
 	arg := CreateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
 		TrainingID: trainingID,
 		BorgScale: borgScale,
 	}
@@ -32,10 +34,10 @@ func (s *DbTestSuite) TestDeleteTrainingFeedback() {
 	t := s.createTraining(u.ID)
 	s.createTrainingFeedback(t.ID, 10)
 
-	err := s.q.DeleteTrainingFeedback(context.Background(), t.ID)
+	err := s.q.DeleteTrainingFeedback(context.Background(), DeleteTrainingFeedbackParams{OrganizationID: s.org.ID, TrainingID: t.ID})
 	s.Require().NoError(err)
 
-	feedback, err := s.q.GetTrainingFeedback(context.Background(), t.ID)
+	feedback, err := s.q.GetTrainingFeedback(context.Background(), GetTrainingFeedbackParams{OrganizationID: s.org.ID, TrainingID: t.ID})
 	s.Require().Error(err)
 	s.Empty(feedback)
 }
@@ -45,7 +47,7 @@ func (s *DbTestSuite) TestGetTrainingFeedback() {
 	t := s.createTraining(u.ID)
 	f := s.createTrainingFeedback(t.ID, 10)
 
-	feedback, err := s.q.GetTrainingFeedback(context.Background(), t.ID)
+	feedback, err := s.q.GetTrainingFeedback(context.Background(), GetTrainingFeedbackParams{OrganizationID: s.org.ID, TrainingID: t.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(feedback)
 
@@ -72,12 +74,12 @@ func (s *DbTestSuite) TestListTrainingFeedbacksByUser() {
 	s.createTrainingFeedback(t.ID, 10)
 
 
-	trainings, err := s.q.ListTrainingFeedbacksByUser(context.Background(), u1.ID)
+	trainings, err := s.q.ListTrainingFeedbacksByUser(context.Background(), ListTrainingFeedbacksByUserParams{OrganizationID: s.org.ID, UserID: u1.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(trainings)
 	s.Len(trainings, 3)
 
-	trainings, err = s.q.ListTrainingFeedbacksByUser(context.Background(), u2.ID)
+	trainings, err = s.q.ListTrainingFeedbacksByUser(context.Background(), ListTrainingFeedbacksByUserParams{OrganizationID: s.org.ID, UserID: u2.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(trainings)
 	s.Len(trainings, 1)
@@ -87,8 +89,9 @@ func (s *DbTestSuite) TestListTrainingFeedbackByUserInPeriod() {
 	u := s.createUser(UserTypeAthlete, true)
 
 	t := CreateTrainingParams{
+		OrganizationID: s.org.ID,
 		UserID: u.ID,
-		Sport: TrainingSportRunning,
+		Sport: "running",
 		Type: null.String{},
 		Intensity: null.String{},
 		Details: "random training",
@@ -101,6 +104,7 @@ func (s *DbTestSuite) TestListTrainingFeedbackByUserInPeriod() {
 		training, err := s.q.CreateTraining(context.Background(), t)
 		s.Require().NoError(err)
 		f := CreateTrainingFeedbackParams{
+			OrganizationID: s.org.ID,
 			TrainingID: training.ID,
 			BorgScale: 10,
 		}
@@ -112,6 +116,7 @@ func (s *DbTestSuite) TestListTrainingFeedbackByUserInPeriod() {
 	endDate := date.AddDate(0, 0, 7)
 
 	arg := ListTrainingFeedbacksByUserInPeriodParams{
+		s.org.ID,
 		u.ID,
 		startDate,
 		endDate,
@@ -131,17 +136,23 @@ func (s *DbTestSuite) TestUpdateTrainingFeedback() {
 
 
 	arg := UpdateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
 		TrainingID: t.ID,
 		BorgScale: 15,
+		Version: f.Version,
 	}
 
 	_, err := s.q.UpdateTrainingFeedback(context.Background(), arg)
 	s.Require().NoError(err)
 
-	feedback, err := s.q.GetTrainingFeedback(context.Background(), t.ID)
+	_, err = s.q.UpdateTrainingFeedback(context.Background(), arg)
+	s.Require().ErrorIs(err, sql.ErrNoRows)
+
+	feedback, err := s.q.GetTrainingFeedback(context.Background(), GetTrainingFeedbackParams{OrganizationID: s.org.ID, TrainingID: t.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(feedback)
 
 	s.Equal(f.ID, feedback.ID)
 	s.Equal(arg.BorgScale, feedback.BorgScale)
+	s.Equal(f.Version+1, feedback.Version)
 }
\ No newline at end of file
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createBrick(userID int64, date time.Time) TrainingTxResult {
+	arg := CreateTrainingTxParams{
+		Training: CreateTrainingParams{
+			OrganizationID: s.org.ID,
+			UserID:         userID,
+			Date:           date,
+			Sport:          "multisport",
+			Details:        "brick",
+			Status:         TrainingStatusNew,
+		},
+		Legs: []CreateTrainingLegParams{
+			{Position: 1, Sport: "cycling", PlannedDuration: null.NewInt32(3600, true)},
+			{Position: 2, Sport: "running", PlannedDuration: null.NewInt32(1200, true), Transition: null.NewInt32(120, true)},
+		},
+	}
+
+	result, err := s.store.CreateTrainingTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal("multisport", result.Training.Sport)
+	s.Require().Len(result.Legs, 2)
+	for i, l := range result.Legs {
+		s.Equal(s.org.ID, l.OrganizationID)
+		s.Equal(result.Training.ID, l.TrainingID)
+		s.Equal(arg.Legs[i].Sport, l.Sport)
+		s.Equal(arg.Legs[i].Transition, l.Transition)
+	}
+
+	return result
+}
+
+func (s *DbTestSuite) TestUpdateTrainingTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	brick := s.createBrick(u.ID, time.Now().UTC())
+	t := brick.Training
+
+	result, err := s.store.UpdateTrainingTx(context.Background(), UpdateTrainingTxParams{
+		Training: UpdateTrainingParams{
+			OrganizationID: s.org.ID,
+			ID:             t.ID,
+			Date:           t.Date,
+			Sport:          t.Sport,
+			Details:        "swim and run",
+			Status:         t.Status,
+			Version:        t.Version,
+		},
+		Legs: []CreateTrainingLegParams{
+			{Position: 1, Sport: "swimming"},
+			{Position: 2, Sport: "running"},
+			{Position: 3, Sport: "swimming"},
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal("swim and run", result.Training.Details)
+	s.Len(result.Legs, 3)
+
+	// a training that isn't multisport anymore loses its legs
+	result, err = s.store.UpdateTrainingTx(context.Background(), UpdateTrainingTxParams{
+		Training: UpdateTrainingParams{
+			OrganizationID: s.org.ID,
+			ID:             t.ID,
+			Date:           t.Date,
+			Sport:          "running",
+			Details:        t.Details,
+			Status:         t.Status,
+			Version:        result.Training.Version,
+		},
+	})
+	s.Require().NoError(err)
+	s.Empty(result.Legs)
+
+	// the legs stay as they are when the training was updated in the meantime
+	_, err = s.store.UpdateTrainingTx(context.Background(), UpdateTrainingTxParams{
+		Training: UpdateTrainingParams{
+			OrganizationID: s.org.ID,
+			ID:             t.ID,
+			Date:           t.Date,
+			Sport:          t.Sport,
+			Details:        t.Details,
+			Status:         t.Status,
+			Version:        t.Version,
+		},
+		Legs: []CreateTrainingLegParams{{Position: 1, Sport: "swimming"}, {Position: 2, Sport: "running"}},
+	})
+	s.Require().ErrorIs(err, ErrVersionConflict)
+
+	legs, err := s.q.ListTrainingLegs(context.Background(), ListTrainingLegsParams{OrganizationID: s.org.ID, TrainingID: t.ID})
+	s.Require().NoError(err)
+	s.Empty(legs)
+}
+
+func (s *DbTestSuite) TestUpdateTrainingLegsFeedbackTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	brick := s.createBrick(u.ID, time.Now().UTC())
+
+	legs, err := s.store.UpdateTrainingLegsFeedbackTx(context.Background(), UpdateTrainingLegsFeedbackTxParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     brick.Training.ID,
+		Legs: []UpdateTrainingLegFeedbackParams{
+			{Position: 1, Duration: null.NewInt32(3700, true), Distance: null.NewInt32(32000, true)},
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(legs, 1)
+	s.Equal(null.NewInt32(3700, true), legs[0].Duration)
+
+	// the legs left out have no actual data
+	_, err = s.store.UpdateTrainingLegsFeedbackTx(context.Background(), UpdateTrainingLegsFeedbackTxParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     brick.Training.ID,
+		Legs: []UpdateTrainingLegFeedbackParams{
+			{Position: 2, Duration: null.NewInt32(1250, true)},
+		},
+	})
+	s.Require().NoError(err)
+
+	legs, err = s.q.ListTrainingLegs(context.Background(), ListTrainingLegsParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     brick.Training.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(legs, 2)
+	s.False(legs[0].Duration.Valid)
+	s.False(legs[0].Distance.Valid)
+	s.Equal(int32(1250), legs[1].Duration.Int32)
+
+	// the training changed along with its legs
+	training, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: brick.Training.ID})
+	s.Require().NoError(err)
+	s.Equal(brick.Training.Version+2, training.Version)
+}
+
+func (s *DbTestSuite) TestCopyTrainingsTxWithLegs() {
+	u := s.createUser(UserTypeAthlete, true)
+	start, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+	s.createBrick(u.ID, start)
+
+	result, err := s.store.CopyTrainingsTx(context.Background(), CopyTrainingsTxParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		StartDate:      start,
+		EndDate:        start,
+		Days:           7,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(result.Trainings, 1)
+
+	legs, err := s.q.ListTrainingLegsByTrainings(context.Background(), ListTrainingLegsByTrainingsParams{
+		OrganizationID: s.org.ID,
+		TrainingIds:    []int64{result.Trainings[0].ID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(legs, 2)
+	s.Equal("cycling", legs[0].Sport)
+	s.Equal("running", legs[1].Sport)
+	s.Equal(int32(120), legs[1].Transition.Int32)
+}
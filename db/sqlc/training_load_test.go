@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) TestListTrainingLoadsByUser() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+
+	createTraining := func(date time.Time, status TrainingStatus) Training {
+		training, err := s.q.CreateTraining(context.Background(), CreateTrainingParams{
+			OrganizationID:  s.org.ID,
+			UserID:          u.ID,
+			Date:            date,
+			Sport:           "running",
+			Intensity:       null.NewString("Z2", true),
+			Details:         "easy run",
+			Status:          status,
+			PlannedDuration: null.NewInt32(3600, true),
+		})
+		s.Require().NoError(err)
+		s.Equal(int32(3600), training.PlannedDuration.Int32)
+		return training
+	}
+
+	done := createTraining(date, TrainingStatusDoneFeedback)
+	planned := createTraining(date.AddDate(0, 0, 1), TrainingStatusNew)
+	createTraining(date.AddDate(0, 0, 10), TrainingStatusNew)
+
+	feedback, err := s.q.CreateTrainingFeedback(context.Background(), CreateTrainingFeedbackParams{
+		OrganizationID: s.org.ID,
+		TrainingID:     done.ID,
+		BorgScale:      13,
+		Duration:       null.NewInt32(3300, true),
+		Distance:       null.NewInt32(10000, true),
+	})
+	s.Require().NoError(err)
+	s.Equal(int32(3300), feedback.Duration.Int32)
+	s.False(feedback.AvgPower.Valid)
+
+	loads, err := s.q.ListTrainingLoadsByUser(context.Background(), ListTrainingLoadsByUserParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		StartDate:      date,
+		EndDate:        date.AddDate(0, 0, 7),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(loads, 2)
+
+	s.Equal(done.ID, loads[0].ID)
+	s.Equal(int32(13), loads[0].BorgScale)
+	s.Equal(int32(3300), loads[0].Duration)
+	s.Equal(int32(10000), loads[0].Distance)
+	s.Zero(loads[0].AvgPower)
+
+	// trainings without feedback have zero values
+	s.Equal(planned.ID, loads[1].ID)
+	s.Zero(loads[1].BorgScale)
+	s.Equal(null.NewInt32(3600, true), loads[1].PlannedDuration)
+}
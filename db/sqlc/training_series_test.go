@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createTrainingSeries(userID int64, dates int) TrainingSeriesTxResult {
+	start, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+
+	arg := CreateTrainingSeriesTxParams{
+		Series: CreateTrainingSeriesParams{
+			OrganizationID: s.org.ID,
+			UserID:         userID,
+			Rrule:          "FREQ=DAILY;COUNT=10",
+			Dtstart:        start,
+			Sport:          "weight",
+			Details:        "strength session",
+		},
+		Status: TrainingStatusNew,
+	}
+	for i := 0; i < dates; i++ {
+		arg.Dates = append(arg.Dates, start.AddDate(0, 0, i))
+	}
+
+	result, err := s.store.CreateTrainingSeriesTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.NotEmpty(result.Series)
+	s.Equal(arg.Series.Rrule, result.Series.Rrule)
+	s.Equal(arg.Series.Details, result.Series.Details)
+	s.Len(result.Trainings, dates)
+	for _, t := range result.Trainings {
+		s.Equal(null.NewInt64(result.Series.ID, true), t.SeriesID)
+		s.Equal(arg.Series.Details, t.Details)
+	}
+
+	return result
+}
+
+func (s *DbTestSuite) TestCreateTrainingSeriesTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	s.createTrainingSeries(u.ID, 10)
+}
+
+func (s *DbTestSuite) TestListTrainingsBySeries() {
+	u := s.createUser(UserTypeAthlete, true)
+	result := s.createTrainingSeries(u.ID, 5)
+
+	err := s.q.DeleteTraining(context.Background(), DeleteTrainingParams{OrganizationID: s.org.ID, ID: result.Trainings[0].ID})
+	s.Require().NoError(err)
+
+	trainings, err := s.q.ListTrainingsBySeries(context.Background(), ListTrainingsBySeriesParams{OrganizationID: s.org.ID, SeriesID: null.NewInt64(result.Series.ID, true)})
+	s.Require().NoError(err)
+	s.Len(trainings, 4)
+}
+
+func (s *DbTestSuite) TestUpdateTrainingSeriesTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	result := s.createTrainingSeries(u.ID, 5)
+	series := result.Series
+	third := result.Trainings[2]
+
+	// the completed trainings are kept as they are
+	fourth := result.Trainings[3]
+	done, err := s.q.UpdateTraining(context.Background(), UpdateTrainingParams{
+		OrganizationID: s.org.ID,
+		ID:             fourth.ID,
+		Date:           fourth.Date,
+		Sport:          fourth.Sport,
+		Details:        fourth.Details,
+		Status:         TrainingStatusDone,
+		Version:        fourth.Version,
+	})
+	s.Require().NoError(err)
+
+	arg := UpdateTrainingSeriesTxParams{
+		Series: UpdateTrainingSeriesParams{
+			OrganizationID: s.org.ID,
+			ID:             series.ID,
+			Rrule:          "FREQ=DAILY;UNTIL=20000101",
+			Dtstart:        series.Dtstart,
+			Sport:          series.Sport,
+			Details:        series.Details,
+		},
+		Split: &CreateTrainingSeriesParams{
+			OrganizationID: s.org.ID,
+			UserID:         u.ID,
+			Rrule:          "FREQ=DAILY;COUNT=3",
+			Dtstart:        third.Date.AddDate(0, 0, 1),
+			Sport:          "running",
+			Details:        "new details",
+		},
+		Trainings: UpdateSeriesTrainingsParams{
+			OrganizationID: s.org.ID,
+			Days:           1,
+			Sport:          "running",
+			Attributes:     json.RawMessage(`{"distance": 5000}`),
+			Details:        "new details",
+			SeriesID:       null.NewInt64(series.ID, true),
+			FromDate:       third.Date,
+		},
+		Training: UpdateTrainingParams{
+			OrganizationID: s.org.ID,
+			ID:             third.ID,
+			Date:           third.Date.AddDate(0, 0, 1),
+			Sport:          "running",
+			Details:        "new details",
+			Status:         TrainingStatusDone,
+			Version:        third.Version,
+		},
+	}
+
+	updated, err := s.store.UpdateTrainingSeriesTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.NotEqual(series.ID, updated.Series.ID)
+	s.Len(updated.Trainings, 2)
+	for _, t := range updated.Trainings {
+		s.NotEqual(fourth.ID, t.ID)
+		s.Equal(null.NewInt64(updated.Series.ID, true), t.SeriesID)
+		s.Equal("new details", t.Details)
+		// the sport changed, so the trainings take the new attributes
+		s.JSONEq(`{"distance": 5000}`, string(t.Attributes))
+	}
+
+	training, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: third.ID})
+	s.Require().NoError(err)
+	s.Equal(TrainingStatusDone, training.Status)
+	s.Equal(third.Date.AddDate(0, 0, 1).Format("2006-01-02"), training.Date.Format("2006-01-02"))
+
+	training, err = s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: fourth.ID})
+	s.Require().NoError(err)
+	s.Equal(done, training)
+
+	trainings, err := s.q.ListTrainingsBySeries(context.Background(), ListTrainingsBySeriesParams{OrganizationID: s.org.ID, SeriesID: null.NewInt64(series.ID, true)})
+	s.Require().NoError(err)
+	s.Len(trainings, 3)
+}
+
+func (s *DbTestSuite) TestDeleteTrainingSeriesTx() {
+	u := s.createUser(UserTypeAthlete, true)
+	result := s.createTrainingSeries(u.ID, 5)
+	series := result.Series
+	seriesID := null.NewInt64(series.ID, true)
+
+	// the completed trainings are kept
+	last := result.Trainings[4]
+	_, err := s.q.UpdateTraining(context.Background(), UpdateTrainingParams{
+		OrganizationID: s.org.ID,
+		ID:             last.ID,
+		Date:           last.Date,
+		Sport:          last.Sport,
+		Details:        last.Details,
+		Status:         TrainingStatusDone,
+		Version:        last.Version,
+	})
+	s.Require().NoError(err)
+
+	arg := DeleteTrainingSeriesTxParams{
+		Series: UpdateTrainingSeriesParams{
+			OrganizationID: s.org.ID,
+			ID:             series.ID,
+			Rrule:          series.Rrule,
+			Dtstart:        series.Dtstart,
+			Exdate:         result.Trainings[0].Date.Format("20060102"),
+			Sport:          series.Sport,
+			Details:        series.Details,
+		},
+		TrainingID: result.Trainings[0].ID,
+	}
+
+	// this training
+	deleted, err := s.store.DeleteTrainingSeriesTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Len(deleted.Deleted, 1)
+
+	got, err := s.q.GetTrainingSeries(context.Background(), GetTrainingSeriesParams{OrganizationID: s.org.ID, ID: series.ID})
+	s.Require().NoError(err)
+	s.Equal(arg.Series.Exdate, got.Exdate)
+
+	// this and following
+	arg.TrainingID = 0
+	arg.FromDate = result.Trainings[3].Date
+	deleted, err = s.store.DeleteTrainingSeriesTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Len(deleted.Deleted, 1)
+	s.NotEqual(last.ID, deleted.Deleted[0].ID)
+
+	trainings, err := s.q.ListTrainingsBySeries(context.Background(), ListTrainingsBySeriesParams{OrganizationID: s.org.ID, SeriesID: seriesID})
+	s.Require().NoError(err)
+	s.Len(trainings, 3)
+
+	// whole series
+	arg.FromDate = time.Time{}
+	arg.DeleteSeries = true
+	deleted, err = s.store.DeleteTrainingSeriesTx(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Len(deleted.Deleted, 2)
+	s.Require().Len(deleted.Detached, 1)
+	s.Equal(last.ID, deleted.Detached[0].ID)
+	s.False(deleted.Detached[0].SeriesID.Valid)
+
+	_, err = s.q.GetTrainingSeries(context.Background(), GetTrainingSeriesParams{OrganizationID: s.org.ID, ID: series.ID})
+	s.Require().Error(err)
+
+	training, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: last.ID})
+	s.Require().NoError(err)
+	s.Equal(TrainingStatusDone, training.Status)
+}
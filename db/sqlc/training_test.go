@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"github.com/emvi/null"
 	"time"
 )
@@ -9,9 +10,10 @@ import (
 func (s *DbTestSuite) createTraining(userID int64) Training {
 
 	arg := CreateTrainingParams{
+		OrganizationID: s.org.ID,
 		UserID: userID,
 		Date: time.Now().UTC(),
-		Sport: TrainingSportRunning,
+		Sport: "running",
 		Type: null.String{},
 		Intensity: null.String{},
 		Details: "random training",
@@ -45,10 +47,10 @@ func (s *DbTestSuite) TestDeleteTraining() {
 	u := s.createUser(UserTypeAthlete, true)
 	t := s.createTraining(u.ID)
 
-	err := s.q.DeleteTraining(context.Background(), t.ID)
+	err := s.q.DeleteTraining(context.Background(), DeleteTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
 	s.Require().NoError(err)
 
-	training, err := s.q.GetTraining(context.Background(), t.ID)
+	training, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
 	s.Require().Error(err)
 	s.Empty(training)
 }
@@ -57,7 +59,7 @@ func (s *DbTestSuite) TestGetTraining() {
 	u := s.createUser(UserTypeAthlete, true)
 	t := s.createTraining(u.ID)
 
-	training, err := s.q.GetTraining(context.Background(), t.ID)
+	training, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(training)
 
@@ -84,7 +86,7 @@ func (s *DbTestSuite) TestListTrainingByUser() {
 		s.createTraining(u2.ID)
 	}
 
-	trainings, err := s.q.ListTrainingsByUser(context.Background(), u1.ID)
+	trainings, err := s.q.ListTrainingsByUser(context.Background(), ListTrainingsByUserParams{OrganizationID: s.org.ID, UserID: u1.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(trainings)
 	s.Len(trainings, 3)
@@ -92,7 +94,7 @@ func (s *DbTestSuite) TestListTrainingByUser() {
 		s.Equal(t.UserID, u1.ID)
 	}
 
-	trainings, err = s.q.ListTrainingsByUser(context.Background(), u2.ID)
+	trainings, err = s.q.ListTrainingsByUser(context.Background(), ListTrainingsByUserParams{OrganizationID: s.org.ID, UserID: u2.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(trainings)
 	s.Len(trainings, 4)
@@ -105,8 +107,9 @@ func (s *DbTestSuite) TestListTrainingsByUserInPeriod() {
 	u := s.createUser(UserTypeAthlete, true)
 
 	t := CreateTrainingParams{
+		OrganizationID: s.org.ID,
 		UserID: u.ID,
-		Sport: TrainingSportRunning,
+		Sport: "running",
 		Type: null.String{},
 		Intensity: null.String{},
 		Details: "random training",
@@ -124,6 +127,7 @@ func (s *DbTestSuite) TestListTrainingsByUserInPeriod() {
 	endDate := date.AddDate(0, 0, 7)
 
 	arg := ListTrainingsByUserInPeriodParams{
+		s.org.ID,
 		u.ID,
 		startDate,
 		endDate,
@@ -146,19 +150,25 @@ func (s *DbTestSuite) TestUpdateTraining() {
 	d, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
 
 	arg := UpdateTrainingParams{
+		OrganizationID: s.org.ID,
 		ID: t.ID,
 		Date: d,
-		Sport: TrainingSportCycling,
+		Sport: "cycling",
 		Type: null.NewString("interval", true),
 		Intensity: null.NewString("high", true),
 		Details: "details 2",
 		Status: TrainingStatusNotified,
+		Version: t.Version,
 	}
 
 	_, err := s.q.UpdateTraining(context.Background(), arg)
 	s.Require().NoError(err)
 
-	training, err := s.q.GetTraining(context.Background(), arg.ID)
+	// the version was bumped, so an update made from the previous one doesn't apply
+	_, err = s.q.UpdateTraining(context.Background(), arg)
+	s.Require().ErrorIs(err, sql.ErrNoRows)
+
+	training, err := s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: arg.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(training)
 
@@ -173,4 +183,24 @@ func (s *DbTestSuite) TestUpdateTraining() {
 
 	s.Equal(t.CreatedAt, training.CreatedAt)
 	s.Equal(t.DeletedAt, training.DeletedAt)
-}
\ No newline at end of file
+}
+
+func (s *DbTestSuite) TestRestoreTraining() {
+	u := s.createUser(UserTypeAthlete, true)
+	t := s.createTraining(u.ID)
+
+	err := s.q.DeleteTraining(context.Background(), DeleteTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
+	s.Require().NoError(err)
+
+	deleted, err := s.q.GetDeletedTraining(context.Background(), GetDeletedTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
+	s.Require().NoError(err)
+	s.True(deleted.DeletedAt.Valid)
+
+	training, err := s.q.RestoreTraining(context.Background(), RestoreTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
+	s.Require().NoError(err)
+	s.Equal(t.ID, training.ID)
+	s.False(training.DeletedAt.Valid)
+
+	_, err = s.q.GetTraining(context.Background(), GetTrainingParams{OrganizationID: s.org.ID, ID: t.ID})
+	s.Require().NoError(err)
+}
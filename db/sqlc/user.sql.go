@@ -10,23 +10,28 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (type, name, gender, email, phone, birth, active)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, type, name, gender, email, phone, birth, active, created_at, deleted_at
+INSERT INTO users (organization_id, type, name, gender, email, phone, birth, active, timezone, locale, units)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
 `
 
 type CreateUserParams struct {
-	Type   UserType    `json:"type"`
-	Name   string      `json:"name"`
-	Gender GenderType  `json:"gender"`
-	Email  string      `json:"email"`
-	Phone  null.String `json:"phone"`
-	Birth  null.Time   `json:"birth"`
-	Active bool        `json:"active"`
+	OrganizationID int64       `json:"organization_id"`
+	Type           UserType    `json:"type"`
+	Name           string      `json:"name"`
+	Gender         null.String `json:"gender"`
+	Email          string      `json:"email"`
+	Phone          null.String `json:"phone"`
+	Birth          null.Time   `json:"birth"`
+	Active         bool        `json:"active"`
+	Timezone       string      `json:"timezone"`
+	Locale         string      `json:"locale"`
+	Units          UnitSystem  `json:"units"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
 	row := q.db.QueryRowContext(ctx, createUser,
+		arg.OrganizationID,
 		arg.Type,
 		arg.Name,
 		arg.Gender,
@@ -34,6 +39,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Phone,
 		arg.Birth,
 		arg.Active,
+		arg.Timezone,
+		arg.Locale,
+		arg.Units,
 	)
 	var i User
 	err := row.Scan(
@@ -47,6 +55,11 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Active,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
 	)
 	return i, err
 }
@@ -54,24 +67,118 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 const deleteUser = `-- name: DeleteUser :exec
 UPDATE users
 SET deleted_at = now()
-WHERE id = $1
+WHERE organization_id = $1
+  AND id = $2
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
+type DeleteUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, arg.OrganizationID, arg.ID)
 	return err
 }
 
+const eraseUser = `-- name: EraseUser :one
+UPDATE users
+SET name     = 'Erased user',
+    gender   = NULL,
+    email    = 'erased-' || id || '@erased.invalid',
+    phone    = NULL,
+    birth    = NULL,
+    active   = FALSE,
+    timezone = DEFAULT,
+    locale   = DEFAULT,
+    units    = DEFAULT,
+    version  = version + 1
+WHERE organization_id = $1
+  AND id = $2
+RETURNING id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
+`
+
+type EraseUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) EraseUser(ctx context.Context, arg EraseUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, eraseUser, arg.OrganizationID, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Name,
+		&i.Gender,
+		&i.Email,
+		&i.Phone,
+		&i.Birth,
+		&i.Active,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getDeletedUser = `-- name: GetDeletedUser :one
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
+FROM users
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+LIMIT 1
+`
+
+type GetDeletedUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) GetDeletedUser(ctx context.Context, arg GetDeletedUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedUser, arg.OrganizationID, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Name,
+		&i.Gender,
+		&i.Email,
+		&i.Phone,
+		&i.Birth,
+		&i.Active,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
+	)
+	return i, err
+}
+
 const getUser = `-- name: GetUser :one
-SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
 FROM users
-WHERE id = $1
+WHERE organization_id = $1
+  AND id = $2
   AND deleted_at IS NULL
 LIMIT 1
 `
 
-func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUser, id)
+type GetUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) GetUser(ctx context.Context, arg GetUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, arg.OrganizationID, arg.ID)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -84,26 +191,156 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.Active,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
+FROM users
+WHERE organization_id = $1
+  AND email = $2
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT 1
+`
+
+type GetUserByEmailParams struct {
+	OrganizationID int64  `json:"organization_id"`
+	Email          string `json:"email"`
+}
+
+func (q *Queries) GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, arg.OrganizationID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Name,
+		&i.Gender,
+		&i.Email,
+		&i.Phone,
+		&i.Birth,
+		&i.Active,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getUserIncludingDeleted = `-- name: GetUserIncludingDeleted :one
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
+FROM users
+WHERE organization_id = $1
+  AND id = $2
+LIMIT 1
+`
+
+type GetUserIncludingDeletedParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) GetUserIncludingDeleted(ctx context.Context, arg GetUserIncludingDeletedParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserIncludingDeleted, arg.OrganizationID, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Name,
+		&i.Gender,
+		&i.Email,
+		&i.Phone,
+		&i.Birth,
+		&i.Active,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
+	)
+	return i, err
+}
+
+const listActiveAthletes = `-- name: ListActiveAthletes :many
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
+FROM users
+WHERE organization_id = $1
+  AND type = 'athlete'
+  AND active = TRUE
+  AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) ListActiveAthletes(ctx context.Context, organizationID int64) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveAthletes, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Name,
+			&i.Gender,
+			&i.Email,
+			&i.Phone,
+			&i.Birth,
+			&i.Active,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.OrganizationID,
+			&i.Timezone,
+			&i.Locale,
+			&i.Units,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listActiveUsers = `-- name: ListActiveUsers :many
-SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
 FROM users
-WHERE deleted_at IS NULL
+WHERE organization_id = $1
+  AND deleted_at IS NULL
   AND active = TRUE
 ORDER BY id
-LIMIT $1 OFFSET $2
+LIMIT $2 OFFSET $3
 `
 
 type ListActiveUsersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	OrganizationID int64 `json:"organization_id"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
 }
 
 func (q *Queries) ListActiveUsers(ctx context.Context, arg ListActiveUsersParams) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listActiveUsers, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listActiveUsers, arg.OrganizationID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +359,11 @@ func (q *Queries) ListActiveUsers(ctx context.Context, arg ListActiveUsersParams
 			&i.Active,
 			&i.CreatedAt,
 			&i.DeletedAt,
+			&i.OrganizationID,
+			&i.Timezone,
+			&i.Locale,
+			&i.Units,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -137,19 +379,75 @@ func (q *Queries) ListActiveUsers(ctx context.Context, arg ListActiveUsersParams
 }
 
 const listAllUsers = `-- name: ListAllUsers :many
-SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
 FROM users
+WHERE organization_id = $1
 ORDER BY id
-LIMIT $1 OFFSET $2
+LIMIT $2 OFFSET $3
 `
 
 type ListAllUsersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	OrganizationID int64 `json:"organization_id"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
 }
 
 func (q *Queries) ListAllUsers(ctx context.Context, arg ListAllUsersParams) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listAllUsers, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listAllUsers, arg.OrganizationID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Name,
+			&i.Gender,
+			&i.Email,
+			&i.Phone,
+			&i.Birth,
+			&i.Active,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.OrganizationID,
+			&i.Timezone,
+			&i.Locale,
+			&i.Units,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedUsers = `-- name: ListDeletedUsers :many
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
+FROM users
+WHERE organization_id = $1
+  AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListDeletedUsersParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
+}
+
+func (q *Queries) ListDeletedUsers(ctx context.Context, arg ListDeletedUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedUsers, arg.OrganizationID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -168,6 +466,11 @@ func (q *Queries) ListAllUsers(ctx context.Context, arg ListAllUsersParams) ([]U
 			&i.Active,
 			&i.CreatedAt,
 			&i.DeletedAt,
+			&i.OrganizationID,
+			&i.Timezone,
+			&i.Locale,
+			&i.Units,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -183,20 +486,22 @@ func (q *Queries) ListAllUsers(ctx context.Context, arg ListAllUsersParams) ([]U
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at
+SELECT id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
 FROM users
-WHERE deleted_at IS NULL
+WHERE organization_id = $1
+  AND deleted_at IS NULL
 ORDER BY id
-LIMIT $1 OFFSET $2
+LIMIT $2 OFFSET $3
 `
 
 type ListUsersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	OrganizationID int64 `json:"organization_id"`
+	Limit          int32 `json:"limit"`
+	Offset         int32 `json:"offset"`
 }
 
 func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listUsers, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.OrganizationID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -215,6 +520,11 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.Active,
 			&i.CreatedAt,
 			&i.DeletedAt,
+			&i.OrganizationID,
+			&i.Timezone,
+			&i.Locale,
+			&i.Units,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -229,32 +539,117 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const purgeUsers = `-- name: PurgeUsers :many
+DELETE
+FROM users
+WHERE organization_id = $1
+  AND deleted_at < $2
+RETURNING id
+`
+
+type PurgeUsersParams struct {
+	OrganizationID int64     `json:"organization_id"`
+	DeletedAt      null.Time `json:"deleted_at"`
+}
+
+func (q *Queries) PurgeUsers(ctx context.Context, arg PurgeUsersParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, purgeUsers, arg.OrganizationID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE organization_id = $1
+  AND id = $2
+  AND deleted_at IS NOT NULL
+RETURNING id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
+`
+
+type RestoreUserParams struct {
+	OrganizationID int64 `json:"organization_id"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, arg.OrganizationID, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Name,
+		&i.Gender,
+		&i.Email,
+		&i.Phone,
+		&i.Birth,
+		&i.Active,
+		&i.CreatedAt,
+		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
+	)
+	return i, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET type   = $2,
-    name   = $3,
-    gender = $4,
-    email  = $5,
-    phone  = $6,
-    birth  = $7,
-    active = $8
-WHERE id = $1
-RETURNING id, type, name, gender, email, phone, birth, active, created_at, deleted_at
+SET type     = $3,
+    name     = $4,
+    gender   = $5,
+    email    = $6,
+    phone    = $7,
+    birth    = $8,
+    active   = $9,
+    timezone = $10,
+    locale   = $11,
+    units    = $12,
+    version  = version + 1
+WHERE organization_id = $1
+  AND id = $2
+  AND version = $13
+RETURNING id, type, name, gender, email, phone, birth, active, created_at, deleted_at, organization_id, timezone, locale, units, version
 `
 
 type UpdateUserParams struct {
-	ID     int64       `json:"id"`
-	Type   UserType    `json:"type"`
-	Name   string      `json:"name"`
-	Gender GenderType  `json:"gender"`
-	Email  string      `json:"email"`
-	Phone  null.String `json:"phone"`
-	Birth  null.Time   `json:"birth"`
-	Active bool        `json:"active"`
+	OrganizationID int64       `json:"organization_id"`
+	ID             int64       `json:"id"`
+	Type           UserType    `json:"type"`
+	Name           string      `json:"name"`
+	Gender         null.String `json:"gender"`
+	Email          string      `json:"email"`
+	Phone          null.String `json:"phone"`
+	Birth          null.Time   `json:"birth"`
+	Active         bool        `json:"active"`
+	Timezone       string      `json:"timezone"`
+	Locale         string      `json:"locale"`
+	Units          UnitSystem  `json:"units"`
+	Version        int32       `json:"version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
 	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.OrganizationID,
 		arg.ID,
 		arg.Type,
 		arg.Name,
@@ -263,6 +658,10 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Phone,
 		arg.Birth,
 		arg.Active,
+		arg.Timezone,
+		arg.Locale,
+		arg.Units,
+		arg.Version,
 	)
 	var i User
 	err := row.Scan(
@@ -276,6 +675,11 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Active,
 		&i.CreatedAt,
 		&i.DeletedAt,
+		&i.OrganizationID,
+		&i.Timezone,
+		&i.Locale,
+		&i.Units,
+		&i.Version,
 	)
 	return i, err
 }
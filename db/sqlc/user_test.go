@@ -10,13 +10,17 @@ import (
 
 func (s *DbTestSuite) createUser(userType UserType, active bool) User {
 	arg := CreateUserParams{
+		OrganizationID: s.org.ID,
 		Type: userType,
 		Name: s.f.Person().Name(),
-		Gender: GenderTypeM,
+		Gender: null.NewString(string(GenderTypeM), true),
 		Email: s.f.Internet().Email(),
 		Phone: null.NewString("12345678", true),
 		Birth: null.NewTime(time.Now().UTC(), true),
 		Active: active,
+		Timezone: "UTC",
+		Locale: "en",
+		Units: UnitSystemMetric,
 	}
 
 	user, err := s.q.CreateUser(context.Background(), arg)
@@ -29,6 +33,8 @@ func (s *DbTestSuite) createUser(userType UserType, active bool) User {
 	s.Equal(arg.Phone, user.Phone)
 	s.Equal(arg.Birth.Time.Format("2006-01-02"), user.Birth.Time.Format("2006-01-02"))
 	s.Equal(arg.Active, user.Active)
+	s.Equal(arg.Timezone, user.Timezone)
+	s.Equal(arg.Units, user.Units)
 
 	s.NotEmpty(user.CreatedAt)
 	s.Empty(user.DeletedAt)
@@ -43,17 +49,17 @@ func (s *DbTestSuite) TestCreateUser() {
 func (s *DbTestSuite) TestDeleteUser() {
 	u := s.createUser(UserTypeAdmin, false)
 
-	err := s.q.DeleteUser(context.Background(),u.ID)
+	err := s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: u.ID})
 	s.Require().NoError(err)
 
-	_, err = s.q.GetUser(context.Background(), u.ID)
+	_, err = s.q.GetUser(context.Background(), GetUserParams{OrganizationID: s.org.ID, ID: u.ID})
 	s.Require().Error(err)
 }
 
 func (s *DbTestSuite) TestGetUser() {
 	u := s.createUser(UserTypeAdmin, false)
 
-	user, err := s.q.GetUser(context.Background(), u.ID)
+	user, err := s.q.GetUser(context.Background(), GetUserParams{OrganizationID: s.org.ID, ID: u.ID})
 	s.Require().NoError(err)
 	s.NotEmpty(user)
 
@@ -70,6 +76,7 @@ func (s *DbTestSuite) TestGetUser() {
 
 func (s *DbTestSuite) TestListActiveUsers() {
 	arg := ListActiveUsersParams{
+		OrganizationID: s.org.ID,
 		Limit: math.MaxInt32,
 		Offset: 0,
 	}
@@ -102,6 +109,7 @@ func (s *DbTestSuite) TestListActiveUsers() {
 
 func (s *DbTestSuite) TestListAllUsers() {
 	arg := ListAllUsersParams{
+		OrganizationID: s.org.ID,
 		Limit: math.MaxInt32,
 		Offset: 0,
 	}
@@ -122,7 +130,7 @@ func (s *DbTestSuite) TestListAllUsers() {
 	// Create a few deleted users
 	for i := 0; i<5; i++ {
 		u := s.createUser(UserTypeAthlete, false)
-		err := s.q.DeleteUser(context.Background(), u.ID)
+		err := s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: u.ID})
 		s.NoError(err)
 	}
 
@@ -134,6 +142,7 @@ func (s *DbTestSuite) TestListAllUsers() {
 
 func (s *DbTestSuite) TestListUsers() {
 	arg := ListUsersParams{
+		OrganizationID: s.org.ID,
 		Limit: math.MaxInt32,
 		Offset: 0,
 	}
@@ -154,7 +163,7 @@ func (s *DbTestSuite) TestListUsers() {
 	// Create a few deleted users
 	for i := 0; i<5; i++ {
 		u := s.createUser(UserTypeAthlete, false)
-		err := s.q.DeleteUser(context.Background(), u.ID)
+		err := s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: u.ID})
 		s.NoError(err)
 	}
 
@@ -168,14 +177,19 @@ func (s *DbTestSuite) TestUpdateUser() {
 	u := s.createUser(UserTypeAdmin, false)
 
 	arg := UpdateUserParams{
+		OrganizationID: s.org.ID,
 		ID: u.ID,
 		Type: u.Type,
 		Name: s.f.Person().Name(),
-		Gender: GenderTypeF,
+		Gender: null.NewString(string(GenderTypeF), true),
 		Email: s.f.Internet().Email(),
 		Phone: u.Phone,
 		Birth: u.Birth,
 		Active: true,
+		Timezone: "America/Sao_Paulo",
+		Locale: "pt-BR",
+		Units: UnitSystemImperial,
+		Version: u.Version,
 	}
 
 	user, err := s.q.UpdateUser(context.Background(), arg)
@@ -189,5 +203,45 @@ func (s *DbTestSuite) TestUpdateUser() {
 	s.Equal(u.Phone, user.Phone)
 	s.Equal(u.Birth.Time, user.Birth.Time)
 	s.Equal(true, user.Active)
+	s.Equal(arg.Timezone, user.Timezone)
+	s.Equal(arg.Locale, user.Locale)
+	s.Equal(arg.Units, user.Units)
+	s.Equal(u.Version+1, user.Version)
 	s.Equal(u.CreatedAt, user.CreatedAt)
 }
+
+func (s *DbTestSuite) TestRestoreUser() {
+	u := s.createUser(UserTypeAthlete, true)
+
+	_, err := s.q.RestoreUser(context.Background(), RestoreUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().Error(err)
+
+	err = s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().NoError(err)
+
+	deleted, err := s.q.GetDeletedUser(context.Background(), GetDeletedUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().NoError(err)
+	s.True(deleted.DeletedAt.Valid)
+
+	user, err := s.q.RestoreUser(context.Background(), RestoreUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().NoError(err)
+	s.Equal(u.ID, user.ID)
+	s.False(user.DeletedAt.Valid)
+
+	_, err = s.q.GetUser(context.Background(), GetUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().NoError(err)
+}
+
+func (s *DbTestSuite) TestGetUserByEmail() {
+	u := s.createUser(UserTypeAthlete, true)
+
+	user, err := s.q.GetUserByEmail(context.Background(), GetUserByEmailParams{OrganizationID: s.org.ID, Email: u.Email})
+	s.Require().NoError(err)
+	s.Equal(u.ID, user.ID)
+
+	err = s.q.DeleteUser(context.Background(), DeleteUserParams{OrganizationID: s.org.ID, ID: u.ID})
+	s.Require().NoError(err)
+
+	_, err = s.q.GetUserByEmail(context.Background(), GetUserByEmailParams{OrganizationID: s.org.ID, Email: u.Email})
+	s.Require().Error(err)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createWellness(userID int64, date time.Time) Wellness {
+	arg := CreateWellnessParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		Date:           date,
+		Weight:         null.NewFloat64(64.2, true),
+		RestingHr:      null.NewInt32(48, true),
+		Hrv:            null.NewInt32(70, true),
+		SleepHours:     null.NewFloat64(7.5, true),
+		SleepQuality:   null.NewInt32(4, true),
+		CyclePhase:     null.NewString("follicular", true),
+	}
+
+	wellness, err := s.q.CreateWellness(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.Date.Format("2006-01-02"), wellness.Date.Format("2006-01-02"))
+	s.Equal(arg.Weight, wellness.Weight)
+	s.Equal(arg.Hrv, wellness.Hrv)
+	s.Equal(arg.CyclePhase, wellness.CyclePhase)
+	s.False(wellness.Soreness.Valid)
+
+	return wellness
+}
+
+func (s *DbTestSuite) TestCreateWellnessTwiceADay() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+	s.createWellness(u.ID, date)
+
+	_, err := s.q.CreateWellness(context.Background(), CreateWellnessParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		Date:           date,
+	})
+	s.Require().Error(err)
+}
+
+func (s *DbTestSuite) TestUpdateWellness() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+	w := s.createWellness(u.ID, date)
+
+	updated, err := s.q.UpdateWellness(context.Background(), UpdateWellnessParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		Date:           date,
+		Weight:         w.Weight,
+		Soreness:       null.NewInt32(3, true),
+	})
+	s.Require().NoError(err)
+	s.Equal(w.ID, updated.ID)
+	s.Equal(w.Weight, updated.Weight)
+	s.Equal(null.NewInt32(3, true), updated.Soreness)
+	s.False(updated.Hrv.Valid)
+}
+
+func (s *DbTestSuite) TestListWellnessByUserInPeriod() {
+	u1 := s.createUser(UserTypeAthlete, true)
+	u2 := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+
+	s.createWellness(u1.ID, date.AddDate(0, 0, -1))
+	first := s.createWellness(u1.ID, date)
+	second := s.createWellness(u1.ID, date.AddDate(0, 0, 1))
+	s.createWellness(u2.ID, date)
+
+	entries, err := s.q.ListWellnessByUserInPeriod(context.Background(), ListWellnessByUserInPeriodParams{
+		OrganizationID: s.org.ID,
+		UserID:         u1.ID,
+		StartDate:      date,
+		EndDate:        date.AddDate(0, 0, 7),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(entries, 2)
+	s.Equal(first.ID, entries[0].ID)
+	s.Equal(second.ID, entries[1].ID)
+}
+
+func (s *DbTestSuite) TestDeleteWellness() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+	s.createWellness(u.ID, date)
+
+	err := s.q.DeleteWellness(context.Background(), DeleteWellnessParams{OrganizationID: s.org.ID, UserID: u.ID, Date: date})
+	s.Require().NoError(err)
+
+	_, err = s.q.GetWellness(context.Background(), GetWellnessParams{OrganizationID: s.org.ID, UserID: u.ID, Date: date})
+	s.Require().Error(err)
+}
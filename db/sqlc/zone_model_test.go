@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/emvi/null"
+)
+
+func (s *DbTestSuite) createZoneModel(userID int64, method ZoneMethod, effectiveFrom time.Time) ZoneModel {
+	arg := CreateZoneModelParams{
+		OrganizationID: s.org.ID,
+		UserID:         userID,
+		Sport:          "running",
+		Method:         method,
+		Threshold:      int32(s.f.IntBetween(150, 200)),
+		Resting:        null.NewInt32(50, method == ZoneMethodHrKarvonen),
+		EffectiveFrom:  effectiveFrom,
+	}
+
+	model, err := s.q.CreateZoneModel(context.Background(), arg)
+	s.Require().NoError(err)
+	s.Equal(arg.UserID, model.UserID)
+	s.Equal(arg.Method, model.Method)
+	s.Equal(arg.Threshold, model.Threshold)
+	s.Equal(arg.Resting, model.Resting)
+	s.Equal(arg.EffectiveFrom.Format("2006-01-02"), model.EffectiveFrom.Format("2006-01-02"))
+
+	return model
+}
+
+func (s *DbTestSuite) TestListEffectiveZoneModels() {
+	u := s.createUser(UserTypeAthlete, true)
+	date, _ := time.Parse("2006-01-02", "2021-06-01")
+
+	old := s.createZoneModel(u.ID, ZoneMethodHrMax, date.AddDate(0, -2, 0))
+	current := s.createZoneModel(u.ID, ZoneMethodHrMax, date.AddDate(0, -1, 0))
+	pace := s.createZoneModel(u.ID, ZoneMethodPace, date.AddDate(0, -3, 0))
+	s.createZoneModel(u.ID, ZoneMethodHrMax, date.AddDate(0, 1, 0))
+
+	models, err := s.q.ListEffectiveZoneModels(context.Background(), ListEffectiveZoneModelsParams{
+		OrganizationID: s.org.ID,
+		UserID:         u.ID,
+		Sport:          "running",
+		OnDate:         date,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(models, 3)
+	s.Equal(current.ID, models[0].ID)
+	s.Equal(old.ID, models[1].ID)
+	s.Equal(pace.ID, models[2].ID)
+
+	err = s.q.DeleteZoneModel(context.Background(), DeleteZoneModelParams{OrganizationID: s.org.ID, ID: current.ID})
+	s.Require().NoError(err)
+
+	models, err = s.q.ListZoneModelsByUser(context.Background(), ListZoneModelsByUserParams{OrganizationID: s.org.ID, UserID: u.ID})
+	s.Require().NoError(err)
+	s.Len(models, 3)
+}
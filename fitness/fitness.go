@@ -0,0 +1,109 @@
+// Package fitness derives physiological thresholds from the results of field tests.
+//
+// Distances are in meters, times in seconds, power in watts and heart rates in beats per minute.
+// Threshold paces are in seconds per km and CSS paces in seconds per 100m.
+package fitness
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rondondev/runapp/calc"
+)
+
+// Protocol is a field test protocol
+type Protocol string
+
+// Supported protocols
+const (
+	// Cooper is the 12-minute run for the longest distance
+	Cooper Protocol = "cooper"
+	// TT30 is a 30-minute running time trial
+	TT30 Protocol = "tt30"
+	// FTP20 is a 20-minute all-out cycling effort
+	FTP20 Protocol = "ftp20"
+	// CSS is a 400m and a 200m swim time trial
+	CSS Protocol = "css"
+)
+
+// TT30Duration is the duration of the TT30 test
+const TT30Duration = 30 * 60
+
+// ErrInvalidInput is returned when the input misses a value required by the protocol
+var ErrInvalidInput = errors.New("invalid test input")
+
+// Input is the raw result of a test, each protocol uses some of the values
+type Input struct {
+	// Distance covered by the Cooper and TT30 tests
+	Distance int
+	// AvgPower of the FTP20 test
+	AvgPower int
+	// AvgHR is the optional average heart rate of the TT30 and FTP20 tests
+	AvgHR int
+	// Time400 and Time200 of the CSS test
+	Time400 int
+	Time200 int
+}
+
+// Result holds the values derived from a test, a zero value wasn't derived by the protocol
+type Result struct {
+	VO2max        float64
+	ThresholdPace int
+	FTP           int
+	CSS           int
+	LTHR          int
+}
+
+// Calculate derives the thresholds from the input of a test
+func Calculate(protocol Protocol, in Input) (Result, error) {
+	var result Result
+
+	switch protocol {
+	case Cooper:
+		if in.Distance <= 0 {
+			return result, fmt.Errorf("%w: the cooper test requires a distance", ErrInvalidInput)
+		}
+		result.VO2max = CooperVO2max(in.Distance)
+	case TT30:
+		if in.Distance <= 0 {
+			return result, fmt.Errorf("%w: the tt30 test requires a distance", ErrInvalidInput)
+		}
+		result.ThresholdPace = Pace(in.Distance, TT30Duration)
+		result.LTHR = in.AvgHR
+	case FTP20:
+		if in.AvgPower <= 0 {
+			return result, fmt.Errorf("%w: the ftp20 test requires an average power", ErrInvalidInput)
+		}
+		result.FTP = round(0.95 * float64(in.AvgPower))
+		result.LTHR = round(0.95 * float64(in.AvgHR))
+	case CSS:
+		if in.Time200 <= 0 || in.Time400 <= in.Time200 {
+			return result, fmt.Errorf("%w: the css test requires a 400m time slower than the 200m time", ErrInvalidInput)
+		}
+		result.CSS = CriticalSwimSpeed(in.Time400, in.Time200)
+	default:
+		return result, fmt.Errorf("%w: unknown protocol %q", ErrInvalidInput, protocol)
+	}
+
+	return result, nil
+}
+
+// CooperVO2max estimates the VO2max in ml/kg/min from the distance run in 12 minutes
+func CooperVO2max(distance int) float64 {
+	return math.Round((float64(distance)-504.9)/44.73*10) / 10
+}
+
+// Pace returns the pace in seconds per km of a distance covered in the given time
+func Pace(distance, seconds int) int {
+	return round(calc.PacePerKm(calc.Speed(float64(distance), float64(seconds))))
+}
+
+// CriticalSwimSpeed returns the CSS pace in seconds per 100m from 400m and 200m times
+func CriticalSwimSpeed(time400, time200 int) int {
+	return round(float64(time400-time200) / 2)
+}
+
+func round(f float64) int {
+	return int(math.Round(f))
+}
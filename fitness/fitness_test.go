@@ -0,0 +1,39 @@
+package fitness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		protocol Protocol
+		input    Input
+		valid    bool
+		want     Result
+	}{
+		{"cooper", Cooper, Input{Distance: 2800}, true, Result{VO2max: 51.3}},
+		{"cooper without distance", Cooper, Input{}, false, Result{}},
+		{"tt30", TT30, Input{Distance: 7500, AvgHR: 172}, true, Result{ThresholdPace: 240, LTHR: 172}},
+		{"tt30 without hr", TT30, Input{Distance: 6000}, true, Result{ThresholdPace: 300}},
+		{"ftp20", FTP20, Input{AvgPower: 280, AvgHR: 170}, true, Result{FTP: 266, LTHR: 162}},
+		{"ftp20 without power", FTP20, Input{AvgHR: 170}, false, Result{}},
+		{"css", CSS, Input{Time400: 360, Time200: 170}, true, Result{CSS: 95}},
+		{"css reversed", CSS, Input{Time400: 170, Time200: 360}, false, Result{}},
+		{"unknown", "ramp", Input{AvgPower: 300}, false, Result{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Calculate(tc.protocol, tc.input)
+			if !tc.valid {
+				require.ErrorIs(t, err, ErrInvalidInput)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, result)
+		})
+	}
+}
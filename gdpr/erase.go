@@ -0,0 +1,33 @@
+package gdpr
+
+import (
+	"context"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+
+	"github.com/emvi/null"
+)
+
+// Erasure audit values shared by the API and the command line
+const (
+	AuditActionExport = "export"
+	AuditActionErase  = "erase"
+	AuditEntityUser   = "user"
+)
+
+// Erase anonymizes the personal data of a user while keeping their trainings and feedbacks
+// for aggregated stats. The erasure is recorded in the audit log on behalf of the actor.
+func Erase(ctx context.Context, store db.Store, organizationID, userID int64, actorID null.Int64, requestID string) (db.User, error) {
+	arg := db.EraseUserTxParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Audit: db.CreateAuditLogParams{
+			ActorID:    actorID,
+			Action:     AuditActionErase,
+			EntityType: AuditEntityUser,
+			RequestID:  requestID,
+		},
+	}
+
+	return store.EraseUserTx(ctx, arg)
+}
@@ -0,0 +1,625 @@
+// Package gdpr builds the data exports and erasures required by data protection regulations.
+package gdpr
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+
+	"github.com/emvi/null"
+)
+
+// Data is everything stored about a user, the audit logs being the changes made by the user and the ones
+// made to their data
+type Data struct {
+	ExportedAt            time.Time                 `json:"exported_at"`
+	User                  db.User                   `json:"user"`
+	Trainings             []db.Training             `json:"trainings"`
+	TrainingLegs          []db.TrainingLeg          `json:"training_legs"`
+	TrainingFeedbacks     []db.TrainingFeedback     `json:"training_feedbacks"`
+	TrainingSeries        []db.TrainingSeries       `json:"training_series"`
+	ZoneModels            []db.ZoneModel            `json:"zone_models"`
+	TestResults           []db.TestResult           `json:"test_results"`
+	Races                 []db.Race                 `json:"races"`
+	Wellness              []db.Wellness             `json:"wellness"`
+	Injuries              []db.Injury               `json:"injuries"`
+	Availability          []db.Availability         `json:"availability"`
+	Blackouts             []db.Blackout             `json:"blackouts"`
+	Equipment             []db.Equipment            `json:"equipment"`
+	ExerciseLogs          []db.ExerciseLog          `json:"exercise_logs"`
+	ExercisePrescriptions []db.ExercisePrescription `json:"exercise_prescriptions"`
+	GroupMemberships      []db.GroupMember          `json:"group_memberships"`
+	AuditLogs             []db.AuditLog             `json:"audit_logs"`
+}
+
+// Collect loads everything tied to a user of an organization, including soft-deleted rows
+func Collect(ctx context.Context, q db.Querier, organizationID, userID int64) (Data, error) {
+	var data Data
+	var err error
+
+	data.User, err = q.GetUserIncludingDeleted(ctx, db.GetUserIncludingDeletedParams{
+		OrganizationID: organizationID,
+		ID:             userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.Trainings, err = q.ListAllTrainingsByUser(ctx, db.ListAllTrainingsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.TrainingLegs, err = q.ListAllTrainingLegsByUser(ctx, db.ListAllTrainingLegsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.TrainingFeedbacks, err = q.ListAllTrainingFeedbacksByUser(ctx, db.ListAllTrainingFeedbacksByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.TrainingSeries, err = q.ListAllTrainingSeriesByUser(ctx, db.ListAllTrainingSeriesByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.ZoneModels, err = q.ListAllZoneModelsByUser(ctx, db.ListAllZoneModelsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.TestResults, err = q.ListAllTestResultsByUser(ctx, db.ListAllTestResultsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.Races, err = q.ListAllRacesByUser(ctx, db.ListAllRacesByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.Wellness, err = q.ListAllWellnessByUser(ctx, db.ListAllWellnessByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.Injuries, err = q.ListAllInjuriesByUser(ctx, db.ListAllInjuriesByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.Availability, err = q.ListAvailabilityByUser(ctx, db.ListAvailabilityByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.Blackouts, err = q.ListBlackoutsByUser(ctx, db.ListBlackoutsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.Equipment, err = q.ListEquipmentByUser(ctx, db.ListEquipmentByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.ExerciseLogs, err = q.ListAllExerciseLogsByUser(ctx, db.ListAllExerciseLogsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.ExercisePrescriptions, err = q.ListAllExercisePrescriptionsByUser(ctx, db.ListAllExercisePrescriptionsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.GroupMemberships, err = q.ListAllGroupMembershipsByUser(ctx, db.ListAllGroupMembershipsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.AuditLogs, err = q.ListAllAuditLogsByUser(ctx, db.ListAllAuditLogsByUserParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return data, err
+	}
+
+	data.ExportedAt = time.Now().UTC()
+	return data, nil
+}
+
+// Write writes the data as a zip archive holding a JSON document and one CSV file per table
+func Write(w io.Writer, data Data) error {
+	z := zip.NewWriter(w)
+
+	f, err := z.Create("export.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(data); err != nil {
+		return err
+	}
+
+	users := [][]string{
+		{"id", "type", "name", "gender", "email", "phone", "birth", "active", "created_at", "deleted_at", "timezone",
+			"locale", "units"},
+		{
+			strconv.FormatInt(data.User.ID, 10),
+			string(data.User.Type),
+			data.User.Name,
+			formatString(data.User.Gender),
+			data.User.Email,
+			formatString(data.User.Phone),
+			formatDate(data.User.Birth),
+			strconv.FormatBool(data.User.Active),
+			data.User.CreatedAt.Format(time.RFC3339),
+			formatTime(data.User.DeletedAt),
+			data.User.Timezone,
+			data.User.Locale,
+			string(data.User.Units),
+		},
+	}
+	if err = writeCSV(z, "users.csv", users); err != nil {
+		return err
+	}
+
+	trainings := [][]string{
+		{"id", "user_id", "date", "sport", "type", "intensity", "details", "status", "created_at", "deleted_at", "series_id", "planned_duration",
+			"attributes"},
+	}
+	for _, t := range data.Trainings {
+		trainings = append(trainings, []string{
+			strconv.FormatInt(t.ID, 10),
+			strconv.FormatInt(t.UserID, 10),
+			t.Date.Format("2006-01-02"),
+			t.Sport,
+			formatString(t.Type),
+			formatString(t.Intensity),
+			t.Details,
+			string(t.Status),
+			t.CreatedAt.Format(time.RFC3339),
+			formatTime(t.DeletedAt),
+			formatInt(t.SeriesID),
+			formatInt32(t.PlannedDuration),
+			string(t.Attributes),
+		})
+	}
+	if err = writeCSV(z, "trainings.csv", trainings); err != nil {
+		return err
+	}
+
+	legs := [][]string{
+		{"id", "training_id", "position", "sport", "details", "planned_duration", "transition", "attributes", "duration",
+			"distance"},
+	}
+	for _, l := range data.TrainingLegs {
+		legs = append(legs, []string{
+			strconv.FormatInt(l.ID, 10),
+			strconv.FormatInt(l.TrainingID, 10),
+			strconv.FormatInt(int64(l.Position), 10),
+			l.Sport,
+			formatString(l.Details),
+			formatInt32(l.PlannedDuration),
+			formatInt32(l.Transition),
+			string(l.Attributes),
+			formatInt32(l.Duration),
+			formatInt32(l.Distance),
+		})
+	}
+	if err = writeCSV(z, "training_legs.csv", legs); err != nil {
+		return err
+	}
+
+	feedbacks := [][]string{
+		{"id", "training_id", "borg_scale", "duration", "distance", "avg_power", "avg_hr", "pain", "injury_id"},
+	}
+	for _, f := range data.TrainingFeedbacks {
+		feedbacks = append(feedbacks, []string{
+			strconv.FormatInt(f.ID, 10),
+			strconv.FormatInt(f.TrainingID, 10),
+			strconv.FormatInt(int64(f.BorgScale), 10),
+			formatInt32(f.Duration),
+			formatInt32(f.Distance),
+			formatInt32(f.AvgPower),
+			formatInt32(f.AvgHr),
+			formatInt32(f.Pain),
+			formatInt(f.InjuryID),
+		})
+	}
+	if err = writeCSV(z, "training_feedbacks.csv", feedbacks); err != nil {
+		return err
+	}
+
+	series := [][]string{
+		{"id", "user_id", "rrule", "dtstart", "exdate", "sport", "type", "intensity", "details", "created_at", "deleted_at"},
+	}
+	for _, s := range data.TrainingSeries {
+		series = append(series, []string{
+			strconv.FormatInt(s.ID, 10),
+			strconv.FormatInt(s.UserID, 10),
+			s.Rrule,
+			s.Dtstart.Format("2006-01-02"),
+			s.Exdate,
+			s.Sport,
+			formatString(s.Type),
+			formatString(s.Intensity),
+			s.Details,
+			s.CreatedAt.Format(time.RFC3339),
+			formatTime(s.DeletedAt),
+		})
+	}
+	if err = writeCSV(z, "training_series.csv", series); err != nil {
+		return err
+	}
+
+	zoneModels := [][]string{
+		{"id", "user_id", "sport", "method", "threshold", "resting", "effective_from", "created_at", "deleted_at"},
+	}
+	for _, m := range data.ZoneModels {
+		zoneModels = append(zoneModels, []string{
+			strconv.FormatInt(m.ID, 10),
+			strconv.FormatInt(m.UserID, 10),
+			m.Sport,
+			string(m.Method),
+			strconv.FormatInt(int64(m.Threshold), 10),
+			formatInt32(m.Resting),
+			m.EffectiveFrom.Format("2006-01-02"),
+			m.CreatedAt.Format(time.RFC3339),
+			formatTime(m.DeletedAt),
+		})
+	}
+	if err = writeCSV(z, "zone_models.csv", zoneModels); err != nil {
+		return err
+	}
+
+	testResults := [][]string{
+		{"id", "user_id", "training_id", "protocol", "distance", "avg_power", "avg_hr", "time_400m", "time_200m",
+			"vo2max", "threshold_pace", "ftp", "css", "lthr", "created_at"},
+	}
+	for _, r := range data.TestResults {
+		testResults = append(testResults, []string{
+			strconv.FormatInt(r.ID, 10),
+			strconv.FormatInt(r.UserID, 10),
+			strconv.FormatInt(r.TrainingID, 10),
+			string(r.Protocol),
+			formatInt32(r.Distance),
+			formatInt32(r.AvgPower),
+			formatInt32(r.AvgHr),
+			formatInt32(r.Time400m),
+			formatInt32(r.Time200m),
+			formatFloat(r.Vo2max),
+			formatInt32(r.ThresholdPace),
+			formatInt32(r.Ftp),
+			formatInt32(r.Css),
+			formatInt32(r.Lthr),
+			r.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "test_results.csv", testResults); err != nil {
+		return err
+	}
+
+	races := [][]string{
+		{"id", "user_id", "name", "date", "sport", "distance", "priority", "goal_time", "result_time", "created_at", "deleted_at"},
+	}
+	for _, r := range data.Races {
+		races = append(races, []string{
+			strconv.FormatInt(r.ID, 10),
+			strconv.FormatInt(r.UserID, 10),
+			r.Name,
+			r.Date.Format("2006-01-02"),
+			r.Sport,
+			strconv.FormatInt(int64(r.Distance), 10),
+			string(r.Priority),
+			formatInt32(r.GoalTime),
+			formatInt32(r.ResultTime),
+			r.CreatedAt.Format(time.RFC3339),
+			formatTime(r.DeletedAt),
+		})
+	}
+	if err = writeCSV(z, "races.csv", races); err != nil {
+		return err
+	}
+
+	wellness := [][]string{
+		{"id", "user_id", "date", "weight", "resting_hr", "hrv", "sleep_hours", "sleep_quality", "soreness", "stress",
+			"cycle_phase", "created_at"},
+	}
+	for _, w := range data.Wellness {
+		wellness = append(wellness, []string{
+			strconv.FormatInt(w.ID, 10),
+			strconv.FormatInt(w.UserID, 10),
+			w.Date.Format("2006-01-02"),
+			formatFloat(w.Weight),
+			formatInt32(w.RestingHr),
+			formatInt32(w.Hrv),
+			formatFloat(w.SleepHours),
+			formatInt32(w.SleepQuality),
+			formatInt32(w.Soreness),
+			formatInt32(w.Stress),
+			formatString(w.CyclePhase),
+			w.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "wellness.csv", wellness); err != nil {
+		return err
+	}
+
+	injuries := [][]string{
+		{"id", "user_id", "kind", "body_part", "diagnosis", "onset", "severity", "status", "expected_return", "created_at"},
+	}
+	for _, i := range data.Injuries {
+		injuries = append(injuries, []string{
+			strconv.FormatInt(i.ID, 10),
+			strconv.FormatInt(i.UserID, 10),
+			string(i.Kind),
+			formatString(i.BodyPart),
+			i.Diagnosis,
+			i.Onset.Format("2006-01-02"),
+			string(i.Severity),
+			string(i.Status),
+			formatDate(i.ExpectedReturn),
+			i.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "injuries.csv", injuries); err != nil {
+		return err
+	}
+
+	windows := [][]string{
+		{"id", "user_id", "weekday", "start_minute", "end_minute", "max_duration", "created_at"},
+	}
+	for _, a := range data.Availability {
+		windows = append(windows, []string{
+			strconv.FormatInt(a.ID, 10),
+			strconv.FormatInt(a.UserID, 10),
+			strconv.FormatInt(int64(a.Weekday), 10),
+			strconv.FormatInt(int64(a.StartMinute), 10),
+			strconv.FormatInt(int64(a.EndMinute), 10),
+			formatInt32(a.MaxDuration),
+			a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "availability.csv", windows); err != nil {
+		return err
+	}
+
+	blackouts := [][]string{
+		{"id", "user_id", "start_date", "end_date", "reason", "created_at"},
+	}
+	for _, b := range data.Blackouts {
+		blackouts = append(blackouts, []string{
+			strconv.FormatInt(b.ID, 10),
+			strconv.FormatInt(b.UserID, 10),
+			b.StartDate.Format("2006-01-02"),
+			b.EndDate.Format("2006-01-02"),
+			formatString(b.Reason),
+			b.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "blackouts.csv", blackouts); err != nil {
+		return err
+	}
+
+	equipment := [][]string{
+		{"id", "user_id", "type", "brand", "model", "start_date", "threshold_distance", "threshold_duration", "retired_on",
+			"created_at"},
+	}
+	for _, e := range data.Equipment {
+		equipment = append(equipment, []string{
+			strconv.FormatInt(e.ID, 10),
+			strconv.FormatInt(e.UserID, 10),
+			string(e.Type),
+			e.Brand,
+			e.Model,
+			e.StartDate.Format("2006-01-02"),
+			formatInt32(e.ThresholdDistance),
+			formatInt32(e.ThresholdDuration),
+			formatDate(e.RetiredOn),
+			e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "equipment.csv", equipment); err != nil {
+		return err
+	}
+
+	exerciseLogs := [][]string{
+		{"id", "training_id", "exercise_id", "prescription_id", "set_number", "reps", "weight", "rpe", "created_at"},
+	}
+	for _, l := range data.ExerciseLogs {
+		exerciseLogs = append(exerciseLogs, []string{
+			strconv.FormatInt(l.ID, 10),
+			strconv.FormatInt(l.TrainingID, 10),
+			strconv.FormatInt(l.ExerciseID, 10),
+			formatInt(l.PrescriptionID),
+			strconv.FormatInt(int64(l.SetNumber), 10),
+			strconv.FormatInt(int64(l.Reps), 10),
+			strconv.FormatFloat(l.Weight, 'f', -1, 64),
+			formatFloat(l.Rpe),
+			l.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "exercise_logs.csv", exerciseLogs); err != nil {
+		return err
+	}
+
+	prescriptions := [][]string{
+		{"id", "training_id", "exercise_id", "position", "sets", "reps", "percent_1rm", "rpe", "rest", "notes", "created_at"},
+	}
+	for _, p := range data.ExercisePrescriptions {
+		prescriptions = append(prescriptions, []string{
+			strconv.FormatInt(p.ID, 10),
+			strconv.FormatInt(p.TrainingID, 10),
+			strconv.FormatInt(p.ExerciseID, 10),
+			strconv.FormatInt(int64(p.Position), 10),
+			strconv.FormatInt(int64(p.Sets), 10),
+			strconv.FormatInt(int64(p.Reps), 10),
+			formatFloat(p.Percent1rm),
+			formatFloat(p.Rpe),
+			formatInt32(p.Rest),
+			formatString(p.Notes),
+			p.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "exercise_prescriptions.csv", prescriptions); err != nil {
+		return err
+	}
+
+	memberships := [][]string{
+		{"group_id", "user_id", "created_at"},
+	}
+	for _, m := range data.GroupMemberships {
+		memberships = append(memberships, []string{
+			strconv.FormatInt(m.GroupID, 10),
+			strconv.FormatInt(m.UserID, 10),
+			m.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "group_memberships.csv", memberships); err != nil {
+		return err
+	}
+
+	auditLogs := [][]string{
+		{"id", "actor_id", "action", "entity_type", "entity_id", "before", "after", "request_id", "created_at"},
+	}
+	for _, l := range data.AuditLogs {
+		auditLogs = append(auditLogs, []string{
+			strconv.FormatInt(l.ID, 10),
+			formatInt(l.ActorID),
+			l.Action,
+			l.EntityType,
+			strconv.FormatInt(l.EntityID, 10),
+			string(l.Before),
+			string(l.After),
+			l.RequestID,
+			l.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err = writeCSV(z, "audit_logs.csv", auditLogs); err != nil {
+		return err
+	}
+
+	return z.Close()
+}
+
+// Export collects everything tied to a user and writes it as a zip archive
+func Export(ctx context.Context, q db.Querier, organizationID, userID int64, w io.Writer) error {
+	data, err := Collect(ctx, q, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	return Write(w, data)
+}
+
+func writeCSV(z *zip.Writer, name string, records [][]string) error {
+	f, err := z.Create(name)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if err = w.WriteAll(records); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func formatString(s null.String) string {
+	if !s.Valid {
+		return ""
+	}
+	return s.String
+}
+
+func formatInt(i null.Int64) string {
+	if !i.Valid {
+		return ""
+	}
+	return strconv.FormatInt(i.Int64, 10)
+}
+
+func formatInt32(i null.Int32) string {
+	if !i.Valid {
+		return ""
+	}
+	return strconv.FormatInt(int64(i.Int32), 10)
+}
+
+func formatFloat(f null.Float64) string {
+	if !f.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(f.Float64, 'f', -1, 64)
+}
+
+func formatDate(t null.Time) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02")
+}
+
+func formatTime(t null.Time) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.RFC3339)
+}
@@ -0,0 +1,98 @@
+package gdpr
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/sports"
+
+	"github.com/emvi/null"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	data := Data{
+		ExportedAt: time.Now().UTC(),
+		User: db.User{
+			ID:     1,
+			Type:   db.UserTypeAthlete,
+			Name:   "Athlete",
+			Gender: null.NewString(string(db.GenderTypeF), true),
+			Email:  "athlete@example.com",
+			Phone:  null.NewString("12345678", true),
+		},
+		Trainings: []db.Training{
+			{ID: 10, UserID: 1, Sport: sports.Running, Details: "10 km, easy", Status: db.TrainingStatusDone},
+			{ID: 11, UserID: 1, Sport: sports.Swimming, Details: "2 km", Status: db.TrainingStatusNew},
+		},
+		TrainingFeedbacks: []db.TrainingFeedback{
+			{ID: 100, TrainingID: 10, BorgScale: 13},
+		},
+		ZoneModels: []db.ZoneModel{
+			{ID: 1000, UserID: 1, Sport: sports.Running, Method: db.ZoneMethodHrKarvonen, Threshold: 190, Resting: null.NewInt32(50, true)},
+		},
+		Wellness: []db.Wellness{
+			{ID: 2000, UserID: 1, Weight: null.NewFloat64(61.5, true), Hrv: null.NewInt32(72, true)},
+		},
+		ExerciseLogs: []db.ExerciseLog{
+			{ID: 3000, TrainingID: 10, ExerciseID: 1, SetNumber: 1, Reps: 5, Weight: 80, Rpe: null.NewFloat64(8, true)},
+		},
+		ExercisePrescriptions: []db.ExercisePrescription{
+			{ID: 4000, TrainingID: 10, ExerciseID: 1, Position: 1, Sets: 3, Reps: 5, Percent1rm: null.NewFloat64(80, true)},
+		},
+		GroupMemberships: []db.GroupMember{
+			{GroupID: 5, UserID: 1},
+		},
+		AuditLogs: []db.AuditLog{
+			{ID: 6000, ActorID: null.NewInt64(1, true), Action: "update", EntityType: "training", EntityID: 10,
+				Before: []byte(`{"id": 10}`), After: []byte(`{"id": 10}`)},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data))
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	rows := map[string]int{}
+	for _, f := range r.File {
+		if f.Name == "export.json" {
+			rows[f.Name] = 1
+			continue
+		}
+
+		rc, err := f.Open()
+		require.NoError(t, err)
+		records, err := csv.NewReader(rc).ReadAll()
+		require.NoError(t, err)
+		rc.Close()
+
+		rows[f.Name] = len(records)
+	}
+
+	require.Equal(t, map[string]int{
+		"export.json":                1,
+		"users.csv":                  2,
+		"trainings.csv":              3,
+		"training_legs.csv":          1,
+		"training_feedbacks.csv":     2,
+		"training_series.csv":        1,
+		"zone_models.csv":            2,
+		"test_results.csv":           1,
+		"races.csv":                  1,
+		"wellness.csv":               2,
+		"injuries.csv":               1,
+		"availability.csv":           1,
+		"blackouts.csv":              1,
+		"equipment.csv":              1,
+		"exercise_logs.csv":          2,
+		"exercise_prescriptions.csv": 2,
+		"group_memberships.csv":      2,
+		"audit_logs.csv":             2,
+	}, rows)
+}
@@ -5,7 +5,7 @@ go 1.14
 require (
 	github.com/emvi/null v0.0.0-20210117151026-1bf8abf21c69
 	github.com/gin-gonic/gin v1.6.3
-	github.com/go-playground/validator/v10 v10.4.1 // indirect
+	github.com/go-playground/validator/v10 v10.4.1
 	github.com/golang/protobuf v1.4.3 // indirect
 	github.com/jaswdr/faker v1.3.0
 	github.com/json-iterator/go v1.1.10 // indirect
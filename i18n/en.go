@@ -0,0 +1,90 @@
+package i18n
+
+// en is the reference catalog, every other catalog has the same keys
+var en = catalog{
+	// validation messages get the field and the parameter of the rule
+	"validation.invalid":          "%[1]s is invalid",
+	"validation.required":         "%[1]s is required",
+	"validation.required_without": "%[1]s is required without %[2]s",
+	"validation.min":              "%[1]s must be at least %[2]s",
+	"validation.max":              "%[1]s must be at most %[2]s",
+	"validation.gt":               "%[1]s must be greater than %[2]s",
+	"validation.oneof":            "%[1]s must be one of: %[2]s",
+	"validation.datetime":         "%[1]s must be a date like %[2]s",
+	"validation.timezone":         "%[1]s must be a timezone like America/Sao_Paulo",
+	"validation.url":              "%[1]s must be a URL",
+
+	"request.invalid_id":       "invalid %s %d",
+	"request.invalid_field":    "invalid %s",
+	"request.invalid_tz":       "invalid tz: %v",
+	"request.end_before_start": "end_date must not be before start_date",
+	"request.period_too_long":  "the period is too long",
+	"precondition.required":    "the If-Match header with the ETag of the version being edited is required",
+	"precondition.failed":      "it was changed in the meantime, reload it and try again",
+	"patch.unsupported_type":   "unsupported patch type %s, send application/merge-patch+json or application/json-patch+json",
+	"patch.invalid":            "invalid patch: %v",
+	"patch.test_failed":        "a test operation of the patch doesn't match the current values",
+	"auth.missing_token":       "the Authorization header with a bearer token is required",
+	"auth.invalid_token":       "invalid or revoked token",
+	"actor.missing":            "missing actor",
+	"actor.admin_only":         "admin only",
+	"user.invalid_locale":      "the locale must be a language tag like en or pt-BR",
+	"gdpr.export_forbidden":    "not allowed to export this user",
+	"trash.user_deleted":       "the user of this training is deleted",
+	"trash.no_retention":       "no retention configured, older_than is required",
+	"csv.empty":                "the CSV must have a header and at least one row",
+	"csv.too_many_rows":        "the CSV can't have more than %d rows",
+	"csv.missing_column":       "missing column %q",
+	"csv.missing_athlete":      "missing athlete",
+	"csv.unknown_athlete":      "unknown athlete %q",
+	"csv.inactive_athlete":     "%q is not an active athlete",
+	"csv.invalid_number":       "invalid %s %q",
+	"csv.invalid_attributes":   "the attributes must be a JSON object",
+	"csv.multisport":           "multisport trainings can't be imported",
+	"sport.invalid_slug":       "the slug must be lowercase letters, digits and underscores",
+	"sport.exists":             "the sport %s already exists",
+	"sport.built_in":           "the built-in sports can't be changed",
+	"sport.unknown":            "unknown sport %s",
+	"sport.invalid_metrics":    "invalid metrics: %s",
+	"sport.invalid_attributes": "invalid attributes: %s",
+	"leg.not_multisport":       "only multisport trainings can have legs",
+	"leg.min":                  "a multisport training needs at least %d legs",
+	"leg.invalid_sport":        "invalid sport %s for leg %d",
+	"leg.invalid":              "leg %d: %v",
+	"leg.first_transition":     "the first leg can't have a transition",
+	"leg.none":                 "only multisport trainings have legs",
+	"leg.count":                "the training has %d legs",
+	"series.no_occurrences":    "the rule has no occurrences",
+	"series.multisport":        "a series can't be multisport",
+	"series.invalid_rule":      "invalid recurrence rule: %s",
+	"series.invalid_date":      "invalid date %s",
+	"series.too_many_dates":    "the rule has too many occurrences",
+	"series.shift_ordinal":     "the trainings of a rule with ordinal weekdays can't be moved together",
+	"group.athletes_only":      "only athletes can be group members",
+	"group.attributes":         "group trainings can't have attributes",
+	"group.multisport":         "group trainings can't be multisport",
+	"group.coach_type":         "coach_id must be a coach or an admin",
+	"fitness.wrong_sport":      "the %s test must be recorded on a %s training",
+	"fitness.invalid_input":    "invalid test input: %s",
+	"zone.method_sport":        "the %s method doesn't apply to %s",
+	"zone.invalid_model":       "invalid zone model: %s",
+	"zone.invalid_ref":         "invalid zone reference: %s",
+	"race.no_results":          "no running race results to predict from",
+	"tools.two_required":       "two of distance, time and pace are required",
+	"tools.too_slow":           "the result is too slow to estimate a VDOT",
+	"exercise.load_conflict":   "only one of percent_1rm and rpe can be given",
+	"injury.expected_return":   "expected_return must be after onset",
+	"injury.resolved":          "the injury is resolved",
+	"injury.no_return":         "the trainings can only be rescheduled once the expected return is known",
+	"injury.days_short":        "days must be at least %d, to move the trainings past the expected return",
+	"wellness.exists":          "there is already a check-in on this date",
+	"availability.end_minute":  "end_minute must be after start_minute",
+	"equipment.retired_on":     "retired_on must not be before start_date",
+	"equipment.not_completed":  "equipment can only be attached to completed trainings",
+
+	// notification templates, executed with the data of the notification
+	"notification.equipment": "Your {{.Equipment}} reached {{.Value}} {{.Unit}}, past its threshold of {{.Threshold}} {{.Unit}}. Time to check or replace it.",
+	"unit.km":                "km",
+	"unit.mi":                "mi",
+	"unit.hours":             "hours",
+}
@@ -0,0 +1,87 @@
+package i18n
+
+var es = catalog{
+	"validation.invalid":          "%[1]s no es válido",
+	"validation.required":         "%[1]s es obligatorio",
+	"validation.required_without": "%[1]s es obligatorio sin %[2]s",
+	"validation.min":              "%[1]s debe ser al menos %[2]s",
+	"validation.max":              "%[1]s debe ser como máximo %[2]s",
+	"validation.gt":               "%[1]s debe ser mayor que %[2]s",
+	"validation.oneof":            "%[1]s debe ser uno de: %[2]s",
+	"validation.datetime":         "%[1]s debe ser una fecha como %[2]s",
+	"validation.timezone":         "%[1]s debe ser una zona horaria como America/Madrid",
+	"validation.url":              "%[1]s debe ser una URL",
+
+	"request.invalid_id":       "%s %d no válido",
+	"request.invalid_field":    "%s no válido",
+	"request.invalid_tz":       "tz no válida: %v",
+	"request.end_before_start": "end_date no puede ser anterior a start_date",
+	"request.period_too_long":  "el período es demasiado largo",
+	"precondition.required":    "se requiere el encabezado If-Match con el ETag de la versión que se edita",
+	"precondition.failed":      "fue modificado mientras tanto, vuelve a cargarlo e inténtalo de nuevo",
+	"patch.unsupported_type":   "tipo de patch no soportado %s, envía application/merge-patch+json o application/json-patch+json",
+	"patch.invalid":            "patch inválido: %v",
+	"patch.test_failed":        "una operación test del patch no coincide con los valores actuales",
+	"auth.missing_token":       "se requiere el encabezado Authorization con un token bearer",
+	"auth.invalid_token":       "token no válido o revocado",
+	"actor.missing":            "falta el usuario",
+	"actor.admin_only":         "solo para administradores",
+	"user.invalid_locale":      "el idioma debe ser una etiqueta como es o pt-BR",
+	"gdpr.export_forbidden":    "no tienes permiso para exportar este usuario",
+	"trash.user_deleted":       "el usuario de este entrenamiento está eliminado",
+	"trash.no_retention":       "no hay retención configurada, older_than es obligatorio",
+	"csv.empty":                "el CSV debe tener una cabecera y al menos una fila",
+	"csv.too_many_rows":        "el CSV no puede tener más de %d filas",
+	"csv.missing_column":       "falta la columna %q",
+	"csv.missing_athlete":      "falta el atleta",
+	"csv.unknown_athlete":      "atleta desconocido %q",
+	"csv.inactive_athlete":     "%q no es un atleta activo",
+	"csv.invalid_number":       "%s inválido %q",
+	"csv.invalid_attributes":   "los atributos deben ser un objeto JSON",
+	"csv.multisport":           "los entrenamientos multideporte no se pueden importar",
+	"sport.invalid_slug":       "el slug debe tener letras minúsculas, dígitos y guiones bajos",
+	"sport.exists":             "el deporte %s ya existe",
+	"sport.built_in":           "los deportes predefinidos no se pueden cambiar",
+	"sport.unknown":            "deporte desconocido %s",
+	"sport.invalid_metrics":    "métricas inválidas: %s",
+	"sport.invalid_attributes": "atributos inválidos: %s",
+	"leg.not_multisport":       "solo los entrenamientos multideporte pueden tener segmentos",
+	"leg.min":                  "un entrenamiento multideporte necesita al menos %d segmentos",
+	"leg.invalid_sport":        "deporte %s no válido para el segmento %d",
+	"leg.invalid":              "segmento %d: %v",
+	"leg.first_transition":     "el primer segmento no puede tener transición",
+	"leg.none":                 "solo los entrenamientos multideporte tienen segmentos",
+	"leg.count":                "el entrenamiento tiene %d segmentos",
+	"series.no_occurrences":    "la regla no tiene ocurrencias",
+	"series.multisport":        "una serie no puede ser multideporte",
+	"series.invalid_rule":      "regla de recurrencia inválida: %s",
+	"series.invalid_date":      "fecha inválida %s",
+	"series.too_many_dates":    "la regla tiene demasiadas ocurrencias",
+	"series.shift_ordinal":     "los entrenamientos de una regla con días ordinales no se pueden mover juntos",
+	"group.athletes_only":      "solo los atletas pueden ser miembros de un grupo",
+	"group.attributes":         "los entrenamientos de grupo no pueden tener atributos",
+	"group.multisport":         "los entrenamientos de grupo no pueden ser multideporte",
+	"group.coach_type":         "coach_id debe ser un entrenador o un administrador",
+	"fitness.wrong_sport":      "la prueba %s debe registrarse en un entrenamiento de %s",
+	"fitness.invalid_input":    "datos de la prueba inválidos: %s",
+	"zone.method_sport":        "el método %s no se aplica a %s",
+	"zone.invalid_model":       "modelo de zonas inválido: %s",
+	"zone.invalid_ref":         "referencia de zona inválida: %s",
+	"race.no_results":          "no hay resultados de carreras a pie para predecir",
+	"tools.two_required":       "se requieren dos de distancia, tiempo y ritmo",
+	"tools.too_slow":           "el resultado es demasiado lento para estimar un VDOT",
+	"exercise.load_conflict":   "solo se puede indicar uno de percent_1rm y rpe",
+	"injury.expected_return":   "expected_return debe ser posterior a onset",
+	"injury.resolved":          "la lesión está resuelta",
+	"injury.no_return":         "los entrenamientos solo se pueden reprogramar con una fecha de regreso prevista",
+	"injury.days_short":        "days debe ser al menos %d, para mover los entrenamientos después del regreso previsto",
+	"wellness.exists":          "ya hay un registro en esta fecha",
+	"availability.end_minute":  "end_minute debe ser posterior a start_minute",
+	"equipment.retired_on":     "retired_on no puede ser anterior a start_date",
+	"equipment.not_completed":  "el equipo solo se puede asociar a entrenamientos completados",
+
+	"notification.equipment": "Tu {{.Equipment}} llegó a {{.Value}} {{.Unit}}, superando su límite de {{.Threshold}} {{.Unit}}. Es hora de revisarlo o reemplazarlo.",
+	"unit.km":                "km",
+	"unit.mi":                "mi",
+	"unit.hours":             "horas",
+}
@@ -0,0 +1,191 @@
+// Package i18n holds the message catalogs of the API errors and notifications in the languages of the
+// athletes, and picks the language of a request from its Accept-Language header and the user locale.
+//
+// The messages are fmt formats, the notification ones text/template templates.
+package i18n
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// DefaultLanguage is used when none of the languages asked for is supported
+const DefaultLanguage = "en"
+
+type catalog map[string]string
+
+var catalogs = map[string]catalog{
+	"en": en,
+	"es": es,
+	"pt": pt,
+}
+
+// Languages returns the supported languages
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Negotiate returns the supported language that best matches an Accept-Language header, falling back to
+// the first supported one of the given locales, like the one of the user, and then to the default
+func Negotiate(acceptLanguage string, locales ...string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	for _, t := range tags {
+		if lang, ok := supported(t.tag); ok {
+			return lang
+		}
+	}
+	for _, locale := range locales {
+		if lang, ok := supported(locale); ok {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// supported returns the language of a tag like "pt-BR" if there is a catalog for it
+func supported(tag string) (string, bool) {
+	lang := strings.ToLower(strings.SplitN(strings.ReplaceAll(tag, "_", "-"), "-", 2)[0])
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// message returns the message of a key in the language, the default one if the catalog lacks it
+func message(lang, key string) (string, bool) {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg, true
+	}
+	msg, ok := catalogs[DefaultLanguage][key]
+	return msg, ok
+}
+
+// T formats the message of a key in the language. Args that are errors are translated as well.
+func T(lang, key string, args ...interface{}) string {
+	msg, ok := message(lang, key)
+	if !ok {
+		return key
+	}
+
+	translated := make([]interface{}, len(args))
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = Translate(lang, err)
+		}
+		translated[i] = arg
+	}
+	return fmt.Sprintf(msg, translated...)
+}
+
+// Render executes the notification template of a key in the language
+func Render(lang, key string, data interface{}) (string, error) {
+	msg, ok := message(lang, key)
+	if !ok {
+		return "", fmt.Errorf("unknown template %s", key)
+	}
+
+	tmpl, err := template.New(key).Parse(msg)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Error is an error whose message is in the catalogs
+type Error struct {
+	Key  string
+	Args []interface{}
+}
+
+// Errorf returns the error of a message key, formatted with the args in the language of each request
+func Errorf(key string, args ...interface{}) error {
+	return &Error{Key: key, Args: args}
+}
+
+// Error returns the message in the default language
+func (e *Error) Error() string {
+	return T(DefaultLanguage, e.Key, e.Args...)
+}
+
+// wrappedKeys are the keys of the messages of the errors of other packages
+var wrappedKeys = map[error]string{}
+
+// RegisterError gives the errors that wrap err, like the sentinel errors of a package, the message of
+// key. The message gets the detail the error adds to err, if any.
+func RegisterError(err error, key string) {
+	wrappedKeys[err] = key
+}
+
+// Translate returns the message of an error in the language. Validation errors have one message per
+// field, registered errors the message of their key with their detail, and errors that aren't from the
+// catalogs keep their own message.
+func Translate(lang string, err error) string {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		msgs := make([]string, len(validationErrs))
+		for i, fe := range validationErrs {
+			key := "validation." + fe.Tag()
+			if _, ok := message(lang, key); !ok {
+				key = "validation.invalid"
+			}
+			msgs[i] = T(lang, key, fe.Field(), fe.Param())
+		}
+		return strings.Join(msgs, "; ")
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return T(lang, e.Key, e.Args...)
+	}
+
+	for wrapped, key := range wrappedKeys {
+		if errors.Is(err, wrapped) {
+			detail := strings.TrimPrefix(err.Error(), wrapped.Error())
+			if detail == "" {
+				return T(lang, key)
+			}
+			return T(lang, key, strings.TrimSpace(strings.TrimPrefix(detail, ":")))
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[a-z]`)
+
+func TestCatalogsHaveEveryKey(t *testing.T) {
+	for lang, c := range catalogs {
+		for key := range en {
+			require.Contains(t, c, key, "%s lacks %s", lang, key)
+		}
+		for key := range c {
+			require.Contains(t, en, key, "%s has the unknown key %s", lang, key)
+		}
+	}
+}
+
+func TestCatalogsFormats(t *testing.T) {
+	for lang, c := range catalogs {
+		for key, msg := range c {
+			require.NotEmpty(t, msg, "%s %s", lang, key)
+			if strings.HasPrefix(key, "notification.") {
+				_, err := template.New(key).Parse(msg)
+				require.NoError(t, err, "%s %s", lang, key)
+				continue
+			}
+
+			// the translations take the same args
+			verbs := verbPattern.FindAllString(msg, -1)
+			want := verbPattern.FindAllString(en[key], -1)
+			sort.Strings(verbs)
+			sort.Strings(want)
+			require.Equal(t, want, verbs, "%s %s", lang, key)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	require.Equal(t, "en", Negotiate(""))
+	require.Equal(t, "pt", Negotiate("pt-BR,pt;q=0.9,en;q=0.8"))
+	require.Equal(t, "es", Negotiate("fr-FR, es;q=0.7, en;q=0.5"))
+	require.Equal(t, "en", Negotiate("es;q=0.5, en"))
+	require.Equal(t, "en", Negotiate("es;q=0, en;q=0.1"))
+	// the user locale is used when no language of the header is supported
+	require.Equal(t, "pt", Negotiate("fr, *", "pt-BR"))
+	require.Equal(t, "es", Negotiate("", "es"))
+	require.Equal(t, "en", Negotiate("de", "fr"))
+	require.Equal(t, []string{"en", "es", "pt"}, Languages())
+}
+
+func TestTranslate(t *testing.T) {
+	err := Errorf("leg.invalid", 2, Errorf("sport.unknown", "rowing"))
+	require.Equal(t, "leg 2: unknown sport rowing", err.Error())
+	require.Equal(t, "etapa 2: esporte desconhecido rowing", Translate("pt", err))
+	require.Equal(t, "segmento 2: deporte desconocido rowing", Translate("es", fmt.Errorf("wrapped: %w", err)))
+	require.Equal(t, "plain", Translate("pt", fmt.Errorf("plain")))
+	require.Equal(t, "missing.key", T("es", "missing.key"))
+
+	// the errors of other packages get the message of their key with their detail
+	errInvalid := errors.New("invalid zone model")
+	RegisterError(errInvalid, "zone.invalid_model")
+	require.Equal(t, "modelo de zonas inválido: threshold must be positive",
+		Translate("es", fmt.Errorf("%w: threshold must be positive", errInvalid)))
+	errTooMany := errors.New("rrule: more than 730 occurrences")
+	RegisterError(errTooMany, "series.too_many_dates")
+	require.Equal(t, "a regra tem ocorrências demais", Translate("pt", errTooMany))
+
+	type request struct {
+		Name   string `validate:"required"`
+		Period string `validate:"oneof=week month"`
+		Code   string `validate:"uuid"`
+	}
+	err = validator.New().Struct(request{Period: "day", Code: "x"})
+	require.Error(t, err)
+	require.Equal(t, "Name é obrigatório; Period deve ser um de: week month; Code é inválido", Translate("pt", err))
+}
+
+func TestRender(t *testing.T) {
+	data := map[string]interface{}{"Equipment": "Pegasus", "Value": 812, "Threshold": 800, "Unit": T("pt", "unit.km")}
+	msg, err := Render("pt", "notification.equipment", data)
+	require.NoError(t, err)
+	require.Equal(t, "Seu Pegasus chegou a 812 km, passando do limite de 800 km. Hora de revisar ou trocar.", msg)
+
+	_, err = Render("en", "notification.unknown", data)
+	require.Error(t, err)
+}
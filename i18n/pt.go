@@ -0,0 +1,87 @@
+package i18n
+
+var pt = catalog{
+	"validation.invalid":          "%[1]s é inválido",
+	"validation.required":         "%[1]s é obrigatório",
+	"validation.required_without": "%[1]s é obrigatório sem %[2]s",
+	"validation.min":              "%[1]s deve ser no mínimo %[2]s",
+	"validation.max":              "%[1]s deve ser no máximo %[2]s",
+	"validation.gt":               "%[1]s deve ser maior que %[2]s",
+	"validation.oneof":            "%[1]s deve ser um de: %[2]s",
+	"validation.datetime":         "%[1]s deve ser uma data como %[2]s",
+	"validation.timezone":         "%[1]s deve ser um fuso horário como America/Sao_Paulo",
+	"validation.url":              "%[1]s deve ser uma URL",
+
+	"request.invalid_id":       "%s %d inválido",
+	"request.invalid_field":    "%s inválido",
+	"request.invalid_tz":       "tz inválido: %v",
+	"request.end_before_start": "end_date não pode ser anterior a start_date",
+	"request.period_too_long":  "o período é longo demais",
+	"precondition.required":    "o cabeçalho If-Match com o ETag da versão editada é obrigatório",
+	"precondition.failed":      "foi alterado nesse meio tempo, recarregue e tente novamente",
+	"patch.unsupported_type":   "tipo de patch não suportado %s, envie application/merge-patch+json ou application/json-patch+json",
+	"patch.invalid":            "patch inválido: %v",
+	"patch.test_failed":        "uma operação test do patch não confere com os valores atuais",
+	"auth.missing_token":       "o cabeçalho Authorization com um token bearer é obrigatório",
+	"auth.invalid_token":       "token inválido ou revogado",
+	"actor.missing":            "usuário não informado",
+	"actor.admin_only":         "somente administradores",
+	"user.invalid_locale":      "o idioma deve ser uma tag como pt-BR ou en",
+	"gdpr.export_forbidden":    "sem permissão para exportar este usuário",
+	"trash.user_deleted":       "o usuário deste treino foi excluído",
+	"trash.no_retention":       "nenhuma retenção configurada, older_than é obrigatório",
+	"csv.empty":                "o CSV deve ter um cabeçalho e ao menos uma linha",
+	"csv.too_many_rows":        "o CSV não pode ter mais de %d linhas",
+	"csv.missing_column":       "coluna %q ausente",
+	"csv.missing_athlete":      "atleta não informado",
+	"csv.unknown_athlete":      "atleta desconhecido %q",
+	"csv.inactive_athlete":     "%q não é um atleta ativo",
+	"csv.invalid_number":       "%s inválido %q",
+	"csv.invalid_attributes":   "os atributos devem ser um objeto JSON",
+	"csv.multisport":           "treinos multiesporte não podem ser importados",
+	"sport.invalid_slug":       "o slug deve ter letras minúsculas, dígitos e sublinhados",
+	"sport.exists":             "o esporte %s já existe",
+	"sport.built_in":           "os esportes padrão não podem ser alterados",
+	"sport.unknown":            "esporte desconhecido %s",
+	"sport.invalid_metrics":    "métricas inválidas: %s",
+	"sport.invalid_attributes": "atributos inválidos: %s",
+	"leg.not_multisport":       "somente treinos multiesporte podem ter etapas",
+	"leg.min":                  "um treino multiesporte precisa de ao menos %d etapas",
+	"leg.invalid_sport":        "esporte %s inválido para a etapa %d",
+	"leg.invalid":              "etapa %d: %v",
+	"leg.first_transition":     "a primeira etapa não pode ter transição",
+	"leg.none":                 "somente treinos multiesporte têm etapas",
+	"leg.count":                "o treino tem %d etapas",
+	"series.no_occurrences":    "a regra não tem ocorrências",
+	"series.multisport":        "uma série não pode ser multiesporte",
+	"series.invalid_rule":      "regra de recorrência inválida: %s",
+	"series.invalid_date":      "data inválida %s",
+	"series.too_many_dates":    "a regra tem ocorrências demais",
+	"series.shift_ordinal":     "os treinos de uma regra com dias ordinais não podem ser movidos juntos",
+	"group.athletes_only":      "somente atletas podem ser membros de um grupo",
+	"group.attributes":         "treinos de grupo não podem ter atributos",
+	"group.multisport":         "treinos de grupo não podem ser multiesporte",
+	"group.coach_type":         "coach_id deve ser um treinador ou um administrador",
+	"fitness.wrong_sport":      "o teste %s deve ser registrado em um treino de %s",
+	"fitness.invalid_input":    "dados do teste inválidos: %s",
+	"zone.method_sport":        "o método %s não se aplica a %s",
+	"zone.invalid_model":       "modelo de zonas inválido: %s",
+	"zone.invalid_ref":         "referência de zona inválida: %s",
+	"race.no_results":          "nenhum resultado de corrida para fazer a previsão",
+	"tools.two_required":       "dois entre distância, tempo e pace são obrigatórios",
+	"tools.too_slow":           "o resultado é lento demais para estimar um VDOT",
+	"exercise.load_conflict":   "somente um entre percent_1rm e rpe pode ser informado",
+	"injury.expected_return":   "expected_return deve ser posterior a onset",
+	"injury.resolved":          "a lesão está resolvida",
+	"injury.no_return":         "os treinos só podem ser reagendados com uma previsão de retorno",
+	"injury.days_short":        "days deve ser ao menos %d, para mover os treinos para depois do retorno previsto",
+	"wellness.exists":          "já existe um registro nesta data",
+	"availability.end_minute":  "end_minute deve ser posterior a start_minute",
+	"equipment.retired_on":     "retired_on não pode ser anterior a start_date",
+	"equipment.not_completed":  "o equipamento só pode ser associado a treinos concluídos",
+
+	"notification.equipment": "Seu {{.Equipment}} chegou a {{.Value}} {{.Unit}}, passando do limite de {{.Threshold}} {{.Unit}}. Hora de revisar ou trocar.",
+	"unit.km":                "km",
+	"unit.mi":                "mi",
+	"unit.hours":             "horas",
+}
@@ -0,0 +1,150 @@
+// Package load computes the training stress score (TSS) of sessions and the performance
+// management chart built from the daily scores: the chronic training load (fitness), the
+// acute training load (fatigue) and the training stress balance (form).
+//
+// Durations are in seconds, distances in meters, power in watts and heart rates in beats per
+// minute. Threshold paces are in seconds per km and CSS paces in seconds per 100m.
+package load
+
+import (
+	"math"
+	"time"
+
+	"github.com/rondondev/runapp/calc"
+	"github.com/rondondev/runapp/zones"
+)
+
+// Sport of a session, matching the training sports
+type Sport string
+
+// Sports with a specific stress score
+const (
+	Running  Sport = "running"
+	Cycling  Sport = "cycling"
+	Swimming Sport = "swimming"
+)
+
+// Time constants of the chart in days
+const (
+	CTLDays = 42
+	ATLDays = 7
+)
+
+// referenceBorg is the Borg rating considered as a threshold effort
+const referenceBorg = 15
+
+// defaultIntensity is the intensity factor of a planned session without a zone reference
+const defaultIntensity = 0.75
+
+// zoneIntensities are the intensity factors of planned sessions by zone, starting with Z1
+var zoneIntensities = []float64{0.65, 0.75, 0.85, 0.95, 1.05}
+
+// Session is the actual data of a completed session, a zero value is unknown
+type Session struct {
+	Duration int
+	Distance int
+	AvgPower int
+	AvgHR    int
+	// Borg is the perceived exertion from 6 to 20
+	Borg int
+}
+
+// Thresholds of the athlete on the day of the session, a zero value is unknown
+type Thresholds struct {
+	FTP  int
+	Pace int
+	CSS  int
+	LTHR int
+}
+
+// TSS returns the stress score of a session using the most precise data available: power,
+// pace, heart rate and finally the perceived exertion. It returns 0 without a duration.
+func TSS(sport Sport, s Session, t Thresholds) float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	hours := float64(s.Duration) / 3600
+
+	switch {
+	case sport == Cycling && s.AvgPower > 0 && t.FTP > 0:
+		return score(hours, float64(s.AvgPower)/float64(t.FTP), 2)
+	case sport == Running && s.Distance > 0 && t.Pace > 0:
+		pace := calc.PacePerKm(calc.Speed(float64(s.Distance), float64(s.Duration)))
+		return score(hours, float64(t.Pace)/pace, 2)
+	case sport == Swimming && s.Distance > 0 && t.CSS > 0:
+		pace := float64(s.Duration) / float64(s.Distance) * 100
+		return score(hours, float64(t.CSS)/pace, 3)
+	case s.AvgHR > 0 && t.LTHR > 0:
+		return score(hours, float64(s.AvgHR)/float64(t.LTHR), 2)
+	case s.Borg > 0:
+		return score(hours, float64(s.Borg)/referenceBorg, 2)
+	}
+
+	return 0
+}
+
+// PlannedTSS estimates the stress score of a planned session from its duration and the zone
+// reference of its intensity, like "Z2" or "Z3-Z4"
+func PlannedTSS(duration int, intensity string) float64 {
+	if duration <= 0 {
+		return 0
+	}
+
+	factor := defaultIntensity
+	if first, last, err := zones.ParseRef(intensity); err == nil {
+		factor = (zoneIntensity(first) + zoneIntensity(last)) / 2
+	}
+
+	return score(float64(duration)/3600, factor, 2)
+}
+
+func zoneIntensity(zone int) float64 {
+	if zone > len(zoneIntensities) {
+		return zoneIntensities[len(zoneIntensities)-1]
+	}
+	return zoneIntensities[zone-1]
+}
+
+func score(hours, intensity, exponent float64) float64 {
+	return hours * math.Pow(intensity, exponent) * 100
+}
+
+// Day is a point of the performance management chart
+type Day struct {
+	Date time.Time `json:"date"`
+	TSS  float64   `json:"tss"`
+	CTL  float64   `json:"ctl"`
+	ATL  float64   `json:"atl"`
+	// TSB is the form at the start of the day, the CTL minus the ATL of the day before
+	TSB       float64 `json:"tsb"`
+	Projected bool    `json:"projected"`
+}
+
+// Chart computes the chart of consecutive days from start, loads holding the TSS of each day.
+// The first warmUp days only seed the averages and are not returned.
+func Chart(start time.Time, loads []float64, warmUp int) []Day {
+	if warmUp > len(loads) {
+		warmUp = len(loads)
+	}
+
+	days := make([]Day, 0, len(loads)-warmUp)
+	var ctl, atl float64
+	for i, tss := range loads {
+		tsb := ctl - atl
+		ctl += (tss - ctl) / CTLDays
+		atl += (tss - atl) / ATLDays
+		if i < warmUp {
+			continue
+		}
+
+		days = append(days, Day{
+			Date: start.AddDate(0, 0, i),
+			TSS:  calc.Round(tss, 1),
+			CTL:  calc.Round(ctl, 1),
+			ATL:  calc.Round(atl, 1),
+			TSB:  calc.Round(tsb, 1),
+		})
+	}
+
+	return days
+}
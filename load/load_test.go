@@ -0,0 +1,72 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTSS(t *testing.T) {
+	thresholds := Thresholds{FTP: 250, Pace: 300, CSS: 100, LTHR: 160}
+
+	testCases := []struct {
+		name       string
+		sport      Sport
+		session    Session
+		thresholds Thresholds
+		want       float64
+	}{
+		{"power", Cycling, Session{Duration: 3600, AvgPower: 250, AvgHR: 120}, thresholds, 100},
+		{"pace", Running, Session{Duration: 3000, Distance: 10000}, thresholds, 83.33},
+		{"swim", Swimming, Session{Duration: 1000, Distance: 1000}, thresholds, 27.78},
+		{"heart rate", Running, Session{Duration: 3600, AvgHR: 144}, thresholds, 81},
+		{"borg", Cycling, Session{Duration: 3600, Borg: 15}, thresholds, 100},
+		{"borg without thresholds", Running, Session{Duration: 1800, Distance: 6000, Borg: 12}, Thresholds{}, 32},
+		{"without duration", Cycling, Session{AvgPower: 250, Borg: 15}, thresholds, 0},
+		{"without data", Running, Session{Duration: 3600}, thresholds, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.InDelta(t, tc.want, TSS(tc.sport, tc.session, tc.thresholds), 0.01)
+		})
+	}
+}
+
+func TestPlannedTSS(t *testing.T) {
+	require.InDelta(t, 56.25, PlannedTSS(3600, "Z2"), 0.01)
+	require.InDelta(t, 81, PlannedTSS(3600, "z3-z4"), 0.01)
+	require.InDelta(t, 110.25, PlannedTSS(3600, "Z7"), 0.01)
+	require.InDelta(t, 28.13, PlannedTSS(1800, "easy"), 0.01)
+	require.Zero(t, PlannedTSS(0, "Z2"))
+}
+
+func TestChart(t *testing.T) {
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	days := Chart(start, []float64{100, 0}, 0)
+	require.Len(t, days, 2)
+	require.Equal(t, Day{Date: start, TSS: 100, CTL: 2.4, ATL: 14.3, TSB: 0}, days[0])
+	require.Equal(t, Day{Date: start.AddDate(0, 0, 1), TSS: 0, CTL: 2.3, ATL: 12.2, TSB: -11.9}, days[1])
+
+	// the warm up days are not returned but seed the averages
+	days = Chart(start, []float64{100, 0}, 1)
+	require.Len(t, days, 1)
+	require.Equal(t, start.AddDate(0, 0, 1), days[0].Date)
+	require.Equal(t, -11.9, days[0].TSB)
+
+	// a constant load converges to itself
+	loads := make([]float64, 365)
+	for i := range loads {
+		loads[i] = 80
+	}
+	days = Chart(start, loads, 300)
+	require.Len(t, days, 65)
+	last := days[len(days)-1]
+	require.InDelta(t, 80, last.CTL, 0.1)
+	require.InDelta(t, 80, last.ATL, 0.1)
+	require.InDelta(t, 0, last.TSB, 0.1)
+
+	require.Empty(t, Chart(start, nil, 42))
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/rondondev/runapp/api"
 	db "github.com/rondondev/runapp/db/sqlc"
+	"github.com/rondondev/runapp/gdpr"
 	"github.com/rondondev/runapp/util"
 
+	"github.com/emvi/null"
 	_ "github.com/lib/pq"
 )
 
@@ -24,11 +30,196 @@ func main() {
 		log.Fatal("cannot connect to db: ", err)
 	}
 
-	store := db.New(conn)
-	server := api.NewServer(store)
+	store := db.NewStore(conn)
+
+	if len(os.Args) > 1 {
+		runCommand(store, os.Args[1], os.Args[2:])
+		return
+	}
+
+	server := api.NewServer(config, store)
+
+	if config.RetentionInterval > 0 {
+		go server.RunRetention()
+	}
 
 	err = server.Start(config.ServerAddress)
 	if err != nil {
 		log.Fatal("cannot start server: ", err)
 	}
 }
+
+// runCommand runs the maintenance commands available from the command line
+func runCommand(store db.Store, name string, args []string) {
+	switch name {
+	case "export", "erase":
+		runUserCommand(store, name, args)
+	case "bootstrap":
+		runBootstrap(store, args)
+	case "token":
+		runTokenCommand(store, args)
+	default:
+		log.Fatalf("unknown command %q, expected export, erase, bootstrap or token", name)
+	}
+}
+
+func runUserCommand(store db.Store, name string, args []string) {
+	cmd := flag.NewFlagSet(name, flag.ExitOnError)
+	organizationID := cmd.Int64("org", 0, "id of the organization of the user")
+	userID := cmd.Int64("user", 0, "id of the user")
+	out := cmd.String("out", "", "export file, defaults to user-<id>-export.zip")
+	_ = cmd.Parse(args)
+
+	if *organizationID < 1 {
+		log.Fatal("a valid -org is required")
+	}
+	if *userID < 1 {
+		log.Fatal("a valid -user is required")
+	}
+
+	ctx := context.Background()
+	switch name {
+	case "export":
+		if *out == "" {
+			*out = fmt.Sprintf("user-%d-export.zip", *userID)
+		}
+
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal("cannot create export file: ", err)
+		}
+		defer f.Close()
+
+		if err = gdpr.Export(ctx, store, *organizationID, *userID, f); err != nil {
+			log.Fatal("cannot export user: ", err)
+		}
+
+		_, err = store.CreateAuditLog(ctx, db.CreateAuditLogParams{
+			OrganizationID: *organizationID,
+			Action:         gdpr.AuditActionExport,
+			EntityType:     gdpr.AuditEntityUser,
+			EntityID:       *userID,
+			Before:         []byte("null"),
+			After:          []byte("null"),
+			RequestID:      "cli",
+		})
+		if err != nil {
+			log.Fatal("cannot audit export: ", err)
+		}
+
+		fmt.Println("user exported to", *out)
+	case "erase":
+		if _, err := gdpr.Erase(ctx, store, *organizationID, *userID, null.Int64{}, "cli"); err != nil {
+			log.Fatal("cannot erase user: ", err)
+		}
+
+		fmt.Println("user erased")
+	}
+}
+
+// runBootstrap creates a new organization together with its first admin
+func runBootstrap(store db.Store, args []string) {
+	cmd := flag.NewFlagSet("bootstrap", flag.ExitOnError)
+	name := cmd.String("name", "", "name of the organization")
+	slug := cmd.String("slug", "", "unique slug of the organization")
+	adminName := cmd.String("admin-name", "", "name of the admin")
+	adminEmail := cmd.String("admin-email", "", "email of the admin")
+	adminGender := cmd.String("admin-gender", "M", "gender of the admin, M or F")
+	_ = cmd.Parse(args)
+
+	if *name == "" || *slug == "" {
+		log.Fatal("-name and -slug are required")
+	}
+	if *adminName == "" || *adminEmail == "" {
+		log.Fatal("-admin-name and -admin-email are required")
+	}
+	if *adminGender != string(db.GenderTypeM) && *adminGender != string(db.GenderTypeF) {
+		log.Fatal("-admin-gender must be M or F")
+	}
+
+	ctx := context.Background()
+	result, err := store.CreateOrganizationTx(ctx, db.CreateOrganizationTxParams{
+		Organization: db.CreateOrganizationParams{
+			Name:     *name,
+			Slug:     *slug,
+			Settings: []byte("{}"),
+		},
+		Admin: db.CreateUserParams{
+			Name:   *adminName,
+			Email:  *adminEmail,
+			Gender: null.NewString(*adminGender, true),
+			// the admin can change their preferences once signed in
+			Timezone: "UTC",
+			Locale:   "en",
+			Units:    db.UnitSystemMetric,
+		},
+	})
+	if err != nil {
+		log.Fatal("cannot bootstrap organization: ", err)
+	}
+
+	after, err := json.Marshal(result)
+	if err != nil {
+		log.Fatal("cannot audit bootstrap: ", err)
+	}
+	_, err = store.CreateAuditLog(ctx, db.CreateAuditLogParams{
+		OrganizationID: result.Organization.ID,
+		Action:         "create",
+		EntityType:     "organization",
+		EntityID:       result.Organization.ID,
+		Before:         []byte("null"),
+		After:          after,
+		RequestID:      "cli",
+	})
+	if err != nil {
+		log.Fatal("cannot audit bootstrap: ", err)
+	}
+
+	_, token, err := api.IssueToken(ctx, store, result.Organization.ID, result.Admin.ID)
+	if err != nil {
+		log.Fatal("cannot issue token: ", err)
+	}
+
+	fmt.Printf("organization %d created with admin %d\n", result.Organization.ID, result.Admin.ID)
+	fmt.Println("token of the admin, keep it safe as it can't be shown again:", token)
+}
+
+// runTokenCommand issues a new API token for a user, like when the admin lost theirs
+func runTokenCommand(store db.Store, args []string) {
+	cmd := flag.NewFlagSet("token", flag.ExitOnError)
+	organizationID := cmd.Int64("org", 0, "id of the organization of the user")
+	userID := cmd.Int64("user", 0, "id of the user")
+	_ = cmd.Parse(args)
+
+	if *organizationID < 1 {
+		log.Fatal("a valid -org is required")
+	}
+	if *userID < 1 {
+		log.Fatal("a valid -user is required")
+	}
+
+	ctx := context.Background()
+	if _, err := store.GetUser(ctx, db.GetUserParams{OrganizationID: *organizationID, ID: *userID}); err != nil {
+		log.Fatal("cannot find user: ", err)
+	}
+
+	apiToken, token, err := api.IssueToken(ctx, store, *organizationID, *userID)
+	if err != nil {
+		log.Fatal("cannot issue token: ", err)
+	}
+
+	_, err = store.CreateAuditLog(ctx, db.CreateAuditLogParams{
+		OrganizationID: *organizationID,
+		Action:         "create",
+		EntityType:     "api_token",
+		EntityID:       apiToken.ID,
+		Before:         []byte("null"),
+		After:          []byte("null"),
+		RequestID:      "cli",
+	})
+	if err != nil {
+		log.Fatal("cannot audit token: ", err)
+	}
+
+	fmt.Println("token of the user, keep it safe as it can't be shown again:", token)
+}
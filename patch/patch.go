@@ -0,0 +1,377 @@
+// Package patch applies JSON Merge Patches (RFC 7396) and JSON Patches (RFC 6902) to JSON documents.
+//
+// The API turns an entity into the body of its PUT request, patches it and validates the result as if it
+// had been sent whole, so a patch only changes the fields it names and null clears a field.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Media types of the patch formats
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// ErrTestFailed is returned when a test operation of a JSON Patch doesn't match the document
+var ErrTestFailed = errors.New("test operation failed")
+
+// Apply applies a patch of the media type to a document
+func Apply(mediaType string, doc, patch []byte) ([]byte, error) {
+	switch mediaType {
+	case MergePatchType:
+		return Merge(doc, patch)
+	case JSONPatchType:
+		return JSONPatch(doc, patch)
+	}
+	return nil, fmt.Errorf("unsupported patch type %s", mediaType)
+}
+
+// Merge applies a JSON Merge Patch to a document: the members of the patch replace the ones of the
+// document, objects are merged recursively and null removes a member
+func Merge(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = merge(t[k], v)
+	}
+	return t
+}
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// JSONPatch applies a JSON Patch, a list of add, remove, replace, move, copy and test operations, to a
+// document. The operations are applied in order and none is when one fails.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		target, err = apply(target, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func apply(doc interface{}, op operation) (interface{}, error) {
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		// a null value is kept as null, only a missing one is empty
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%s needs a value", op.Op)
+		}
+		var err error
+		if value, err = decode(op.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "replace":
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, errors.New("a value can't be moved into itself")
+			}
+			doc, value, err = remove(doc, from)
+		} else {
+			value, err = get(doc, from)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(value))
+	case "test":
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) in its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid path %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// index returns the array index of a token, up to the length of the array when end is allowed
+func index(token string, length int, end bool) (int, error) {
+	if end && token == "-" {
+		return length, nil
+	}
+	// leading zeros aren't allowed
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > length || (i == length && !end) {
+		return 0, fmt.Errorf("index %q out of range", token)
+	}
+	return i, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if doc, ok = v[token]; !ok {
+				return nil, fmt.Errorf("missing member %q", token)
+			}
+		case []interface{}:
+			i, err := index(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = v[i]
+		default:
+			return nil, fmt.Errorf("missing member %q", token)
+		}
+	}
+	return doc, nil
+}
+
+// add sets the value at the path, inserting it when the path is an array index
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		v[last] = value
+		return doc, nil
+	case []interface{}:
+		i, err := index(last, len(v), true)
+		if err != nil {
+			return nil, err
+		}
+		v = append(v, nil)
+		copy(v[i+1:], v[i:])
+		v[i] = value
+		return replaceParent(doc, path[:len(path)-1], v)
+	}
+	return nil, fmt.Errorf("can't add %q to a value", last)
+}
+
+// remove takes the value at the path out of the document, returning it
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		value, ok := v[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("missing member %q", last)
+		}
+		delete(v, last)
+		return doc, value, nil
+	case []interface{}:
+		i, err := index(last, len(v), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := v[i]
+		v = append(v[:i:i], v[i+1:]...)
+		doc, err = replaceParent(doc, path[:len(path)-1], v)
+		return doc, value, err
+	}
+	return nil, nil, fmt.Errorf("missing member %q", last)
+}
+
+// replaceParent sets the array at the path, as the arrays change when values are added or removed
+func replaceParent(doc interface{}, path []string, array []interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return array, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		v[last] = array
+	case []interface{}:
+		i, err := index(last, len(v), false)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = array
+	}
+	return doc, nil
+}
+
+// equal compares two values, the numbers by their value
+func equal(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, e := range av {
+			if f, ok := bv[k]; !ok || !equal(e, f) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = deepCopy(e)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = deepCopy(e)
+		}
+		return c
+	}
+	return value
+}
+
+// decode keeps the numbers as they are written so the integers don't lose precision
+func decode(data []byte) (interface{}, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,87 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const doc = `{"name":"Ana","phone":"123","birth":"1990-02-01","tags":["a","b"],"zones":{"z1":120,"z2":140}}`
+
+func TestMerge(t *testing.T) {
+	cases := []struct {
+		patch string
+		want  string
+	}{
+		{`{}`, doc},
+		{`{"name":"Bia"}`, `{"name":"Bia","phone":"123","birth":"1990-02-01","tags":["a","b"],"zones":{"z1":120,"z2":140}}`},
+		// null removes the member, arrays are replaced as a whole
+		{`{"phone":null,"tags":["c"]}`, `{"name":"Ana","birth":"1990-02-01","tags":["c"],"zones":{"z1":120,"z2":140}}`},
+		{`{"zones":{"z1":null,"z3":160}}`, `{"name":"Ana","phone":"123","birth":"1990-02-01","tags":["a","b"],"zones":{"z2":140,"z3":160}}`},
+	}
+
+	for _, c := range cases {
+		got, err := Merge([]byte(doc), []byte(c.patch))
+		require.NoError(t, err, c.patch)
+		require.JSONEq(t, c.want, string(got), c.patch)
+	}
+
+	_, err := Merge([]byte(doc), []byte(`{`))
+	require.Error(t, err)
+}
+
+func TestJSONPatch(t *testing.T) {
+	cases := []struct {
+		patch string
+		want  string
+	}{
+		{`[{"op":"replace","path":"/name","value":"Bia"}]`,
+			`{"name":"Bia","phone":"123","birth":"1990-02-01","tags":["a","b"],"zones":{"z1":120,"z2":140}}`},
+		{`[{"op":"remove","path":"/phone"},{"op":"add","path":"/tags/1","value":"x"},{"op":"add","path":"/tags/-","value":"z"}]`,
+			`{"name":"Ana","birth":"1990-02-01","tags":["a","x","b","z"],"zones":{"z1":120,"z2":140}}`},
+		{`[{"op":"replace","path":"/birth","value":null},{"op":"remove","path":"/tags/0"}]`,
+			`{"name":"Ana","phone":"123","birth":null,"tags":["b"],"zones":{"z1":120,"z2":140}}`},
+		{`[{"op":"move","from":"/zones/z1","path":"/zones/z0"},{"op":"copy","from":"/tags","path":"/copy"}]`,
+			`{"name":"Ana","phone":"123","birth":"1990-02-01","tags":["a","b"],"zones":{"z0":120,"z2":140},"copy":["a","b"]}`},
+		{`[{"op":"test","path":"/zones/z1","value":120.0},{"op":"replace","path":"/zones/z1","value":125}]`,
+			`{"name":"Ana","phone":"123","birth":"1990-02-01","tags":["a","b"],"zones":{"z1":125,"z2":140}}`},
+	}
+
+	for _, c := range cases {
+		got, err := JSONPatch([]byte(doc), []byte(c.patch))
+		require.NoError(t, err, c.patch)
+		require.JSONEq(t, c.want, string(got), c.patch)
+	}
+}
+
+func TestJSONPatchErrors(t *testing.T) {
+	_, err := JSONPatch([]byte(doc), []byte(`[{"op":"test","path":"/name","value":"Bia"}]`))
+	require.ErrorIs(t, err, ErrTestFailed)
+
+	for _, patch := range []string{
+		`[{"op":"replace","path":"/missing","value":1}]`,
+		`[{"op":"remove","path":"/tags/2"}]`,
+		`[{"op":"add","path":"/tags/01","value":1}]`,
+		`[{"op":"add","path":"name","value":1}]`,
+		`[{"op":"add","path":"/name"}]`,
+		`[{"op":"move","from":"/zones","path":"/zones/z3"}]`,
+		`[{"op":"rename","path":"/name"}]`,
+		`{"op":"remove","path":"/name"}`,
+	} {
+		_, err := JSONPatch([]byte(doc), []byte(patch))
+		require.Error(t, err, patch)
+	}
+}
+
+func TestApply(t *testing.T) {
+	got, err := Apply(MergePatchType, []byte(doc), []byte(`{"name":"Bia"}`))
+	require.NoError(t, err)
+	require.Contains(t, string(got), `"name":"Bia"`)
+
+	got, err = Apply(JSONPatchType, []byte(doc), []byte(`[{"op":"replace","path":"/name","value":"Bia"}]`))
+	require.NoError(t, err)
+	require.Contains(t, string(got), `"name":"Bia"`)
+
+	_, err = Apply("text/plain", []byte(doc), []byte(`{}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,399 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used to plan recurring trainings.
+//
+// Occurrences are whole days: DTSTART, UNTIL and EXDATE values are truncated to dates.
+// Supported parts are FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY, COUNT and UNTIL.
+// A rule must be bounded by COUNT or UNTIL.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences is the maximum number of occurrences a rule can expand to
+const MaxOccurrences = 730
+
+const dateLayout = "20060102"
+
+// Errors returned when parsing and expanding rules, wrapped along with what is wrong
+var (
+	ErrInvalidRule        = errors.New("rrule: invalid rule")
+	ErrInvalidDate        = errors.New("rrule: invalid date")
+	ErrTooManyOccurrences = fmt.Errorf("rrule: more than %d occurrences", MaxOccurrences)
+	ErrShiftOrdinal       = errors.New("rrule: ordinal weekdays can't be shifted")
+)
+
+// Frequency is the FREQ part of a rule
+type Frequency string
+
+// Supported frequencies
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// Day is a BYDAY entry. N is the ordinal of the weekday in the month (e.g. 1 for the first,
+// -1 for the last), it's only allowed with a monthly frequency and 0 means every such weekday.
+type Day struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []Day
+	Count    int
+	Until    time.Time
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,TH;COUNT=24".
+// The "RRULE:" prefix is optional.
+func Parse(s string) (Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	r := Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return Rule{}, fmt.Errorf("%w: invalid part %q", ErrInvalidRule, part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		if seen[key] {
+			return Rule{}, fmt.Errorf("%w: duplicated %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if r.Freq != Daily && r.Freq != Weekly && r.Freq != Monthly {
+				return Rule{}, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err != nil || r.Interval < 1 {
+				return Rule{}, fmt.Errorf("%w: invalid INTERVAL %q", ErrInvalidRule, value)
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err != nil || r.Count < 1 {
+				return Rule{}, fmt.Errorf("%w: invalid COUNT %q", ErrInvalidRule, value)
+			}
+		case "UNTIL":
+			r.Until, err = parseDate(value)
+			if err != nil {
+				return Rule{}, fmt.Errorf("%w: invalid UNTIL %q", ErrInvalidRule, value)
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				day, err := parseDay(d)
+				if err != nil {
+					return Rule{}, err
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "WKST":
+			if value != "MO" {
+				return Rule{}, fmt.Errorf("%w: unsupported WKST %q", ErrInvalidRule, value)
+			}
+		default:
+			return Rule{}, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, key)
+		}
+	}
+
+	if r.Freq == "" {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL can't be used together", ErrInvalidRule)
+	}
+	if r.Count == 0 && r.Until.IsZero() {
+		return Rule{}, fmt.Errorf("%w: COUNT or UNTIL is required", ErrInvalidRule)
+	}
+	if r.Count > MaxOccurrences {
+		return Rule{}, fmt.Errorf("%w: COUNT can't be greater than %d", ErrInvalidRule, MaxOccurrences)
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly {
+			return Rule{}, fmt.Errorf("%w: BYDAY ordinals are only allowed with FREQ=MONTHLY", ErrInvalidRule)
+		}
+	}
+
+	return r, nil
+}
+
+func parseDay(s string) (Day, error) {
+	if len(s) < 2 {
+		return Day{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, s)
+	}
+
+	wd, ok := weekdays[s[len(s)-2:]]
+	if !ok {
+		return Day{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, s)
+	}
+
+	day := Day{Weekday: wd}
+	if ord := s[:len(s)-2]; ord != "" {
+		n, err := strconv.Atoi(ord)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return Day{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, s)
+		}
+		day.N = n
+	}
+
+	return day, nil
+}
+
+// parseDate parses a DATE or DATE-TIME value, keeping only the date
+func parseDate(s string) (time.Time, error) {
+	if len(s) < len(dateLayout) {
+		return time.Time{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	}
+	if len(s) > len(dateLayout) && s[len(dateLayout)] != 'T' {
+		return time.Time{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	}
+	d, err := time.Parse(dateLayout, s[:len(dateLayout)])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	}
+	return d, nil
+}
+
+// String formats the rule back to its RFC 5545 representation, without the "RRULE:" prefix
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayNames[d.Weekday]
+			if d.N != 0 {
+				days[i] = strconv.Itoa(d.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format(dateLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseDates parses a comma separated list of dates, as used by EXDATE;VALUE=DATE
+func ParseDates(s string) ([]time.Time, error) {
+	var dates []time.Time
+	for _, d := range strings.Split(s, ",") {
+		d = strings.TrimSpace(d)
+		if d == "" {
+			continue
+		}
+		t, err := parseDate(d)
+		if err != nil {
+			return nil, err
+		}
+		dates = append(dates, t)
+	}
+	return dates, nil
+}
+
+// FormatDates formats dates as a sorted comma separated list, as used by EXDATE;VALUE=DATE
+func FormatDates(dates []time.Time) string {
+	sorted := make([]time.Time, len(dates))
+	copy(sorted, dates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	values := make([]string, 0, len(sorted))
+	for i, d := range sorted {
+		if i > 0 && sameDate(d, sorted[i-1]) {
+			continue
+		}
+		values = append(values, d.Format(dateLayout))
+	}
+	return strings.Join(values, ",")
+}
+
+// All expands the rule from dtstart, skipping the excluded dates. As in RFC 5545,
+// excluded dates still count towards COUNT.
+func (r Rule) All(dtstart time.Time, exdates []time.Time) ([]time.Time, error) {
+	start := truncate(dtstart)
+	excluded := map[string]bool{}
+	for _, d := range exdates {
+		excluded[d.Format(dateLayout)] = true
+	}
+
+	var dates []time.Time
+	generated := 0
+	emit := func(d time.Time) bool {
+		if d.Before(start) {
+			return true
+		}
+		if !r.Until.IsZero() && d.After(r.Until) {
+			return false
+		}
+		generated++
+		if !excluded[d.Format(dateLayout)] {
+			dates = append(dates, d)
+		}
+		return r.Count == 0 || generated < r.Count
+	}
+
+	for period := 0; ; period++ {
+		candidates := r.period(start, period)
+		if len(candidates) > 0 && !r.Until.IsZero() && candidates[0].After(r.Until) {
+			break
+		}
+		for _, d := range candidates {
+			if !emit(d) {
+				return dates, nil
+			}
+		}
+		if len(dates) > MaxOccurrences || period > MaxOccurrences*31 {
+			return nil, ErrTooManyOccurrences
+		}
+	}
+
+	return dates, nil
+}
+
+// period returns the sorted candidate dates of the n-th period after start
+func (r Rule) period(start time.Time, n int) []time.Time {
+	step := n * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		d := start.AddDate(0, 0, step)
+		if len(r.ByDay) == 0 || r.matchesWeekday(d) {
+			return []time.Time{d}
+		}
+		return nil
+	case Weekly:
+		// weeks start on monday
+		monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*step)
+		if len(r.ByDay) == 0 {
+			return []time.Time{monday.AddDate(0, 0, (int(start.Weekday())+6)%7)}
+		}
+		var dates []time.Time
+		for i := 0; i < 7; i++ {
+			d := monday.AddDate(0, 0, i)
+			if r.matchesWeekday(d) {
+				dates = append(dates, d)
+			}
+		}
+		return dates
+	case Monthly:
+		first := time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByDay) == 0 {
+			// months without the day of dtstart are skipped
+			d := first.AddDate(0, 0, start.Day()-1)
+			if d.Month() != first.Month() {
+				return nil
+			}
+			return []time.Time{d}
+		}
+		var dates []time.Time
+		for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+			if r.matchesMonthDay(d) {
+				dates = append(dates, d)
+			}
+		}
+		return dates
+	}
+
+	return nil
+}
+
+func (r Rule) matchesWeekday(d time.Time) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday == d.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchesMonthDay(d time.Time) bool {
+	nth := (d.Day()-1)/7 + 1
+	daysInMonth := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	nthFromEnd := -((daysInMonth-d.Day())/7 + 1)
+
+	for _, day := range r.ByDay {
+		if day.Weekday != d.Weekday() {
+			continue
+		}
+		if day.N == 0 || day.N == nth || day.N == nthFromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func sameDate(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// Shift moves the rule by a number of days, so that the occurrences of the shifted
+// rule from dtstart+days are the occurrences of the rule from dtstart moved by days.
+// This doesn't hold for weekly rules with an interval when days move across a week boundary.
+// Weekdays with an ordinal can't be shifted: the 1st Monday moved by a day isn't always
+// the 1st Tuesday, so ErrShiftOrdinal is returned for them.
+func (r Rule) Shift(days int) (Rule, error) {
+	if days == 0 {
+		return r, nil
+	}
+
+	shifted := r
+	if len(r.ByDay) > 0 {
+		shifted.ByDay = make([]Day, len(r.ByDay))
+		for i, d := range r.ByDay {
+			if d.N != 0 {
+				return Rule{}, ErrShiftOrdinal
+			}
+			shifted.ByDay[i] = Day{Weekday: time.Weekday(((int(d.Weekday)+days)%7 + 7) % 7)}
+		}
+	}
+	if !r.Until.IsZero() {
+		shifted.Until = r.Until.AddDate(0, 0, days)
+	}
+	return shifted, nil
+}
@@ -0,0 +1,201 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func format(dates []time.Time) []string {
+	out := make([]string, len(dates))
+	for i, d := range dates {
+		out[i] = d.Format("2006-01-02")
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rule  string
+		valid bool
+		str   string
+	}{
+		{"weekly", "RRULE:FREQ=WEEKLY;BYDAY=MO,TH;COUNT=24", true, "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=24"},
+		{"lower case", "freq=daily;interval=2;until=20211231", true, "FREQ=DAILY;INTERVAL=2;UNTIL=20211231"},
+		{"until date time", "FREQ=DAILY;UNTIL=20211231T235959Z", true, "FREQ=DAILY;UNTIL=20211231"},
+		{"monthly ordinal", "FREQ=MONTHLY;BYDAY=-1SU;COUNT=3", true, "FREQ=MONTHLY;BYDAY=-1SU;COUNT=3"},
+		{"empty", "", false, ""},
+		{"missing freq", "COUNT=3", false, ""},
+		{"unbounded", "FREQ=DAILY", false, ""},
+		{"count and until", "FREQ=DAILY;COUNT=3;UNTIL=20211231", false, ""},
+		{"unsupported freq", "FREQ=YEARLY;COUNT=3", false, ""},
+		{"invalid interval", "FREQ=DAILY;INTERVAL=0;COUNT=3", false, ""},
+		{"invalid day", "FREQ=WEEKLY;BYDAY=XX;COUNT=3", false, ""},
+		{"weekly ordinal", "FREQ=WEEKLY;BYDAY=1MO;COUNT=3", false, ""},
+		{"too many", "FREQ=DAILY;COUNT=10000", false, ""},
+		{"duplicated part", "FREQ=DAILY;COUNT=1;COUNT=2", false, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := Parse(tc.rule)
+			if !tc.valid {
+				require.ErrorIs(t, err, ErrInvalidRule)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.str, r.String())
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rule    string
+		dtstart string
+		exdates string
+		dates   []string
+	}{
+		{
+			name:    "daily",
+			rule:    "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			dtstart: "2021-01-30",
+			dates:   []string{"2021-01-30", "2021-02-01", "2021-02-03"},
+		},
+		{
+			name:    "daily by day until",
+			rule:    "FREQ=DAILY;BYDAY=SA,SU;UNTIL=20210110",
+			dtstart: "2021-01-01",
+			dates:   []string{"2021-01-02", "2021-01-03", "2021-01-09", "2021-01-10"},
+		},
+		{
+			name:    "weekly on dtstart weekday",
+			rule:    "FREQ=WEEKLY;COUNT=3",
+			dtstart: "2021-01-06",
+			dates:   []string{"2021-01-06", "2021-01-13", "2021-01-20"},
+		},
+		{
+			name:    "weekly by day starting mid week",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4",
+			dtstart: "2021-01-05",
+			dates:   []string{"2021-01-07", "2021-01-11", "2021-01-14", "2021-01-18"},
+		},
+		{
+			name:    "biweekly",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;UNTIL=20210201",
+			dtstart: "2021-01-05",
+			dates:   []string{"2021-01-05", "2021-01-19"},
+		},
+		{
+			name:    "exdates count towards count",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4",
+			dtstart: "2021-01-04",
+			exdates: "20210107,20210114",
+			dates:   []string{"2021-01-04", "2021-01-11"},
+		},
+		{
+			name:    "monthly skips short months",
+			rule:    "FREQ=MONTHLY;COUNT=3",
+			dtstart: "2021-01-31",
+			dates:   []string{"2021-01-31", "2021-03-31", "2021-05-31"},
+		},
+		{
+			name:    "monthly last sunday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1SU;COUNT=3",
+			dtstart: "2021-01-01",
+			dates:   []string{"2021-01-31", "2021-02-28", "2021-03-28"},
+		},
+		{
+			name:    "monthly first monday",
+			rule:    "FREQ=MONTHLY;BYDAY=1MO;UNTIL=20210331",
+			dtstart: "2021-01-01",
+			dates:   []string{"2021-01-04", "2021-02-01", "2021-03-01"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := Parse(tc.rule)
+			require.NoError(t, err)
+
+			exdates, err := ParseDates(tc.exdates)
+			require.NoError(t, err)
+
+			dates, err := r.All(date(tc.dtstart), exdates)
+			require.NoError(t, err)
+			require.Equal(t, tc.dates, format(dates))
+		})
+	}
+}
+
+func TestAllTooMany(t *testing.T) {
+	r, err := Parse("FREQ=DAILY;UNTIL=20301231")
+	require.NoError(t, err)
+
+	_, err = r.All(date("2021-01-01"), nil)
+	require.ErrorIs(t, err, ErrTooManyOccurrences)
+}
+
+func TestFormatDates(t *testing.T) {
+	dates := []time.Time{date("2021-02-01"), date("2021-01-01"), date("2021-02-01")}
+	require.Equal(t, "20210101,20210201", FormatDates(dates))
+
+	parsed, err := ParseDates("20210101, 20210201")
+	require.NoError(t, err)
+	require.Equal(t, []string{"2021-01-01", "2021-02-01"}, format(parsed))
+}
+
+func TestShift(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;BYDAY=MO,SA;UNTIL=20210131")
+	require.NoError(t, err)
+
+	dates, err := r.All(date("2021-01-04"), nil)
+	require.NoError(t, err)
+
+	shifted, err := r.Shift(2)
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=WEEKLY;BYDAY=WE,MO;UNTIL=20210202", shifted.String())
+
+	shiftedDates, err := shifted.All(date("2021-01-06"), nil)
+	require.NoError(t, err)
+	require.Len(t, shiftedDates, len(dates))
+	for i := range dates {
+		require.Equal(t, dates[i].AddDate(0, 0, 2), shiftedDates[i])
+	}
+
+	shifted, err = r.Shift(-1)
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=WEEKLY;BYDAY=SU,FR;UNTIL=20210130", shifted.String())
+}
+
+func TestShiftMonthlyOrdinal(t *testing.T) {
+	r, err := Parse("FREQ=MONTHLY;BYDAY=1MO;COUNT=3")
+	require.NoError(t, err)
+
+	// the first Monday of June 2021 is the 7th, moved by a day it's the second Tuesday
+	_, err = r.Shift(1)
+	require.ErrorIs(t, err, ErrShiftOrdinal)
+
+	// the rule is kept when the days don't move
+	shifted, err := r.Shift(0)
+	require.NoError(t, err)
+	require.Equal(t, r, shifted)
+
+	// without an ordinal the weekdays are shifted
+	r, err = Parse("FREQ=MONTHLY;BYDAY=MO;COUNT=3")
+	require.NoError(t, err)
+	shifted, err = r.Shift(1)
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=MONTHLY;BYDAY=TU;COUNT=3", shifted.String())
+}
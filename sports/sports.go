@@ -0,0 +1,159 @@
+// Package sports validates the sport-specific attributes of trainings against the metric schema
+// the sport catalogue defines for each sport, like the pool length for swimming or the sets and
+// repetitions for weight training.
+package sports
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+// Built-in sports of the catalogue, available to every organization
+const (
+	Running  = "running"
+	Cycling  = "cycling"
+	Swimming = "swimming"
+	Weight   = "weight"
+	// Multisport trainings are made of legs of other sports, like a bike and run brick
+	Multisport = "multisport"
+)
+
+// Types of the metric values
+const (
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeString  = "string"
+	TypeBoolean = "boolean"
+)
+
+var slugRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Errors returned by the validations, wrapped along with what is invalid
+var (
+	ErrInvalidSchema     = errors.New("invalid metrics")
+	ErrInvalidAttributes = errors.New("invalid attributes")
+)
+
+// ValidSlug reports whether s can identify a sport or a metric
+func ValidSlug(s string) bool {
+	return slugRegexp.MatchString(s)
+}
+
+// Metric describes an attribute trainings of a sport can have
+type Metric struct {
+	Key      string   `json:"key" binding:"required"`
+	Name     string   `json:"name" binding:"required"`
+	Type     string   `json:"type" binding:"required,oneof=integer number string boolean"`
+	Unit     string   `json:"unit,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// ValidateSchema checks that the metrics of a sport are consistent
+func ValidateSchema(metrics []Metric) error {
+	keys := make(map[string]bool, len(metrics))
+	for _, m := range metrics {
+		if !ValidSlug(m.Key) {
+			return fmt.Errorf("%w: invalid metric key %q", ErrInvalidSchema, m.Key)
+		}
+		if keys[m.Key] {
+			return fmt.Errorf("%w: duplicate metric key %q", ErrInvalidSchema, m.Key)
+		}
+		keys[m.Key] = true
+
+		switch m.Type {
+		case TypeInteger, TypeNumber:
+			if len(m.Values) > 0 {
+				return fmt.Errorf("%w: %s: only string metrics can have values", ErrInvalidSchema, m.Key)
+			}
+			if m.Min != nil && m.Max != nil && *m.Min > *m.Max {
+				return fmt.Errorf("%w: %s: min must not be greater than max", ErrInvalidSchema, m.Key)
+			}
+		case TypeString, TypeBoolean:
+			if m.Min != nil || m.Max != nil {
+				return fmt.Errorf("%w: %s: only numeric metrics can have a min or max", ErrInvalidSchema, m.Key)
+			}
+			if m.Type == TypeBoolean && len(m.Values) > 0 {
+				return fmt.Errorf("%w: %s: only string metrics can have values", ErrInvalidSchema, m.Key)
+			}
+		default:
+			return fmt.Errorf("%w: %s: unknown type %q", ErrInvalidSchema, m.Key, m.Type)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the attributes of a training against the metrics of its sport. The attributes are
+// decoded from JSON, so numbers are float64.
+func Validate(metrics []Metric, attributes map[string]interface{}) error {
+	known := make(map[string]bool, len(metrics))
+	for _, m := range metrics {
+		known[m.Key] = true
+
+		v, ok := attributes[m.Key]
+		if !ok || v == nil {
+			if m.Required {
+				return fmt.Errorf("%w: %s is required", ErrInvalidAttributes, m.Key)
+			}
+			continue
+		}
+		if err := m.validate(v); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidAttributes, m.Key, err)
+		}
+	}
+
+	for k := range attributes {
+		if !known[k] {
+			return fmt.Errorf("%w: unknown attribute %s", ErrInvalidAttributes, k)
+		}
+	}
+
+	return nil
+}
+
+func (m Metric) validate(v interface{}) error {
+	switch m.Type {
+	case TypeInteger, TypeNumber:
+		n, ok := v.(float64)
+		if !ok {
+			return errors.New("must be a number")
+		}
+		if m.Type == TypeInteger && n != math.Trunc(n) {
+			return errors.New("must be an integer")
+		}
+		if m.Min != nil && n < *m.Min {
+			return fmt.Errorf("must be at least %g", *m.Min)
+		}
+		if m.Max != nil && n > *m.Max {
+			return fmt.Errorf("must be at most %g", *m.Max)
+		}
+	case TypeString:
+		s, ok := v.(string)
+		if !ok {
+			return errors.New("must be a string")
+		}
+		if len(m.Values) > 0 && !contains(m.Values, s) {
+			return fmt.Errorf("must be one of %v", m.Values)
+		}
+	case TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return errors.New("must be a boolean")
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
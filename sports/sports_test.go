@@ -0,0 +1,83 @@
+package sports
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestValidateSchema(t *testing.T) {
+	testCases := []struct {
+		name    string
+		metrics []Metric
+		valid   bool
+	}{
+		{"empty", nil, true},
+		{"numeric range", []Metric{{Key: "pool_length", Type: TypeInteger, Min: float(10), Max: float(100)}}, true},
+		{"string values", []Metric{{Key: "stroke", Type: TypeString, Values: []string{"free", "back"}}}, true},
+		{"invalid key", []Metric{{Key: "Pool length", Type: TypeInteger}}, false},
+		{"duplicate key", []Metric{{Key: "sets", Type: TypeInteger}, {Key: "sets", Type: TypeNumber}}, false},
+		{"unknown type", []Metric{{Key: "sets", Type: "date"}}, false},
+		{"inverted range", []Metric{{Key: "sets", Type: TypeInteger, Min: float(5), Max: float(1)}}, false},
+		{"numeric values", []Metric{{Key: "sets", Type: TypeInteger, Values: []string{"1"}}}, false},
+		{"string range", []Metric{{Key: "stroke", Type: TypeString, Max: float(5)}}, false},
+		{"boolean values", []Metric{{Key: "indoor", Type: TypeBoolean, Values: []string{"yes"}}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSchema(tc.metrics)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidSchema)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	metrics := []Metric{
+		{Key: "pool_length", Type: TypeInteger, Min: float(10), Max: float(100), Required: true},
+		{Key: "stroke", Type: TypeString, Values: []string{"free", "back"}},
+		{Key: "open_water", Type: TypeBoolean},
+		{Key: "temperature", Type: TypeNumber},
+	}
+
+	testCases := []struct {
+		name       string
+		attributes string
+		valid      bool
+	}{
+		{"required only", `{"pool_length": 25}`, true},
+		{"all", `{"pool_length": 50, "stroke": "back", "open_water": false, "temperature": 26.5}`, true},
+		{"missing required", `{"stroke": "free"}`, false},
+		{"null required", `{"pool_length": null}`, false},
+		{"not an integer", `{"pool_length": 25.5}`, false},
+		{"below min", `{"pool_length": 5}`, false},
+		{"above max", `{"pool_length": 200}`, false},
+		{"not a number", `{"pool_length": "25"}`, false},
+		{"invalid value", `{"pool_length": 25, "stroke": "fly"}`, false},
+		{"not a boolean", `{"pool_length": 25, "open_water": "no"}`, false},
+		{"unknown attribute", `{"pool_length": 25, "lanes": 8}`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attributes map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(tc.attributes), &attributes))
+
+			err := Validate(metrics, attributes)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidAttributes)
+			}
+		})
+	}
+}
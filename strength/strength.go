@@ -0,0 +1,93 @@
+// Package strength estimates the one repetition maximum (1RM) of an exercise from the sets logged
+// by an athlete and the loads prescribed as a percentage of it.
+//
+// Loads are in kg and the rate of perceived exertion (RPE) is from 1 to 10, 10 meaning that no
+// other repetition could have been done.
+package strength
+
+import (
+	"math"
+	"time"
+)
+
+// MaxReps is the number of repetitions above which a set doesn't give a meaningful estimation
+const MaxReps = 12
+
+// RecentDays is the number of days the estimation of a 1RM is based on
+const RecentDays = 90
+
+// Set is a set logged by an athlete, an unknown RPE being 0
+type Set struct {
+	Date   time.Time
+	Reps   int
+	Weight float64
+	RPE    float64
+}
+
+// OneRepMax estimates the 1RM from a set with the Epley formula. The repetitions left in reserve
+// according to the RPE are added to the ones done. It returns false when the set has no weight or
+// too many repetitions.
+func OneRepMax(s Set) (float64, bool) {
+	reps := float64(s.Reps)
+	if s.RPE > 0 && s.RPE < 10 {
+		reps += 10 - s.RPE
+	}
+	if s.Weight <= 0 || s.Reps < 1 || reps > MaxReps {
+		return 0, false
+	}
+	if reps == 1 {
+		return s.Weight, true
+	}
+	return round(s.Weight * (1 + reps/30)), true
+}
+
+// Estimate is the best 1RM estimated on a day
+type Estimate struct {
+	Date      time.Time `json:"date"`
+	OneRepMax float64   `json:"one_rep_max"`
+}
+
+// History returns the best estimation of each day, sets being sorted by date
+func History(sets []Set) []Estimate {
+	var history []Estimate
+	for _, s := range sets {
+		orm, ok := OneRepMax(s)
+		if !ok {
+			continue
+		}
+
+		n := len(history)
+		if n > 0 && sameDay(history[n-1].Date, s.Date) {
+			history[n-1].OneRepMax = math.Max(history[n-1].OneRepMax, orm)
+			continue
+		}
+		history = append(history, Estimate{Date: s.Date, OneRepMax: orm})
+	}
+	return history
+}
+
+// Current returns the best estimation of the RecentDays before date
+func Current(history []Estimate, date time.Time) (float64, bool) {
+	from := date.AddDate(0, 0, -RecentDays)
+	var best float64
+	for _, e := range history {
+		if e.Date.Before(from) || e.Date.After(date) {
+			continue
+		}
+		best = math.Max(best, e.OneRepMax)
+	}
+	return best, best > 0
+}
+
+// Load returns the weight of a percentage of the 1RM, rounded to 0.5 kg
+func Load(oneRepMax, percent float64) float64 {
+	return math.Round(oneRepMax*percent/100*2) / 2
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
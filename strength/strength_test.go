@@ -0,0 +1,65 @@
+package strength
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOneRepMax(t *testing.T) {
+	testCases := []struct {
+		name  string
+		set   Set
+		valid bool
+		want  float64
+	}{
+		{"single", Set{Reps: 1, Weight: 140}, true, 140},
+		{"five reps", Set{Reps: 5, Weight: 100}, true, 116.7},
+		{"reps in reserve", Set{Reps: 3, Weight: 100, RPE: 8}, true, 116.7},
+		{"max effort", Set{Reps: 5, Weight: 100, RPE: 10}, true, 116.7},
+		{"too many reps", Set{Reps: 15, Weight: 50}, false, 0},
+		{"too many reps in reserve", Set{Reps: 10, Weight: 50, RPE: 6}, false, 0},
+		{"bodyweight", Set{Reps: 10}, false, 0},
+		{"no reps", Set{Weight: 100}, false, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orm, ok := OneRepMax(tc.set)
+			require.Equal(t, tc.valid, ok)
+			require.InDelta(t, tc.want, orm, 0.001)
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	day := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	sets := []Set{
+		{Date: day, Reps: 5, Weight: 100},
+		{Date: day, Reps: 1, Weight: 120},
+		{Date: day, Reps: 20, Weight: 60},
+		{Date: day.AddDate(0, 0, 3), Reps: 3, Weight: 110},
+	}
+
+	history := History(sets)
+	require.Equal(t, []Estimate{
+		{Date: day, OneRepMax: 120},
+		{Date: day.AddDate(0, 0, 3), OneRepMax: 121},
+	}, history)
+
+	orm, ok := Current(history, day.AddDate(0, 0, 10))
+	require.True(t, ok)
+	require.Equal(t, 121.0, orm)
+
+	// only the estimations of the recent days count
+	_, ok = Current(history, day.AddDate(0, 0, RecentDays+4))
+	require.False(t, ok)
+	_, ok = Current(history, day.AddDate(0, 0, -1))
+	require.False(t, ok)
+}
+
+func TestLoad(t *testing.T) {
+	require.Equal(t, 87.5, Load(120, 73))
+	require.Equal(t, 100.0, Load(125, 80))
+}
@@ -1,13 +1,19 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 // Config stores all configurations of the application.
 // The values are read by viper from a config file or env variables.
 type Config struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBSource      string `mapstructure:"DB_SOURCE"`
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	DBDriver          string        `mapstructure:"DB_DRIVER"`
+	DBSource          string        `mapstructure:"DB_SOURCE"`
+	ServerAddress     string        `mapstructure:"SERVER_ADDRESS"`
+	TrashRetention    time.Duration `mapstructure:"TRASH_RETENTION"`
+	RetentionInterval time.Duration `mapstructure:"RETENTION_INTERVAL"`
 }
 
 // LoadConfig reads configuration from file or env variables.
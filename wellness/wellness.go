@@ -0,0 +1,112 @@
+// Package wellness computes the readiness of an athlete from a daily check-in compared to
+// the rolling baseline of the previous check-ins.
+//
+// Heart rates are in beats per minute, the HRV is the rMSSD in milliseconds and the sleep
+// quality, soreness and stress are ratings from 1 to 5. For the sleep quality 5 is the best,
+// for the soreness and stress 5 is the worst.
+package wellness
+
+import (
+	"math"
+)
+
+// BaselineDays is the number of days before a check-in its baseline is computed from
+const BaselineDays = 28
+
+// MinBaseline is the number of values a baseline needs to be meaningful
+const MinBaseline = 3
+
+// targetSleep is the number of hours of sleep considered as a full night
+const targetSleep = 8
+
+// CheckIn holds the values of a daily check-in, a zero value is unknown
+type CheckIn struct {
+	RestingHR    int
+	HRV          int
+	SleepHours   float64
+	SleepQuality int
+	Soreness     int
+	Stress       int
+}
+
+// Readiness returns a score from 0 to 100 of the check-in, 75 being the usual state of the athlete.
+// Each value is compared to its mean over the baseline check-ins. The resting heart rate and HRV
+// are only scored with a baseline, the subjective values are scored on their own scale without
+// one. It returns false when the check-in has no value a score can be computed from.
+func Readiness(c CheckIn, baseline []CheckIn) (int, bool) {
+	var scores []float64
+
+	if hrv := mean(baseline, func(b CheckIn) float64 { return float64(b.HRV) }); c.HRV > 0 && hrv > 0 {
+		scores = append(scores, ratioScore(float64(c.HRV)/hrv))
+	}
+	if rhr := mean(baseline, func(b CheckIn) float64 { return float64(b.RestingHR) }); c.RestingHR > 0 && rhr > 0 {
+		// a lower resting heart rate is better
+		scores = append(scores, ratioScore(rhr/float64(c.RestingHR)))
+	}
+	if c.SleepHours > 0 {
+		if sleep := mean(baseline, func(b CheckIn) float64 { return b.SleepHours }); sleep > 0 {
+			scores = append(scores, ratioScore(c.SleepHours/sleep))
+		} else {
+			scores = append(scores, clamp(c.SleepHours/targetSleep*100))
+		}
+	}
+	if c.SleepQuality > 0 {
+		quality := mean(baseline, func(b CheckIn) float64 { return float64(b.SleepQuality) })
+		scores = append(scores, ratingScore(c.SleepQuality, quality))
+	}
+	// a lower soreness and stress are better
+	if c.Soreness > 0 {
+		soreness := mean(baseline, func(b CheckIn) float64 { return float64(6 - b.Soreness) })
+		scores = append(scores, ratingScore(6-c.Soreness, soreness))
+	}
+	if c.Stress > 0 {
+		stress := mean(baseline, func(b CheckIn) float64 { return float64(6 - b.Stress) })
+		scores = append(scores, ratingScore(6-c.Stress, stress))
+	}
+
+	if len(scores) == 0 {
+		return 0, false
+	}
+
+	var sum float64
+	for _, s := range scores {
+		sum += s
+	}
+	return int(math.Round(sum / float64(len(scores)))), true
+}
+
+// mean returns the mean of the known values of the baseline, or 0 when there are too few of them
+func mean(baseline []CheckIn, value func(CheckIn) float64) float64 {
+	var sum float64
+	var n int
+	for _, b := range baseline {
+		if v := value(b); v > 0 {
+			sum += v
+			n++
+		}
+	}
+	if n < MinBaseline {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// ratioScore scores a value relative to the baseline: 75 at the baseline, 100 when 10% better
+// and 0 when 30% worse
+func ratioScore(ratio float64) float64 {
+	return clamp(75 + (ratio-1)*250)
+}
+
+// ratingScore scores a rating from 1 (worst) to 5 (best) relative to the mean rating of the
+// baseline: 75 at the baseline and 25 points per rating of difference. Without a baseline 1 is
+// 0 and 5 is 100.
+func ratingScore(rating int, baseline float64) float64 {
+	if baseline == 0 {
+		return clamp(float64(rating-1) * 25)
+	}
+	return clamp(75 + (float64(rating)-baseline)*25)
+}
+
+func clamp(score float64) float64 {
+	return math.Max(0, math.Min(100, score))
+}
@@ -0,0 +1,48 @@
+package wellness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness(t *testing.T) {
+	baseline := []CheckIn{
+		{RestingHR: 48, HRV: 58},
+		{RestingHR: 50, HRV: 60, SleepHours: 7},
+		{RestingHR: 52, HRV: 62},
+		{SleepQuality: 3},
+	}
+	subjective := []CheckIn{
+		{SleepHours: 6, SleepQuality: 3, Soreness: 2, Stress: 2},
+		{SleepHours: 7, SleepQuality: 3, Soreness: 3, Stress: 2},
+		{SleepHours: 8, SleepQuality: 3, Soreness: 4, Stress: 2},
+	}
+
+	testCases := []struct {
+		name     string
+		checkIn  CheckIn
+		baseline []CheckIn
+		valid    bool
+		want     int
+	}{
+		{"at the baseline", CheckIn{RestingHR: 50, HRV: 60}, baseline, true, 75},
+		{"better hrv and higher heart rate", CheckIn{RestingHR: 55, HRV: 66}, baseline, true, 76},
+		{"much worse", CheckIn{RestingHR: 75, HRV: 40}, baseline, true, 0},
+		{"subjective", CheckIn{SleepHours: 8, SleepQuality: 4, Soreness: 2, Stress: 1}, nil, true, 88},
+		{"short sleep", CheckIn{SleepHours: 4}, nil, true, 50},
+		{"usual subjective", CheckIn{SleepHours: 7, SleepQuality: 3, Soreness: 3, Stress: 2}, subjective, true, 75},
+		{"better than usual", CheckIn{SleepHours: 7.7, SleepQuality: 4, Soreness: 2, Stress: 2}, subjective, true, 94},
+		{"worse than usual", CheckIn{SleepHours: 3.5, Soreness: 5}, subjective, true, 13},
+		{"short baseline", CheckIn{RestingHR: 50, HRV: 60}, baseline[:2], false, 0},
+		{"empty", CheckIn{}, baseline, false, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score, ok := Readiness(tc.checkIn, tc.baseline)
+			require.Equal(t, tc.valid, ok)
+			require.Equal(t, tc.want, score)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Package zones computes the training zones of an athlete from a threshold value and resolves
+// zone references such as "Z2" or "Z3-Z4" into numeric targets.
+//
+// Heart rates are in beats per minute, running paces in seconds per km, swim paces in seconds
+// per 100m and power in watts. For paces a lower value is faster, so the low bound of a zone
+// is its fastest pace.
+package zones
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Method is the way the zones are derived from the threshold
+type Method string
+
+// Supported methods
+const (
+	// HRMax uses percentages of the maximum heart rate
+	HRMax Method = "hr_max"
+	// HRLTHR uses percentages of the lactate threshold heart rate
+	HRLTHR Method = "hr_lthr"
+	// HRKarvonen uses percentages of the heart rate reserve, between resting and maximum
+	HRKarvonen Method = "hr_karvonen"
+	// Pace uses percentages of the running threshold pace
+	Pace Method = "pace"
+	// Power uses percentages of the cycling functional threshold power
+	Power Method = "power"
+	// CSS uses percentages of the swim critical swim speed pace
+	CSS Method = "css"
+)
+
+// Units of the zone bounds
+const (
+	UnitBPM            = "bpm"
+	UnitSecondsPerKm   = "s/km"
+	UnitSecondsPer100m = "s/100m"
+	UnitWatts          = "W"
+)
+
+// Errors returned when computing or resolving zones
+var (
+	ErrInvalidModel = errors.New("invalid zone model")
+	ErrInvalidRef   = errors.New("invalid zone reference")
+)
+
+// Model holds the values the zones of a method are computed from. Threshold is the maximum
+// heart rate for HRMax and HRKarvonen, the LTHR, the threshold pace, the FTP or the CSS pace.
+// Resting is the resting heart rate and is only used by HRKarvonen.
+type Model struct {
+	Method    Method
+	Threshold int
+	Resting   int
+}
+
+// Zone is a named range of values. A High of 0 means the zone has no upper bound.
+type Zone struct {
+	Name string  `json:"name"`
+	Low  float64 `json:"low"`
+	High float64 `json:"high,omitempty"`
+	Unit string  `json:"unit"`
+}
+
+type band struct {
+	low, high float64
+}
+
+// bands are the fractions of the threshold delimiting each zone, starting with Z1
+var bands = map[Method][]band{
+	HRMax:      {{0.50, 0.60}, {0.60, 0.70}, {0.70, 0.80}, {0.80, 0.90}, {0.90, 1.00}},
+	HRLTHR:     {{0.65, 0.85}, {0.85, 0.90}, {0.90, 0.95}, {0.95, 1.00}, {1.00, 1.06}},
+	HRKarvonen: {{0.50, 0.60}, {0.60, 0.70}, {0.70, 0.80}, {0.80, 0.90}, {0.90, 1.00}},
+	Pace:       {{1.29, 1.40}, {1.14, 1.29}, {1.06, 1.14}, {0.99, 1.06}, {0.90, 0.99}},
+	Power:      {{0, 0.55}, {0.55, 0.75}, {0.75, 0.90}, {0.90, 1.05}, {1.05, 1.20}, {1.20, 1.50}, {1.50, 0}},
+	CSS:        {{1.15, 1.25}, {1.08, 1.15}, {1.03, 1.08}, {0.98, 1.03}, {0.90, 0.98}},
+}
+
+var units = map[Method]string{
+	HRMax:      UnitBPM,
+	HRLTHR:     UnitBPM,
+	HRKarvonen: UnitBPM,
+	Pace:       UnitSecondsPerKm,
+	Power:      UnitWatts,
+	CSS:        UnitSecondsPer100m,
+}
+
+// Validate checks that the model has a known method and sensible values
+func (m Model) Validate() error {
+	if _, ok := bands[m.Method]; !ok {
+		return fmt.Errorf("%w: unknown method %q", ErrInvalidModel, m.Method)
+	}
+	if m.Threshold <= 0 {
+		return fmt.Errorf("%w: threshold must be positive", ErrInvalidModel)
+	}
+	if m.Method == HRKarvonen && (m.Resting <= 0 || m.Resting >= m.Threshold) {
+		return fmt.Errorf("%w: resting heart rate must be positive and below the maximum", ErrInvalidModel)
+	}
+	return nil
+}
+
+// Zones returns all the zones of the model, starting with Z1
+func (m Model) Zones() ([]Zone, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	zones := make([]Zone, len(bands[m.Method]))
+	for i, b := range bands[m.Method] {
+		zones[i] = Zone{
+			Name: "Z" + strconv.Itoa(i+1),
+			Low:  m.value(b.low),
+			Unit: units[m.Method],
+		}
+		if b.high > 0 {
+			zones[i].High = m.value(b.high)
+		}
+	}
+
+	return zones, nil
+}
+
+func (m Model) value(fraction float64) float64 {
+	if m.Method == HRKarvonen {
+		return math.Round(float64(m.Resting) + fraction*float64(m.Threshold-m.Resting))
+	}
+	return math.Round(fraction * float64(m.Threshold))
+}
+
+var refPattern = regexp.MustCompile(`^Z(\d+)(?:\s*-\s*Z?(\d+))?$`)
+
+// ParseRef parses a zone reference like "Z2" or "Z3-Z4" and returns the first and last zone
+// numbers it spans
+func ParseRef(ref string) (int, int, error) {
+	parts := refPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(ref)))
+	if parts == nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidRef, ref)
+	}
+
+	from, _ := strconv.Atoi(parts[1])
+	to := from
+	if parts[2] != "" {
+		to, _ = strconv.Atoi(parts[2])
+	}
+	if from < 1 || to < from {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidRef, ref)
+	}
+
+	return from, to, nil
+}
+
+// Resolve turns a zone reference into the numeric range it spans for the model
+func (m Model) Resolve(ref string) (Zone, error) {
+	from, to, err := ParseRef(ref)
+	if err != nil {
+		return Zone{}, err
+	}
+
+	zones, err := m.Zones()
+	if err != nil {
+		return Zone{}, err
+	}
+	if to > len(zones) {
+		return Zone{}, fmt.Errorf("%w: %q, the %s method has %d zones", ErrInvalidRef, ref, m.Method, len(zones))
+	}
+
+	first, last := zones[from-1], zones[to-1]
+	zone := Zone{
+		Name: first.Name,
+		Low:  first.Low,
+		High: last.High,
+		Unit: first.Unit,
+	}
+	if from != to {
+		zone.Name += "-" + last.Name
+	}
+
+	// Paces go from slow to fast, so the range is bounded by the last zone low end
+	if isPace(m.Method) {
+		zone.Low, zone.High = last.Low, first.High
+	}
+
+	return zone, nil
+}
+
+func isPace(method Method) bool {
+	return method == Pace || method == CSS
+}
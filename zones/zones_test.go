@@ -0,0 +1,72 @@
+package zones
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZones(t *testing.T) {
+	testCases := []struct {
+		name  string
+		model Model
+		zone  int
+		want  Zone
+	}{
+		{"hr max", Model{Method: HRMax, Threshold: 190}, 2, Zone{"Z2", 114, 133, UnitBPM}},
+		{"lthr", Model{Method: HRLTHR, Threshold: 170}, 4, Zone{"Z4", 162, 170, UnitBPM}},
+		{"karvonen", Model{Method: HRKarvonen, Threshold: 190, Resting: 50}, 2, Zone{"Z2", 134, 148, UnitBPM}},
+		{"pace", Model{Method: Pace, Threshold: 300}, 2, Zone{"Z2", 342, 387, UnitSecondsPerKm}},
+		{"power", Model{Method: Power, Threshold: 250}, 4, Zone{"Z4", 225, 263, UnitWatts}},
+		{"power open ended", Model{Method: Power, Threshold: 250}, 7, Zone{"Z7", 375, 0, UnitWatts}},
+		{"css", Model{Method: CSS, Threshold: 100}, 4, Zone{"Z4", 98, 103, UnitSecondsPer100m}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zones, err := tc.model.Zones()
+			require.NoError(t, err)
+			require.Equal(t, tc.want, zones[tc.zone-1])
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Model{Method: HRMax, Threshold: 190}.Validate())
+	require.ErrorIs(t, Model{Method: "speed", Threshold: 10}.Validate(), ErrInvalidModel)
+	require.ErrorIs(t, Model{Method: Power}.Validate(), ErrInvalidModel)
+	require.ErrorIs(t, Model{Method: HRKarvonen, Threshold: 190}.Validate(), ErrInvalidModel)
+	require.ErrorIs(t, Model{Method: HRKarvonen, Threshold: 190, Resting: 200}.Validate(), ErrInvalidModel)
+}
+
+func TestResolve(t *testing.T) {
+	testCases := []struct {
+		name  string
+		model Model
+		ref   string
+		valid bool
+		want  Zone
+	}{
+		{"single", Model{Method: HRMax, Threshold: 200}, "Z3", true, Zone{"Z3", 140, 160, UnitBPM}},
+		{"lower case", Model{Method: HRMax, Threshold: 200}, " z3 ", true, Zone{"Z3", 140, 160, UnitBPM}},
+		{"range", Model{Method: HRMax, Threshold: 200}, "Z2-Z3", true, Zone{"Z2-Z3", 120, 160, UnitBPM}},
+		{"short range", Model{Method: HRMax, Threshold: 200}, "Z2-3", true, Zone{"Z2-Z3", 120, 160, UnitBPM}},
+		{"pace range", Model{Method: Pace, Threshold: 300}, "Z2-Z3", true, Zone{"Z2-Z3", 318, 387, UnitSecondsPerKm}},
+		{"out of range", Model{Method: HRMax, Threshold: 200}, "Z6", false, Zone{}},
+		{"reversed", Model{Method: HRMax, Threshold: 200}, "Z3-Z2", false, Zone{}},
+		{"zero", Model{Method: HRMax, Threshold: 200}, "Z0", false, Zone{}},
+		{"free text", Model{Method: HRMax, Threshold: 200}, "easy", false, Zone{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zone, err := tc.model.Resolve(tc.ref)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, zone)
+		})
+	}
+}